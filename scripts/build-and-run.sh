@@ -30,7 +30,15 @@ custom_params_json='{
     "chainlinkContractDeployerImage": "kurtosistech/chainlink-contract-deployer:latest",
     "chainlinkOracleImage": "smartcontract/chainlink:0.10.2",
     "postgresImage": "postgres:13.2",
+    "chainlinkOracleUpgradeFromImage": "",
     "priceFeedServerImage": "kurtosistech/chainlink-price-feed-server:latest",
+    "gethServiceImageMatrix": [],
+    "chainlinkOracleImageMatrix": [],
+    "numGethNodes": 3,
+    "numSigners": 1,
+    "numOracles": 1,
+    "testsToRun": [],
+    "testOverrides": {},
     "isKurtosisCoreDevMode": false
 }'
 # >>>>>>>> Add custom testsuite parameters here <<<<<<<<<<<<<
@@ -0,0 +1,147 @@
+package benchmarking
+
+import (
+	"encoding/json"
+	"github.com/palantir/stacktrace"
+	"io/ioutil"
+	"math"
+	"math/big"
+	"sort"
+	"time"
+)
+
+const (
+	reportFilePerms = 0644
+)
+
+/*
+	What happened to a single oracle request fired during a benchmark.
+ */
+type FulfillmentSample struct {
+	RequestTxHash string    `json:"requestTxHash"`
+	RequestBlock  uint64    `json:"requestBlock"`
+	RequestTime   time.Time `json:"requestTime"`
+
+	Fulfilled bool `json:"fulfilled"`
+	// Why the request wasn't fulfilled; empty if it was
+	FailureReason     string    `json:"failureReason,omitempty"`
+	FulfillmentTxHash string    `json:"fulfillmentTxHash,omitempty"`
+	FulfillmentBlock  uint64    `json:"fulfillmentBlock,omitempty"`
+	FulfillmentTime   time.Time `json:"fulfillmentTime,omitempty"`
+
+	// $LINK paid for the request, in juels
+	LinkPaidJuels          string `json:"linkPaidJuels"`
+	FulfillmentGasUsed     uint64 `json:"fulfillmentGasUsed,omitempty"`
+	FulfillmentGasPriceWei string `json:"fulfillmentGasPriceWei,omitempty"`
+}
+
+type LatencyPercentiles struct {
+	P50 float64 `json:"p50"`
+	P95 float64 `json:"p95"`
+	P99 float64 `json:"p99"`
+}
+
+type FulfillmentBenchmarkReport struct {
+	ChainlinkOracleImage string `json:"chainlinkOracleImage"`
+	NumRequests          int    `json:"numRequests"`
+	NumFulfilled         int    `json:"numFulfilled"`
+	NumFailed            int    `json:"numFailed"`
+
+	// Seconds between the block containing the request and the block containing its fulfillment
+	LatencySeconds LatencyPercentiles `json:"latencySeconds"`
+	// Blocks between the request and its fulfillment
+	LatencyBlocks LatencyPercentiles `json:"latencyBlocks"`
+
+	AverageLinkJuelsPerFulfillment  string `json:"averageLinkJuelsPerFulfillment"`
+	AverageGasPerFulfillment        uint64 `json:"averageGasPerFulfillment"`
+	AverageGasCostWeiPerFulfillment string `json:"averageGasCostWeiPerFulfillment"`
+
+	Samples []FulfillmentSample `json:"samples"`
+}
+
+func NewFulfillmentBenchmarkReport(chainlinkOracleImage string, samples []FulfillmentSample) (*FulfillmentBenchmarkReport, error) {
+	latencySeconds := []float64{}
+	latencyBlocks := []float64{}
+	totalLinkJuels := big.NewInt(0)
+	totalGasCostWei := big.NewInt(0)
+	totalGas := uint64(0)
+	numFulfilled := 0
+	for _, sample := range samples {
+		if !sample.Fulfilled {
+			continue
+		}
+		numFulfilled++
+		latencySeconds = append(latencySeconds, sample.FulfillmentTime.Sub(sample.RequestTime).Seconds())
+		latencyBlocks = append(latencyBlocks, float64(sample.FulfillmentBlock-sample.RequestBlock))
+
+		linkPaid, ok := new(big.Int).SetString(sample.LinkPaidJuels, 10)
+		if !ok {
+			return nil, stacktrace.NewError("Couldn't parse $LINK payment '%v' of request %v", sample.LinkPaidJuels, sample.RequestTxHash)
+		}
+		totalLinkJuels.Add(totalLinkJuels, linkPaid)
+
+		gasPrice, ok := new(big.Int).SetString(sample.FulfillmentGasPriceWei, 10)
+		if !ok {
+			return nil, stacktrace.NewError("Couldn't parse gas price '%v' of fulfillment %v", sample.FulfillmentGasPriceWei, sample.FulfillmentTxHash)
+		}
+		gasCost := new(big.Int).Mul(gasPrice, new(big.Int).SetUint64(sample.FulfillmentGasUsed))
+		totalGasCostWei.Add(totalGasCostWei, gasCost)
+		totalGas += sample.FulfillmentGasUsed
+	}
+
+	report := &FulfillmentBenchmarkReport{
+		ChainlinkOracleImage:            chainlinkOracleImage,
+		NumRequests:                     len(samples),
+		NumFulfilled:                    numFulfilled,
+		NumFailed:                       len(samples) - numFulfilled,
+		LatencySeconds:                  computePercentiles(latencySeconds),
+		LatencyBlocks:                   computePercentiles(latencyBlocks),
+		AverageLinkJuelsPerFulfillment:  "0",
+		AverageGasCostWeiPerFulfillment: "0",
+		Samples:                         samples,
+	}
+	if numFulfilled > 0 {
+		numFulfilledBig := big.NewInt(int64(numFulfilled))
+		report.AverageLinkJuelsPerFulfillment = new(big.Int).Div(totalLinkJuels, numFulfilledBig).String()
+		report.AverageGasCostWeiPerFulfillment = new(big.Int).Div(totalGasCostWei, numFulfilledBig).String()
+		report.AverageGasPerFulfillment = totalGas / uint64(numFulfilled)
+	}
+	return report, nil
+}
+
+func (report FulfillmentBenchmarkReport) WriteJson(filepath string) error {
+	reportBytes, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return stacktrace.Propagate(err, "An error occurred serializing the fulfillment benchmark report")
+	}
+	if err := ioutil.WriteFile(filepath, reportBytes, reportFilePerms); err != nil {
+		return stacktrace.Propagate(err, "An error occurred writing the fulfillment benchmark report to '%v'", filepath)
+	}
+	return nil
+}
+
+// ==========================================================================================
+//								Helper methods
+// ==========================================================================================
+
+func computePercentiles(values []float64) LatencyPercentiles {
+	if len(values) == 0 {
+		return LatencyPercentiles{}
+	}
+	sorted := append([]float64{}, values...)
+	sort.Float64s(sorted)
+	return LatencyPercentiles{
+		P50: nearestRankPercentile(sorted, 50),
+		P95: nearestRankPercentile(sorted, 95),
+		P99: nearestRankPercentile(sorted, 99),
+	}
+}
+
+// See: https://en.wikipedia.org/wiki/Percentile#The_nearest-rank_method
+func nearestRankPercentile(sortedValues []float64, percentile float64) float64 {
+	rank := int(math.Ceil(percentile / 100 * float64(len(sortedValues))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sortedValues[rank-1]
+}
@@ -0,0 +1,165 @@
+package benchmarking
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestComputePercentiles(t *testing.T) {
+	testCases := []struct {
+		name string
+		values []float64
+		expectedPercentiles LatencyPercentiles
+	}{
+		{
+			name:                "empty",
+			values:              []float64{},
+			expectedPercentiles: LatencyPercentiles{},
+		},
+		{
+			name:                "single sample",
+			values:              []float64{7},
+			expectedPercentiles: LatencyPercentiles{P50: 7, P95: 7, P99: 7},
+		},
+		{
+			name:                "unsorted",
+			values:              []float64{3, 1, 2},
+			expectedPercentiles: LatencyPercentiles{P50: 2, P95: 3, P99: 3},
+		},
+		{
+			// The ranks land exactly on 50, 95 and 99, so being off by one either way shows
+			name:                "100 samples",
+			values:              getRange(1, 100),
+			expectedPercentiles: LatencyPercentiles{P50: 50, P95: 95, P99: 99},
+		},
+		{
+			// 95% and 99% of 20 are ranks 19 and 19.8, the latter rounding up to the last sample
+			name:                "20 samples",
+			values:              getRange(1, 20),
+			expectedPercentiles: LatencyPercentiles{P50: 10, P95: 19, P99: 20},
+		},
+		{
+			// 50% of 7 is rank 3.5, which rounds up
+			name:                "odd number of samples",
+			values:              getRange(1, 7),
+			expectedPercentiles: LatencyPercentiles{P50: 4, P95: 7, P99: 7},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			valuesBefore := append([]float64{}, testCase.values...)
+			percentiles := computePercentiles(testCase.values)
+			if percentiles != testCase.expectedPercentiles {
+				t.Fatalf("Expected percentiles %+v, but got %+v", testCase.expectedPercentiles, percentiles)
+			}
+			if !reflect.DeepEqual(testCase.values, valuesBefore) {
+				t.Fatalf("Expected the values to be left as they were, %v, but they're now %v", valuesBefore, testCase.values)
+			}
+		})
+	}
+}
+
+func TestNewFulfillmentBenchmarkReportAverages(t *testing.T) {
+	requestTime := time.Now()
+	newFulfilledSample := func(linkPaidJuels string, gasUsed uint64, gasPriceWei string) FulfillmentSample {
+		return FulfillmentSample{
+			RequestBlock:           10,
+			RequestTime:            requestTime,
+			Fulfilled:              true,
+			FulfillmentBlock:       12,
+			FulfillmentTime:        requestTime.Add(4 * time.Second),
+			LinkPaidJuels:          linkPaidJuels,
+			FulfillmentGasUsed:     gasUsed,
+			FulfillmentGasPriceWei: gasPriceWei,
+		}
+	}
+	unfulfilledSample := FulfillmentSample{
+		RequestBlock:  10,
+		RequestTime:   requestTime,
+		FailureReason: "timed out",
+		LinkPaidJuels: "1000000000000000000",
+	}
+
+	testCases := []struct {
+		name string
+		samples []FulfillmentSample
+		expectedNumFailed int
+		expectedAverageLinkJuels string
+		expectedAverageGas uint64
+		expectedAverageGasCostWei string
+	}{
+		{
+			name:                      "no samples",
+			samples:                   []FulfillmentSample{},
+			expectedAverageLinkJuels:  "0",
+			expectedAverageGasCostWei: "0",
+		},
+		{
+			name:                      "only unfulfilled samples",
+			samples:                   []FulfillmentSample{unfulfilledSample},
+			expectedNumFailed:         1,
+			expectedAverageLinkJuels:  "0",
+			expectedAverageGasCostWei: "0",
+		},
+		{
+			name:                      "single sample",
+			samples:                   []FulfillmentSample{newFulfilledSample("1000000000000000000", 100000, "20")},
+			expectedAverageLinkJuels:  "1000000000000000000",
+			expectedAverageGas:        100000,
+			expectedAverageGasCostWei: "2000000",
+		},
+		{
+			// Unfulfilled samples don't count towards the averages, and the averages round down
+			name: "fulfilled and unfulfilled samples",
+			samples: []FulfillmentSample{
+				newFulfilledSample("1", 100, "1"),
+				unfulfilledSample,
+				newFulfilledSample("2", 101, "2"),
+			},
+			expectedNumFailed:         1,
+			expectedAverageLinkJuels:  "1",
+			expectedAverageGas:        100,
+			expectedAverageGasCostWei: "151",
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			report, err := NewFulfillmentBenchmarkReport("oracle", testCase.samples)
+			if err != nil {
+				t.Fatalf("Building the report failed: %v", err)
+			}
+			if report.NumRequests != len(testCase.samples) || report.NumFailed != testCase.expectedNumFailed {
+				t.Errorf("Expected %v requests with %v failed, but the report has %v with %v failed",
+					len(testCase.samples), testCase.expectedNumFailed, report.NumRequests, report.NumFailed)
+			}
+			if report.AverageLinkJuelsPerFulfillment != testCase.expectedAverageLinkJuels {
+				t.Errorf("Expected an average of %v juels per fulfillment, but got %v", testCase.expectedAverageLinkJuels, report.AverageLinkJuelsPerFulfillment)
+			}
+			if report.AverageGasPerFulfillment != testCase.expectedAverageGas {
+				t.Errorf("Expected an average of %v gas per fulfillment, but got %v", testCase.expectedAverageGas, report.AverageGasPerFulfillment)
+			}
+			if report.AverageGasCostWeiPerFulfillment != testCase.expectedAverageGasCostWei {
+				t.Errorf("Expected an average gas cost of %v wei per fulfillment, but got %v", testCase.expectedAverageGasCostWei, report.AverageGasCostWeiPerFulfillment)
+			}
+		})
+	}
+}
+
+func TestNewFulfillmentBenchmarkReportRejectsUnparseableAmounts(t *testing.T) {
+	samples := []FulfillmentSample{
+		{Fulfilled: true, LinkPaidJuels: "one $LINK", FulfillmentGasPriceWei: "1"},
+	}
+	if _, err := NewFulfillmentBenchmarkReport("oracle", samples); err == nil {
+		t.Fatal("Expected a sample with an unparseable $LINK payment to be rejected, but it wasn't")
+	}
+}
+
+// The whole numbers from first to last, inclusive
+func getRange(first int, last int) []float64 {
+	values := []float64{}
+	for value := first; value <= last; value++ {
+		values = append(values, float64(value))
+	}
+	return values
+}
@@ -0,0 +1,165 @@
+package dependency_graph
+
+import (
+	"fmt"
+	"github.com/palantir/stacktrace"
+	"strings"
+	"sync"
+)
+
+/*
+	A set of tasks with dependencies between them, run with every task starting as soon as all of its dependencies
+	have finished. Tasks that don't depend on each other run concurrently.
+ */
+type DependencyGraph struct {
+	tasks map[string]*task
+	// Order tasks were added in, so errors get reported in a stable order
+	taskIds []string
+}
+
+type task struct {
+	dependencyIds []string
+	run           func() error
+}
+
+func NewDependencyGraph() *DependencyGraph {
+	return &DependencyGraph{
+		tasks:   map[string]*task{},
+		taskIds: []string{},
+	}
+}
+
+/*
+	Adds a task that runs after the tasks with the given IDs have succeeded. Dependencies can be added after the
+	tasks that depend on them.
+ */
+func (graph *DependencyGraph) AddTask(taskId string, dependencyIds []string, run func() error) error {
+	if _, found := graph.tasks[taskId]; found {
+		return stacktrace.NewError("A task with ID '%v' has already been added", taskId)
+	}
+	graph.tasks[taskId] = &task{
+		dependencyIds: dependencyIds,
+		run:           run,
+	}
+	graph.taskIds = append(graph.taskIds, taskId)
+	return nil
+}
+
+/*
+	Runs every task, waiting for all of them to finish. Tasks whose dependencies failed are skipped rather than run,
+	and the returned error lists every task that failed or was skipped.
+ */
+func (graph *DependencyGraph) Run() error {
+	if err := graph.validate(); err != nil {
+		return stacktrace.Propagate(err, "The dependency graph is invalid")
+	}
+
+	// Each task closes its channel when it's done, whether it succeeded, failed or was skipped
+	doneChans := map[string]chan struct{}{}
+	for _, taskId := range graph.taskIds {
+		doneChans[taskId] = make(chan struct{})
+	}
+	mutex := &sync.Mutex{}
+	taskErrs := map[string]error{}
+	skippedTaskIds := map[string]bool{}
+
+	waitGroup := &sync.WaitGroup{}
+	for _, taskId := range graph.taskIds {
+		waitGroup.Add(1)
+		go func(taskId string) {
+			defer waitGroup.Done()
+			defer close(doneChans[taskId])
+
+			task := graph.tasks[taskId]
+			failedDependencyIds := []string{}
+			for _, dependencyId := range task.dependencyIds {
+				<-doneChans[dependencyId]
+				mutex.Lock()
+				_, dependencyFailed := taskErrs[dependencyId]
+				dependencyFailed = dependencyFailed || skippedTaskIds[dependencyId]
+				mutex.Unlock()
+				if dependencyFailed {
+					failedDependencyIds = append(failedDependencyIds, dependencyId)
+				}
+			}
+			if len(failedDependencyIds) > 0 {
+				mutex.Lock()
+				skippedTaskIds[taskId] = true
+				mutex.Unlock()
+				return
+			}
+
+			if err := task.run(); err != nil {
+				mutex.Lock()
+				taskErrs[taskId] = err
+				mutex.Unlock()
+			}
+		}(taskId)
+	}
+	waitGroup.Wait()
+
+	if len(taskErrs) == 0 {
+		return nil
+	}
+	errStrs := []string{}
+	for _, taskId := range graph.taskIds {
+		if err, found := taskErrs[taskId]; found {
+			errStrs = append(errStrs, fmt.Sprintf("Task '%v' failed:\n%v", taskId, err.Error()))
+		}
+	}
+	skippedIds := []string{}
+	for _, taskId := range graph.taskIds {
+		if skippedTaskIds[taskId] {
+			skippedIds = append(skippedIds, taskId)
+		}
+	}
+	if len(skippedIds) > 0 {
+		errStrs = append(errStrs, fmt.Sprintf("Skipped tasks %v because their dependencies failed", skippedIds))
+	}
+	return stacktrace.NewError("%v of %v tasks failed:\n\n%v", len(taskErrs), len(graph.taskIds), strings.Join(errStrs, "\n\n"))
+}
+
+// ==========================================================================================
+//								Helper methods
+// ==========================================================================================
+
+/*
+	Checks that every dependency exists and that there are no cycles, either of which would deadlock Run.
+ */
+func (graph *DependencyGraph) validate() error {
+	for _, taskId := range graph.taskIds {
+		for _, dependencyId := range graph.tasks[taskId].dependencyIds {
+			if _, found := graph.tasks[dependencyId]; !found {
+				return stacktrace.NewError("Task '%v' depends on task '%v', which doesn't exist", taskId, dependencyId)
+			}
+		}
+	}
+
+	isResolved := map[string]bool{}
+	for len(isResolved) < len(graph.taskIds) {
+		resolvedThisPass := false
+		for _, taskId := range graph.taskIds {
+			if isResolved[taskId] {
+				continue
+			}
+			allDependenciesResolved := true
+			for _, dependencyId := range graph.tasks[taskId].dependencyIds {
+				allDependenciesResolved = allDependenciesResolved && isResolved[dependencyId]
+			}
+			if allDependenciesResolved {
+				isResolved[taskId] = true
+				resolvedThisPass = true
+			}
+		}
+		if !resolvedThisPass {
+			cycleTaskIds := []string{}
+			for _, taskId := range graph.taskIds {
+				if !isResolved[taskId] {
+					cycleTaskIds = append(cycleTaskIds, taskId)
+				}
+			}
+			return stacktrace.NewError("Tasks %v have a dependency cycle", cycleTaskIds)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,301 @@
+package dependency_graph
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+const (
+	// Long enough for tasks that can run at the same time to overlap, even on a loaded machine
+	overlapDuration = 100 * time.Millisecond
+)
+
+type testTask struct {
+	id string
+	dependencyIds []string
+	isFailing bool
+}
+
+/*
+	Records which tasks ran, in the order they finished, and how many were running at once.
+ */
+type taskRecorder struct {
+	mutex *sync.Mutex
+	finishedTaskIds []string
+	numRunning int
+	maxNumRunning int
+}
+
+func newTaskRecorder() *taskRecorder {
+	return &taskRecorder{
+		mutex:           &sync.Mutex{},
+		finishedTaskIds: []string{},
+	}
+}
+
+func (recorder *taskRecorder) newTaskFunc(task testTask) func() error {
+	return func() error {
+		recorder.mutex.Lock()
+		recorder.numRunning++
+		if recorder.numRunning > recorder.maxNumRunning {
+			recorder.maxNumRunning = recorder.numRunning
+		}
+		recorder.mutex.Unlock()
+
+		time.Sleep(overlapDuration)
+
+		recorder.mutex.Lock()
+		defer recorder.mutex.Unlock()
+		recorder.numRunning--
+		recorder.finishedTaskIds = append(recorder.finishedTaskIds, task.id)
+		if task.isFailing {
+			return errors.New("task " + task.id + " failed")
+		}
+		return nil
+	}
+}
+
+func runTestGraph(t *testing.T, tasks []testTask) (*taskRecorder, error) {
+	recorder := newTaskRecorder()
+	graph := NewDependencyGraph()
+	for _, task := range tasks {
+		if err := graph.AddTask(task.id, task.dependencyIds, recorder.newTaskFunc(task)); err != nil {
+			t.Fatalf("Adding task '%v' failed: %v", task.id, err)
+		}
+	}
+	return recorder, graph.Run()
+}
+
+func TestRunOrdersTasksAfterDependencies(t *testing.T) {
+	testCases := []struct {
+		name string
+		tasks []testTask
+	}{
+		{
+			name: "chain",
+			tasks: []testTask{
+				{id: "a"},
+				{id: "b", dependencyIds: []string{"a"}},
+				{id: "c", dependencyIds: []string{"b"}},
+			},
+		},
+		{
+			name: "dependencies added after their dependents",
+			tasks: []testTask{
+				{id: "oracle", dependencyIds: []string{"postgres", "contracts"}},
+				{id: "contracts", dependencyIds: []string{"bootstrapper"}},
+				{id: "postgres"},
+				{id: "bootstrapper"},
+			},
+		},
+		{
+			name: "diamond",
+			tasks: []testTask{
+				{id: "top"},
+				{id: "left", dependencyIds: []string{"top"}},
+				{id: "right", dependencyIds: []string{"top"}},
+				{id: "bottom", dependencyIds: []string{"left", "right"}},
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			recorder, err := runTestGraph(t, testCase.tasks)
+			if err != nil {
+				t.Fatalf("Expected the graph to run without errors, but got: %v", err)
+			}
+			if len(recorder.finishedTaskIds) != len(testCase.tasks) {
+				t.Fatalf("Expected all %v tasks to run, but only %v did", len(testCase.tasks), recorder.finishedTaskIds)
+			}
+			finishIdxs := map[string]int{}
+			for idx, taskId := range recorder.finishedTaskIds {
+				finishIdxs[taskId] = idx
+			}
+			for _, task := range testCase.tasks {
+				for _, dependencyId := range task.dependencyIds {
+					if finishIdxs[dependencyId] > finishIdxs[task.id] {
+						t.Errorf("Expected task '%v' to finish after its dependency '%v', but the finish order was %v",
+							task.id, dependencyId, recorder.finishedTaskIds)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestRunParallelism(t *testing.T) {
+	testCases := []struct {
+		name string
+		tasks []testTask
+		expectedMaxNumRunning int
+	}{
+		{
+			name: "independent tasks all run at once",
+			tasks: []testTask{
+				{id: "a"},
+				{id: "b"},
+				{id: "c"},
+			},
+			expectedMaxNumRunning: 3,
+		},
+		{
+			name: "a chain runs one task at a time",
+			tasks: []testTask{
+				{id: "a"},
+				{id: "b", dependencyIds: []string{"a"}},
+				{id: "c", dependencyIds: []string{"b"}},
+			},
+			expectedMaxNumRunning: 1,
+		},
+		{
+			name: "geth nodes start together after the bootstrapper",
+			tasks: []testTask{
+				{id: "bootstrapper"},
+				{id: "geth1", dependencyIds: []string{"bootstrapper"}},
+				{id: "geth2", dependencyIds: []string{"bootstrapper"}},
+			},
+			expectedMaxNumRunning: 2,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			recorder, err := runTestGraph(t, testCase.tasks)
+			if err != nil {
+				t.Fatalf("Expected the graph to run without errors, but got: %v", err)
+			}
+			if recorder.maxNumRunning != testCase.expectedMaxNumRunning {
+				t.Fatalf("Expected at most %v tasks to run at once, but %v did", testCase.expectedMaxNumRunning, recorder.maxNumRunning)
+			}
+		})
+	}
+}
+
+func TestRunSkipsDependentsOfFailedTasks(t *testing.T) {
+	testCases := []struct {
+		name string
+		tasks []testTask
+		expectedRunTaskIds []string
+		// Expected in the error
+		expectedErrorFragments []string
+	}{
+		{
+			name: "dependents of a failed task are skipped, transitively",
+			tasks: []testTask{
+				{id: "a", isFailing: true},
+				{id: "b", dependencyIds: []string{"a"}},
+				{id: "c", dependencyIds: []string{"b"}},
+				{id: "independent"},
+			},
+			expectedRunTaskIds:     []string{"a", "independent"},
+			expectedErrorFragments: []string{"1 of 4 tasks failed", "Task 'a' failed", "Skipped tasks [b c]"},
+		},
+		{
+			name: "a task is skipped if any one of its dependencies fails",
+			tasks: []testTask{
+				{id: "postgres"},
+				{id: "contracts", isFailing: true},
+				{id: "oracle", dependencyIds: []string{"postgres", "contracts"}},
+			},
+			expectedRunTaskIds:     []string{"postgres", "contracts"},
+			expectedErrorFragments: []string{"1 of 3 tasks failed", "Task 'contracts' failed", "Skipped tasks [oracle]"},
+		},
+		{
+			name: "every failure is reported",
+			tasks: []testTask{
+				{id: "a", isFailing: true},
+				{id: "b", isFailing: true},
+			},
+			expectedRunTaskIds:     []string{"a", "b"},
+			expectedErrorFragments: []string{"2 of 2 tasks failed", "Task 'a' failed", "Task 'b' failed"},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			recorder, err := runTestGraph(t, testCase.tasks)
+			if err == nil {
+				t.Fatal("Expected the graph to return an error, but it didn't")
+			}
+			for _, fragment := range testCase.expectedErrorFragments {
+				if !strings.Contains(err.Error(), fragment) {
+					t.Errorf("Expected the error to contain '%v', but got: %v", fragment, err)
+				}
+			}
+			isRun := map[string]bool{}
+			for _, taskId := range recorder.finishedTaskIds {
+				isRun[taskId] = true
+			}
+			if len(isRun) != len(testCase.expectedRunTaskIds) {
+				t.Errorf("Expected tasks %v to run, but %v did", testCase.expectedRunTaskIds, recorder.finishedTaskIds)
+			}
+			for _, taskId := range testCase.expectedRunTaskIds {
+				if !isRun[taskId] {
+					t.Errorf("Expected tasks %v to run, but %v did", testCase.expectedRunTaskIds, recorder.finishedTaskIds)
+				}
+			}
+		})
+	}
+}
+
+func TestRunRejectsInvalidGraphs(t *testing.T) {
+	testCases := []struct {
+		name string
+		tasks []testTask
+		// Expected in the error
+		expectedErrorFragment string
+	}{
+		{
+			name: "unknown dependency",
+			tasks: []testTask{
+				{id: "a", dependencyIds: []string{"missing"}},
+			},
+			expectedErrorFragment: "depends on task 'missing', which doesn't exist",
+		},
+		{
+			name: "task depending on itself",
+			tasks: []testTask{
+				{id: "a", dependencyIds: []string{"a"}},
+			},
+			expectedErrorFragment: "Tasks [a] have a dependency cycle",
+		},
+		{
+			name: "cycle behind a valid task",
+			tasks: []testTask{
+				{id: "root"},
+				{id: "a", dependencyIds: []string{"root", "c"}},
+				{id: "b", dependencyIds: []string{"a"}},
+				{id: "c", dependencyIds: []string{"b"}},
+			},
+			expectedErrorFragment: "Tasks [a b c] have a dependency cycle",
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			recorder, err := runTestGraph(t, testCase.tasks)
+			if err == nil {
+				t.Fatal("Expected the graph to be rejected, but it ran")
+			}
+			if !strings.Contains(err.Error(), testCase.expectedErrorFragment) {
+				t.Errorf("Expected the error to contain '%v', but got: %v", testCase.expectedErrorFragment, err)
+			}
+			if len(recorder.finishedTaskIds) != 0 {
+				t.Errorf("Expected no task to run in an invalid graph, but %v did", recorder.finishedTaskIds)
+			}
+		})
+	}
+}
+
+func TestAddTaskRejectsDuplicateIds(t *testing.T) {
+	graph := NewDependencyGraph()
+	noOp := func() error {
+		return nil
+	}
+	if err := graph.AddTask("a", []string{}, noOp); err != nil {
+		t.Fatalf("Adding task 'a' failed: %v", err)
+	}
+	if err := graph.AddTask("a", []string{}, noOp); err == nil {
+		t.Fatal("Expected adding a second task with ID 'a' to fail, but it didn't")
+	}
+}
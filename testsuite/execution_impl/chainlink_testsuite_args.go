@@ -5,9 +5,43 @@ type ChainlinkTestsuiteArgs struct {
 	ChainlinkContractDeployerImage	string	`json:"chainlinkContractDeployerImage"`
 	ChainlinkOracleImage	string	`json:"chainlinkOracleImage"`
 	PostgresImage	string	`json:"postgresImage"`
+	// Oracle image the upgrade test starts on before upgrading to the oracle image(s) above; optional, and if empty
+	// the upgrade test restarts the Oracle on the image it's already on
+	ChainlinkOracleUpgradeFromImage	string	`json:"chainlinkOracleUpgradeFromImage"`
 	PriceFeedServerImage	string	`json:"priceFeedServerImage"`
 
+	// Every test is run once per combination of the geth and oracle images in these lists, with results reported per
+	// combination. A non-empty list replaces the single image above, which must then be left empty.
+	GethServiceImageMatrix	[]string	`json:"gethServiceImageMatrix"`
+	ChainlinkOracleImageMatrix	[]string	`json:"chainlinkOracleImageMatrix"`
+
+	// Network topology every test uses unless overridden for that test; zero values fall back to the defaults
+	NumGethNodes	int	`json:"numGethNodes"`
+	NumSigners	int	`json:"numSigners"`
+	NumOracles	int	`json:"numOracles"`
+	// Optional images for individual geth nodes and oracles, by index (0 is the bootstrapper/primary oracle); can't be
+	// set, here or in the test overrides, for a service that has an image matrix
+	GethNodeImages	[]string	`json:"gethNodeImages"`
+	ChainlinkOracleImages	[]string	`json:"chainlinkOracleImages"`
+
+	// Names of the tests to run; if empty, every test is run
+	TestsToRun	[]string	`json:"testsToRun"`
+
+	// Test name -> topology settings that replace the ones above for that test. Network spec tests take their
+	// topology from their spec file instead.
+	TestOverrides	map[string]TestOverrideArgs	`json:"testOverrides"`
+
 	// Indicates that this testsuite is being run as part of CI testing in Kurtosis Core
 	IsKurtosisCoreDevMode bool		`json:"isKurtosisCoreDevMode"`
 }
 
+/*
+	Per-test topology settings; zero values keep whatever the testsuite-wide args say.
+ */
+type TestOverrideArgs struct {
+	NumGethNodes	int	`json:"numGethNodes"`
+	NumSigners	int	`json:"numSigners"`
+	NumOracles	int	`json:"numOracles"`
+	GethNodeImages	[]string	`json:"gethNodeImages"`
+	ChainlinkOracleImages	[]string	`json:"chainlinkOracleImages"`
+}
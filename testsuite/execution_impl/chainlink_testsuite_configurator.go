@@ -1,14 +1,26 @@
 package execution_impl
 
 import (
+"bytes"
 "encoding/json"
 "github.com/kurtosis-tech/kurtosis-libs/golang/lib/testsuite"
+"github.com/kurtosistech/chainlink-testing/testsuite/network_spec"
+"github.com/kurtosistech/chainlink-testing/testsuite/networks_impl"
 "github.com/kurtosistech/chainlink-testing/testsuite/testsuite_impl"
+"github.com/kurtosistech/chainlink-testing/testsuite/testsuite_impl/network_spec_test"
 "github.com/palantir/stacktrace"
 "github.com/sirupsen/logrus"
 "strings"
 )
 
+// Where the testsuite Dockerfile puts the network spec files; every spec in here is run as a test
+const networkSpecsDirpath = "/run/topologies"
+
+// Kurtosis Core's CI only needs to see that the testsuite works, so it gets the quickest test rather than all of them
+var kurtosisCoreDevModeTests = []string{
+	"linkContractInitializationTest",
+}
+
 type ChainlinkTestsuiteConfigurator struct {}
 
 func NewChainlinkTestsuiteConfigurator() *ChainlinkTestsuiteConfigurator {
@@ -31,7 +43,10 @@ func (t ChainlinkTestsuiteConfigurator) SetLogLevel(logLevelStr string) error {
 func (t ChainlinkTestsuiteConfigurator) ParseParamsAndCreateSuite(paramsJsonStr string) (testsuite.TestSuite, error) {
 	paramsJsonBytes := []byte(paramsJsonStr)
 	var args ChainlinkTestsuiteArgs
-	if err := json.Unmarshal(paramsJsonBytes, &args); err != nil {
+	// A misspelled topology param would otherwise be silently ignored, leaving the test on the default topology
+	decoder := json.NewDecoder(bytes.NewReader(paramsJsonBytes))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&args); err != nil {
 		return nil, stacktrace.Propagate(err, "An error occurred deserializing the testsuite params JSON")
 	}
 
@@ -39,15 +54,202 @@ func (t ChainlinkTestsuiteConfigurator) ParseParamsAndCreateSuite(paramsJsonStr
 		return nil, stacktrace.Propagate(err, "An error occurred validating the deserialized testsuite params")
 	}
 
-	suite := testsuite_impl.NewChainlinkTestsuite(args.GethServiceImage, args.ChainlinkContractDeployerImage,
-		args.ChainlinkOracleImage, args.PostgresImage, args.PriceFeedServerImage)
+	versionMatrix, err := testsuite_impl.NewVersionMatrix(
+		getImageMatrix(args.GethServiceImage, args.GethServiceImageMatrix),
+		getImageMatrix(args.ChainlinkOracleImage, args.ChainlinkOracleImageMatrix))
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "An error occurred building the version matrix")
+	}
+	for _, combination := range versionMatrix {
+		logrus.Infof("Tests will run against geth image '%v' and oracle image '%v' (label '%v')",
+			combination.GethServiceImage, combination.ChainlinkOracleImage, combination.GetLabel())
+	}
+
+	defaultTopology := applyTopologyArgs(networks_impl.NewDefaultNetworkTopology(), args.NumGethNodes, args.NumSigners,
+		args.NumOracles, args.GethNodeImages, args.ChainlinkOracleImages)
+	if err := defaultTopology.Validate(); err != nil {
+		return nil, stacktrace.Propagate(err, "The testsuite-wide network topology is invalid")
+	}
+	topologyOverrides := map[string]networks_impl.NetworkTopology{}
+	for testName, overrideArgs := range args.TestOverrides {
+		topology := applyTopologyArgs(defaultTopology, overrideArgs.NumGethNodes, overrideArgs.NumSigners,
+			overrideArgs.NumOracles, overrideArgs.GethNodeImages, overrideArgs.ChainlinkOracleImages)
+		if err := topology.Validate(); err != nil {
+			return nil, stacktrace.Propagate(err, "The network topology override for test '%v' is invalid", testName)
+		}
+		topologyOverrides[testName] = topology
+	}
+
+	networkSpecs, err := network_spec.LoadNetworkSpecs(networkSpecsDirpath)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "An error occurred loading the network specs in '%v'", networkSpecsDirpath)
+	}
+
+	testsToRun := args.TestsToRun
+	if len(testsToRun) == 0 && args.IsKurtosisCoreDevMode {
+		testsToRun = kurtosisCoreDevModeTests
+	}
+
+	// Build the suite with every test first, so we can check the test names in the params against it; test names in
+	// the params don't include version labels, but a combination can leave out a test (e.g. the upgrade test, when the
+	// combination's Oracle image is the one to upgrade from), so the names come from every combination
+	allTestsSuite := testsuite_impl.NewChainlinkTestsuite(versionMatrix, args.ChainlinkContractDeployerImage,
+		args.ChainlinkOracleUpgradeFromImage, args.PostgresImage, args.PriceFeedServerImage, defaultTopology, topologyOverrides,
+		[]string{}, networkSpecs)
+	allTests := map[string]testsuite.Test{}
+	for _, combination := range versionMatrix {
+		for testName, test := range allTestsSuite.GetTestsForVersions(combination) {
+			allTests[testName] = test
+		}
+	}
+	for _, testName := range testsToRun {
+		if _, found := allTests[testName]; !found {
+			return nil, stacktrace.NewError("Test '%v' was requested to run, but the testsuite has no test with that name", testName)
+		}
+	}
+	for testName := range topologyOverrides {
+		if _, found := allTests[testName]; !found {
+			return nil, stacktrace.NewError("Got overrides for test '%v', but the testsuite has no test with that name", testName)
+		}
+		if _, isNetworkSpecTest := allTests[testName].(*network_spec_test.NetworkSpecTest); isNetworkSpecTest {
+			return nil, stacktrace.NewError("Got overrides for test '%v', but it takes its topology from its network spec file", testName)
+		}
+	}
+
+	suite := testsuite_impl.NewChainlinkTestsuite(versionMatrix, args.ChainlinkContractDeployerImage,
+		args.ChainlinkOracleUpgradeFromImage, args.PostgresImage, args.PriceFeedServerImage, defaultTopology, topologyOverrides,
+		testsToRun, networkSpecs)
 	return suite, nil
 }
 
 func validateChainlinkArgs(args ChainlinkTestsuiteArgs) error {
-	if strings.TrimSpace(args.GethServiceImage) == "" {
-		return stacktrace.NewError("Geth service image is empty")
+	if err := validateImageMatrixArgs("Geth service", args.GethServiceImage, args.GethServiceImageMatrix); err != nil {
+		return err
+	}
+	if strings.TrimSpace(args.ChainlinkContractDeployerImage) == "" {
+		return stacktrace.NewError("Chainlink contract deployer image is empty")
+	}
+	if err := validateImageMatrixArgs("Chainlink oracle", args.ChainlinkOracleImage, args.ChainlinkOracleImageMatrix); err != nil {
+		return err
+	}
+	if err := validateNoPerNodeImagesWithMatrix("Geth service", args.GethServiceImageMatrix, args.GethNodeImages, ""); err != nil {
+		return err
+	}
+	if err := validateNoPerNodeImagesWithMatrix("Chainlink oracle", args.ChainlinkOracleImageMatrix, args.ChainlinkOracleImages, ""); err != nil {
+		return err
+	}
+	for testName, overrideArgs := range args.TestOverrides {
+		if err := validateNoPerNodeImagesWithMatrix("Geth service", args.GethServiceImageMatrix, overrideArgs.GethNodeImages, testName); err != nil {
+			return err
+		}
+		if err := validateNoPerNodeImagesWithMatrix("Chainlink oracle", args.ChainlinkOracleImageMatrix, overrideArgs.ChainlinkOracleImages, testName); err != nil {
+			return err
+		}
+	}
+	if strings.TrimSpace(args.PostgresImage) == "" {
+		return stacktrace.NewError("Postgres image is empty")
+	}
+	if strings.TrimSpace(args.PriceFeedServerImage) == "" {
+		return stacktrace.NewError("Price feed server image is empty")
+	}
+	if args.NumGethNodes < 0 || args.NumSigners < 0 || args.NumOracles < 0 {
+		return stacktrace.NewError("Node counts can't be negative")
+	}
+	for testName, overrideArgs := range args.TestOverrides {
+		if overrideArgs.NumGethNodes < 0 || overrideArgs.NumSigners < 0 || overrideArgs.NumOracles < 0 {
+			return stacktrace.NewError("Node counts in the overrides for test '%v' can't be negative", testName)
+		}
+	}
+	seenTestNames := map[string]bool{}
+	for _, testName := range args.TestsToRun {
+		if seenTestNames[testName] {
+			return stacktrace.NewError("Test '%v' is listed more than once in the tests to run", testName)
+		}
+		seenTestNames[testName] = true
+	}
+	return nil
+}
+
+/*
+	Checks that exactly one of the single image and the image matrix for a service is given, and that the matrix has no
+	blank or repeated images.
+ */
+func validateImageMatrixArgs(serviceDescription string, image string, imageMatrix []string) error {
+	if len(imageMatrix) == 0 {
+		if strings.TrimSpace(image) == "" {
+			return stacktrace.NewError("%v image is empty", serviceDescription)
+		}
+		return nil
+	}
+	if image != "" {
+		return stacktrace.NewError("%v image and %v image matrix can't both be set", serviceDescription, serviceDescription)
+	}
+	seenImages := map[string]bool{}
+	for _, matrixImage := range imageMatrix {
+		if strings.TrimSpace(matrixImage) == "" {
+			return stacktrace.NewError("%v image matrix contains an empty image", serviceDescription)
+		}
+		if seenImages[matrixImage] {
+			return stacktrace.NewError("%v image '%v' is listed more than once in the image matrix", serviceDescription, matrixImage)
+		}
+		seenImages[matrixImage] = true
+	}
+	return nil
+}
+
+/*
+	Checks that no per-node images are given for a service that has an image matrix. Per-node images replace the image
+	the matrix picks on those nodes, so every combination would quietly run the same image there. The test name is
+	empty for the testsuite-wide per-node images.
+ */
+func validateNoPerNodeImagesWithMatrix(serviceDescription string, imageMatrix []string, perNodeImages []string, testName string) error {
+	if len(imageMatrix) == 0 {
+		return nil
+	}
+	for nodeIdx, image := range perNodeImages {
+		if strings.TrimSpace(image) == "" {
+			continue
+		}
+		if testName == "" {
+			return stacktrace.NewError("%v image matrix and per-node %v images can't both be set, but node %v is given image '%v'",
+				serviceDescription, serviceDescription, nodeIdx, image)
+		}
+		return stacktrace.NewError("%v image matrix and per-node %v images can't both be set, but the overrides for test '%v' give node %v image '%v'",
+			serviceDescription, serviceDescription, testName, nodeIdx, image)
 	}
 	return nil
 }
 
+/*
+	The images to run a service with: the matrix if one was given, or just the single image otherwise.
+ */
+func getImageMatrix(image string, imageMatrix []string) []string {
+	if len(imageMatrix) == 0 {
+		return []string{image}
+	}
+	return imageMatrix
+}
+
+/*
+	Returns a copy of the base topology with every non-zero setting replaced by the given value.
+ */
+func applyTopologyArgs(base networks_impl.NetworkTopology, numGethNodes int, numSigners int, numOracles int,
+	gethNodeImages []string, oracleImages []string) networks_impl.NetworkTopology {
+	result := base
+	if numGethNodes != 0 {
+		result.NumGethNodes = numGethNodes
+	}
+	if numSigners != 0 {
+		result.NumSigners = numSigners
+	}
+	if numOracles != 0 {
+		result.NumOracles = numOracles
+	}
+	if len(gethNodeImages) != 0 {
+		result.GethNodeImages = gethNodeImages
+	}
+	if len(oracleImages) != 0 {
+		result.OracleImages = oracleImages
+	}
+	return result
+}
@@ -0,0 +1,133 @@
+package execution_impl
+
+import (
+	"testing"
+)
+
+const (
+	testGethImage = "ethereum/client-go:v1.10.1"
+	testOtherGethImage = "ethereum/client-go:v1.9.25"
+	testOracleImage = "smartcontract/chainlink:0.10.3"
+	testOtherOracleImage = "smartcontract/chainlink:0.10.2"
+)
+
+func TestValidateChainlinkArgs(t *testing.T) {
+	testCases := []struct {
+		name string
+		// Changes the otherwise valid args under test
+		modifyArgs func(args *ChainlinkTestsuiteArgs)
+		isErrorExpected bool
+	}{
+		{
+			name:       "single images",
+			modifyArgs: func(args *ChainlinkTestsuiteArgs) {},
+		},
+		{
+			name: "image matrices",
+			modifyArgs: func(args *ChainlinkTestsuiteArgs) {
+				args.GethServiceImage = ""
+				args.GethServiceImageMatrix = []string{testGethImage, testOtherGethImage}
+				args.ChainlinkOracleImage = ""
+				args.ChainlinkOracleImageMatrix = []string{testOracleImage, testOtherOracleImage}
+			},
+		},
+		{
+			name: "per-node images without a matrix",
+			modifyArgs: func(args *ChainlinkTestsuiteArgs) {
+				args.GethNodeImages = []string{"", testOtherGethImage}
+				args.ChainlinkOracleImages = []string{testOtherOracleImage}
+				args.TestOverrides = map[string]TestOverrideArgs{
+					"oracleUpgradeTest": {GethNodeImages: []string{testOtherGethImage}},
+				}
+			},
+		},
+		{
+			name: "single image and matrix",
+			modifyArgs: func(args *ChainlinkTestsuiteArgs) {
+				args.GethServiceImageMatrix = []string{testGethImage, testOtherGethImage}
+			},
+			isErrorExpected: true,
+		},
+		{
+			name: "geth matrix and per-node geth images",
+			modifyArgs: func(args *ChainlinkTestsuiteArgs) {
+				args.GethServiceImage = ""
+				args.GethServiceImageMatrix = []string{testGethImage, testOtherGethImage}
+				args.GethNodeImages = []string{"", testOtherGethImage}
+			},
+			isErrorExpected: true,
+		},
+		{
+			name: "oracle matrix and per-node oracle images",
+			modifyArgs: func(args *ChainlinkTestsuiteArgs) {
+				args.ChainlinkOracleImage = ""
+				args.ChainlinkOracleImageMatrix = []string{testOracleImage, testOtherOracleImage}
+				args.ChainlinkOracleImages = []string{testOtherOracleImage}
+			},
+			isErrorExpected: true,
+		},
+		{
+			name: "oracle matrix and per-node oracle images in a test override",
+			modifyArgs: func(args *ChainlinkTestsuiteArgs) {
+				args.ChainlinkOracleImage = ""
+				args.ChainlinkOracleImageMatrix = []string{testOracleImage, testOtherOracleImage}
+				args.TestOverrides = map[string]TestOverrideArgs{
+					"oracleUpgradeTest": {ChainlinkOracleImages: []string{testOtherOracleImage}},
+				}
+			},
+			isErrorExpected: true,
+		},
+		{
+			name: "geth matrix and per-node oracle images",
+			modifyArgs: func(args *ChainlinkTestsuiteArgs) {
+				args.GethServiceImage = ""
+				args.GethServiceImageMatrix = []string{testGethImage, testOtherGethImage}
+				args.ChainlinkOracleImages = []string{testOtherOracleImage}
+			},
+		},
+		{
+			name: "matrix and blank per-node images",
+			modifyArgs: func(args *ChainlinkTestsuiteArgs) {
+				args.GethServiceImage = ""
+				args.GethServiceImageMatrix = []string{testGethImage, testOtherGethImage}
+				args.GethNodeImages = []string{"", " "}
+			},
+		},
+		{
+			name: "negative node count in a test override",
+			modifyArgs: func(args *ChainlinkTestsuiteArgs) {
+				args.TestOverrides = map[string]TestOverrideArgs{
+					"oracleUpgradeTest": {NumOracles: -1},
+				}
+			},
+			isErrorExpected: true,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			args := getValidArgs()
+			testCase.modifyArgs(&args)
+			err := validateChainlinkArgs(args)
+			if testCase.isErrorExpected && err == nil {
+				t.Errorf("Expected args %+v to be rejected, but they were valid", args)
+			}
+			if !testCase.isErrorExpected && err != nil {
+				t.Errorf("Expected args %+v to be valid, but got an error: %v", args, err)
+			}
+		})
+	}
+}
+
+// ==========================================================================================
+//								Helper methods
+// ==========================================================================================
+
+func getValidArgs() ChainlinkTestsuiteArgs {
+	return ChainlinkTestsuiteArgs{
+		GethServiceImage:               testGethImage,
+		ChainlinkContractDeployerImage: "kurtosistech/chainlink-contract-deployer",
+		ChainlinkOracleImage:           testOracleImage,
+		PostgresImage:                  "postgres:13",
+		PriceFeedServerImage:           "kurtosistech/price-feed-server",
+	}
+}
@@ -0,0 +1,211 @@
+package load_generator
+
+import (
+	"bytes"
+	"encoding/hex"
+	"github.com/kurtosistech/chainlink-testing/testsuite/services_impl/geth"
+	"github.com/kurtosistech/chainlink-testing/testsuite/services_impl/geth/genesis"
+	"github.com/palantir/stacktrace"
+	"github.com/sirupsen/logrus"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// Intrinsic gas cost of a plain value transfer
+	transferGas = 21000
+	// Gas charged per non-zero byte of transaction data; our genesis doesn't enable Istanbul, so this is the pre-EIP-2028 cost
+	nonZeroDataByteGas = 68
+	// Arbitrary non-zero byte used to pad transaction data up to the requested gas
+	paddingByte = 0x01
+
+	// Value sent with every load transaction, as a hex quantity; the sender pays for gas either way
+	loadTransactionValueWei = "0x1"
+
+	maxTransactionsInFlight = 500
+	// Beyond this the ticker interval gets too short to keep up with, and past 1e9 it would round down to zero
+	maxTargetTransactionsPerSecond = 1000
+)
+
+type TransactionLoadConfig struct {
+	// Number of transactions per second to submit across all the target nodes
+	TargetTransactionsPerSecond int
+
+	// Gas each load transaction consumes; values above the 21000 transfer cost are reached by padding transaction data
+	GasPerTransaction uint64
+
+	// Accounts the load is sent from; these must be in the keystore of the target nodes and shouldn't be used
+	// elsewhere in the test, since geth assigns nonces per node
+	SenderAddresses []string
+
+	// Account receiving the load transactions
+	RecipientAddress string
+}
+
+type TransactionLoadStats struct {
+	NumSent   int64
+	NumFailed int64
+	Duration  time.Duration
+}
+
+/*
+	Submits transactions to a set of geth nodes at a target rate, to fill blocks while other parts of the test
+	(e.g. the oracle) are trying to get their own transactions mined.
+ */
+type TransactionLoadGenerator struct {
+	config TransactionLoadConfig
+	// Each sender is bound to a single node, so that node is the only one assigning that sender's nonces
+	senderNodes map[string]geth.GethNode
+	txData      string
+
+	stopChan  chan struct{}
+	waitGroup *sync.WaitGroup
+	startTime time.Time
+	numSent   int64
+	numFailed int64
+}
+
+func NewTransactionLoadGenerator(config TransactionLoadConfig, targetNodes []geth.GethNode) (*TransactionLoadGenerator, error) {
+	if config.TargetTransactionsPerSecond <= 0 {
+		return nil, stacktrace.NewError("Target transactions per second must be positive, but was %v", config.TargetTransactionsPerSecond)
+	}
+	if config.TargetTransactionsPerSecond > maxTargetTransactionsPerSecond {
+		return nil, stacktrace.NewError("Target transactions per second can be at most %v, but was %v", maxTargetTransactionsPerSecond, config.TargetTransactionsPerSecond)
+	}
+	if config.GasPerTransaction < transferGas {
+		return nil, stacktrace.NewError("Gas per transaction must be at least the %v gas of a transfer, but was %v", transferGas, config.GasPerTransaction)
+	}
+	if config.GasPerTransaction > geth.TargetGasLimit {
+		return nil, stacktrace.NewError("Gas per transaction %v exceeds the block gas limit of %v", config.GasPerTransaction, geth.TargetGasLimit)
+	}
+	if len(config.SenderAddresses) == 0 {
+		return nil, stacktrace.NewError("At least one sender address is required to generate transaction load")
+	}
+	if config.RecipientAddress == "" {
+		return nil, stacktrace.NewError("A recipient address is required to generate transaction load")
+	}
+	if len(targetNodes) == 0 {
+		return nil, stacktrace.NewError("At least one geth node is required to generate transaction load")
+	}
+
+	senderNodes := map[string]geth.GethNode{}
+	for idx, senderAddress := range config.SenderAddresses {
+		senderNodes[senderAddress] = targetNodes[idx%len(targetNodes)]
+	}
+	numPaddingBytes := int((config.GasPerTransaction - transferGas) / nonZeroDataByteGas)
+	txData := ""
+	if numPaddingBytes > 0 {
+		txData = "0x" + hex.EncodeToString(bytes.Repeat([]byte{paddingByte}, numPaddingBytes))
+	}
+	return &TransactionLoadGenerator{
+		config:      config,
+		senderNodes: senderNodes,
+		txData:      txData,
+	}, nil
+}
+
+/*
+	Number of load transactions a single block can hold, given the gas limit the signers target.
+ */
+func (generator *TransactionLoadGenerator) GetMaxTransactionsPerBlock() uint64 {
+	return geth.TargetGasLimit / generator.config.GasPerTransaction
+}
+
+/*
+	Unlocks the sender accounts and starts submitting transactions in the background until Stop is called.
+ */
+func (generator *TransactionLoadGenerator) Start() error {
+	if generator.stopChan != nil {
+		return stacktrace.NewError("Transaction load generator has already been started")
+	}
+	for senderAddress, node := range generator.senderNodes {
+		if err := node.UnlockAccount(senderAddress, geth.PrivateKeyPassword); err != nil {
+			return stacktrace.Propagate(err, "Failed to unlock load sender account %v", senderAddress)
+		}
+	}
+
+	maxTps := generator.GetMaxTransactionsPerBlock() / genesis.CliquePeriodSeconds
+	if uint64(generator.config.TargetTransactionsPerSecond) > maxTps {
+		logrus.Infof("Target of %v transactions/s exceeds the ~%v transactions/s that fit under the %v block gas limit; blocks will be saturated.",
+			generator.config.TargetTransactionsPerSecond,
+			maxTps,
+			geth.TargetGasLimit)
+	}
+
+	generator.stopChan = make(chan struct{})
+	generator.waitGroup = &sync.WaitGroup{}
+	generator.startTime = time.Now()
+	generator.waitGroup.Add(1)
+	go generator.run()
+	return nil
+}
+
+/*
+	Stops submitting new transactions, waits for in-flight ones to be submitted, and returns what was sent.
+ */
+func (generator *TransactionLoadGenerator) Stop() (TransactionLoadStats, error) {
+	if generator.stopChan == nil {
+		return TransactionLoadStats{}, stacktrace.NewError("Transaction load generator was never started")
+	}
+	close(generator.stopChan)
+	generator.waitGroup.Wait()
+	return TransactionLoadStats{
+		NumSent:   atomic.LoadInt64(&generator.numSent),
+		NumFailed: atomic.LoadInt64(&generator.numFailed),
+		Duration:  time.Since(generator.startTime),
+	}, nil
+}
+
+// ==========================================================================================
+//								Helper methods
+// ==========================================================================================
+
+func (generator *TransactionLoadGenerator) run() {
+	defer generator.waitGroup.Done()
+
+	senderAddresses := generator.config.SenderAddresses
+	inFlightSemaphore := make(chan struct{}, maxTransactionsInFlight)
+	ticker := time.NewTicker(time.Second / time.Duration(generator.config.TargetTransactionsPerSecond))
+	defer ticker.Stop()
+
+	nextSenderIdx := 0
+	for {
+		select {
+		case <-generator.stopChan:
+			return
+		case <-ticker.C:
+			senderAddress := senderAddresses[nextSenderIdx%len(senderAddresses)]
+			nextSenderIdx++
+			select {
+			case inFlightSemaphore <- struct{}{}:
+			default:
+				// The nodes aren't keeping up with the target rate, so count this as a failed submission rather than queueing unboundedly
+				atomic.AddInt64(&generator.numFailed, 1)
+				continue
+			}
+			generator.waitGroup.Add(1)
+			go func() {
+				defer generator.waitGroup.Done()
+				defer func() { <-inFlightSemaphore }()
+				generator.sendTransaction(senderAddress)
+			}()
+		}
+	}
+}
+
+func (generator *TransactionLoadGenerator) sendTransaction(senderAddress string) {
+	txArgs := geth.TransactionArgs{
+		From:  senderAddress,
+		To:    generator.config.RecipientAddress,
+		Gas:   geth.FormatHexQuantity(generator.config.GasPerTransaction),
+		Value: loadTransactionValueWei,
+		Data:  generator.txData,
+	}
+	if _, err := generator.senderNodes[senderAddress].SendRpcTransaction(txArgs); err != nil {
+		logrus.Tracef("Load transaction from %v failed: %v", senderAddress, err)
+		atomic.AddInt64(&generator.numFailed, 1)
+		return
+	}
+	atomic.AddInt64(&generator.numSent, 1)
+}
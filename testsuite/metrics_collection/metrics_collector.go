@@ -0,0 +1,363 @@
+package metrics_collection
+
+import (
+	"fmt"
+	"github.com/palantir/stacktrace"
+	"github.com/sirupsen/logrus"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	scrapeTimeout = 10 * time.Second
+)
+
+type Point struct {
+	Time time.Time
+	Value float64
+}
+
+type TimeSeries struct {
+	Name string
+	Labels map[string]string
+	Points []Point
+}
+
+/*
+	The series of a metric on a target whose labels include all of the given labels.
+ */
+type SeriesQuery struct {
+	TargetName string
+	MetricName string
+	Labels map[string]string
+}
+
+/*
+	Periodically scrapes Prometheus endpoints in the background, keeping every scraped sample in memory so that
+	tests can assert on how metrics evolved over the course of the test.
+ */
+type MetricsCollector struct {
+	httpClient *http.Client
+
+	// Mutex protecting access to the target URLs, series and scrape error counts
+	mutex *sync.Mutex
+	// Target name -> URL of its metrics endpoint
+	targetUrls map[string]string
+	// Target name -> series key -> series
+	series map[string]map[string]*TimeSeries
+	numScrapeErrors map[string]int
+
+	stopChan chan struct{}
+	waitGroup *sync.WaitGroup
+	// Guards against stopping twice, which would close stopChan twice
+	isStopped bool
+}
+
+func NewMetricsCollector() *MetricsCollector {
+	return &MetricsCollector{
+		targetUrls:      map[string]string{},
+		httpClient:      &http.Client{Timeout: scrapeTimeout},
+		mutex:           &sync.Mutex{},
+		series:          map[string]map[string]*TimeSeries{},
+		numScrapeErrors: map[string]int{},
+	}
+}
+
+/*
+	Registers an endpoint to scrape; must be called before Start.
+ */
+func (collector *MetricsCollector) AddTarget(targetName string, metricsUrl string) error {
+	collector.mutex.Lock()
+	defer collector.mutex.Unlock()
+	if collector.stopChan != nil {
+		return stacktrace.NewError("Can't add metrics target %v after collection has started", targetName)
+	}
+	if _, found := collector.targetUrls[targetName]; found {
+		return stacktrace.NewError("Metrics target %v has already been added", targetName)
+	}
+	collector.targetUrls[targetName] = metricsUrl
+	return nil
+}
+
+/*
+	Points an already-registered target at a new endpoint, e.g. once the service behind it has been replaced; unlike
+	AddTarget this can be called while collection is running. The target keeps the series scraped so far.
+ */
+func (collector *MetricsCollector) SetTargetUrl(targetName string, metricsUrl string) error {
+	collector.mutex.Lock()
+	defer collector.mutex.Unlock()
+	if _, found := collector.targetUrls[targetName]; !found {
+		return stacktrace.NewError("Metrics target %v hasn't been added", targetName)
+	}
+	collector.targetUrls[targetName] = metricsUrl
+	return nil
+}
+
+func (collector *MetricsCollector) Start(scrapeInterval time.Duration) error {
+	if collector.stopChan != nil {
+		return stacktrace.NewError("Metrics collection has already been started")
+	}
+	collector.stopChan = make(chan struct{})
+	collector.waitGroup = &sync.WaitGroup{}
+	collector.waitGroup.Add(1)
+	go collector.run(scrapeInterval)
+	return nil
+}
+
+/*
+	Stops scraping, after taking one last scrape so the series reflect the state at the time of stopping.
+ */
+func (collector *MetricsCollector) Stop() error {
+	if collector.stopChan == nil {
+		return stacktrace.NewError("Metrics collection was never started")
+	}
+	collector.mutex.Lock()
+	isStopped := collector.isStopped
+	collector.isStopped = true
+	collector.mutex.Unlock()
+	if isStopped {
+		return stacktrace.NewError("Metrics collection has already been stopped")
+	}
+	close(collector.stopChan)
+	collector.waitGroup.Wait()
+	collector.scrapeAll()
+	return nil
+}
+
+/*
+	Scrapes every target immediately, outside of the regular interval.
+ */
+func (collector *MetricsCollector) ScrapeNow() {
+	collector.scrapeAll()
+}
+
+/*
+	Gets every series of the given metric on the target whose labels include all of the given labels.
+ */
+func (collector *MetricsCollector) GetSeries(targetName string, metricName string, labels map[string]string) []TimeSeries {
+	collector.mutex.Lock()
+	defer collector.mutex.Unlock()
+
+	result := []TimeSeries{}
+	for _, series := range collector.series[targetName] {
+		if series.Name != metricName || !labelsMatch(series.Labels, labels) {
+			continue
+		}
+		result = append(result, TimeSeries{
+			Name:   series.Name,
+			Labels: series.Labels,
+			Points: append([]Point{}, series.Points...),
+		})
+	}
+	return result
+}
+
+/*
+	Sums the most recent value of every series matching the metric name and labels, e.g. to total a counter
+	across all its label values.
+ */
+func (collector *MetricsCollector) GetLatestValue(targetName string, metricName string, labels map[string]string) (float64, error) {
+	matchingSeries := collector.GetSeries(targetName, metricName, labels)
+	if len(matchingSeries) == 0 {
+		return 0, stacktrace.NewError("No samples of metric %v with labels %v have been scraped from target %v", metricName, labels, targetName)
+	}
+	total := 0.0
+	for _, series := range matchingSeries {
+		total += series.Points[len(series.Points) - 1].Value
+	}
+	return total, nil
+}
+
+/*
+	Sums how much every series matching the metric name and labels grew between its first and latest scrape; intended
+	for counters. Series that first appeared partway through collection count from zero.
+ */
+func (collector *MetricsCollector) GetIncrease(targetName string, metricName string, labels map[string]string) (float64, error) {
+	matchingSeries := collector.GetSeries(targetName, metricName, labels)
+	if len(matchingSeries) == 0 {
+		return 0, stacktrace.NewError("No samples of metric %v with labels %v have been scraped from target %v", metricName, labels, targetName)
+	}
+	collector.mutex.Lock()
+	firstScrapeTime := collector.getFirstScrapeTime(targetName)
+	collector.mutex.Unlock()
+
+	total := 0.0
+	for _, series := range matchingSeries {
+		latest := series.Points[len(series.Points) - 1].Value
+		first := series.Points[0]
+		if first.Time.After(firstScrapeTime) {
+			total += latest
+		} else {
+			total += latest - first.Value
+		}
+	}
+	return total, nil
+}
+
+/*
+	Gets the value of each query, summed across its matching series like GetLatestValue does, as of the latest scrape
+	that has samples for every query. Values of different targets taken from their own latest scrapes can be a scrape
+	interval or more apart, which is too far apart to compare when both are moving, e.g. two nodes' chain heads.
+ */
+func (collector *MetricsCollector) GetLatestValuesFromSameScrape(queries ...SeriesQuery) ([]float64, error) {
+	// Query index -> scrape time -> the query's value in that scrape
+	valuesByScrapeTime := []map[time.Time]float64{}
+	for _, query := range queries {
+		matchingSeries := collector.GetSeries(query.TargetName, query.MetricName, query.Labels)
+		if len(matchingSeries) == 0 {
+			return nil, stacktrace.NewError("No samples of metric %v with labels %v have been scraped from target %v", query.MetricName, query.Labels, query.TargetName)
+		}
+		queryValues := map[time.Time]float64{}
+		for _, series := range matchingSeries {
+			for _, point := range series.Points {
+				queryValues[point.Time] += point.Value
+			}
+		}
+		valuesByScrapeTime = append(valuesByScrapeTime, queryValues)
+	}
+
+	var latestCommonScrapeTime time.Time
+	for scrapeTime := range valuesByScrapeTime[0] {
+		isInEveryQuery := true
+		for _, queryValues := range valuesByScrapeTime[1:] {
+			if _, found := queryValues[scrapeTime]; !found {
+				isInEveryQuery = false
+				break
+			}
+		}
+		if isInEveryQuery && scrapeTime.After(latestCommonScrapeTime) {
+			latestCommonScrapeTime = scrapeTime
+		}
+	}
+	if latestCommonScrapeTime.IsZero() {
+		return nil, stacktrace.NewError("No scrape has samples for every one of the queries %+v", queries)
+	}
+	values := []float64{}
+	for _, queryValues := range valuesByScrapeTime {
+		values = append(values, queryValues[latestCommonScrapeTime])
+	}
+	return values, nil
+}
+
+func (collector *MetricsCollector) GetNumScrapeErrors(targetName string) int {
+	collector.mutex.Lock()
+	defer collector.mutex.Unlock()
+	return collector.numScrapeErrors[targetName]
+}
+
+// ==========================================================================================
+//								Helper methods
+// ==========================================================================================
+
+func (collector *MetricsCollector) run(scrapeInterval time.Duration) {
+	defer collector.waitGroup.Done()
+	ticker := time.NewTicker(scrapeInterval)
+	defer ticker.Stop()
+
+	collector.scrapeAll()
+	for {
+		select {
+		case <-collector.stopChan:
+			return
+		case <-ticker.C:
+			collector.scrapeAll()
+		}
+	}
+}
+
+func (collector *MetricsCollector) scrapeAll() {
+	// Copied so a target's URL can be changed while a scrape is under way
+	collector.mutex.Lock()
+	targetUrls := map[string]string{}
+	for targetName, metricsUrl := range collector.targetUrls {
+		targetUrls[targetName] = metricsUrl
+	}
+	collector.mutex.Unlock()
+	// Every target's samples are stored under the time the scrape started, so they can be matched up across targets
+	scrapeTime := time.Now()
+	for targetName, metricsUrl := range targetUrls {
+		samples, err := collector.scrape(metricsUrl)
+		if err != nil {
+			// A target being briefly unreachable is itself worth asserting on, so we record it rather than failing
+			logrus.Debugf("An error occurred scraping metrics from %v: %v", targetName, err)
+			collector.mutex.Lock()
+			collector.numScrapeErrors[targetName]++
+			collector.mutex.Unlock()
+			continue
+		}
+		collector.storeSamples(targetName, scrapeTime, samples)
+	}
+}
+
+func (collector *MetricsCollector) scrape(metricsUrl string) ([]Sample, error) {
+	resp, err := collector.httpClient.Get(metricsUrl)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "An error occurred requesting metrics from %v", metricsUrl)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, stacktrace.NewError("Received non-200 status code %v from metrics endpoint %v", resp.StatusCode, metricsUrl)
+	}
+	samples, err := ParsePrometheusText(resp.Body)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "An error occurred parsing metrics from %v", metricsUrl)
+	}
+	return samples, nil
+}
+
+func (collector *MetricsCollector) storeSamples(targetName string, scrapeTime time.Time, samples []Sample) {
+	collector.mutex.Lock()
+	defer collector.mutex.Unlock()
+
+	targetSeries, found := collector.series[targetName]
+	if !found {
+		targetSeries = map[string]*TimeSeries{}
+		collector.series[targetName] = targetSeries
+	}
+	for _, sample := range samples {
+		key := getSeriesKey(sample.Name, sample.Labels)
+		series, found := targetSeries[key]
+		if !found {
+			series = &TimeSeries{
+				Name:   sample.Name,
+				Labels: sample.Labels,
+				Points: []Point{},
+			}
+			targetSeries[key] = series
+		}
+		series.Points = append(series.Points, Point{Time: scrapeTime, Value: sample.Value})
+	}
+}
+
+// Must be called with the mutex held
+func (collector *MetricsCollector) getFirstScrapeTime(targetName string) time.Time {
+	var firstScrapeTime time.Time
+	for _, series := range collector.series[targetName] {
+		seriesStart := series.Points[0].Time
+		if firstScrapeTime.IsZero() || seriesStart.Before(firstScrapeTime) {
+			firstScrapeTime = seriesStart
+		}
+	}
+	return firstScrapeTime
+}
+
+func getSeriesKey(metricName string, labels map[string]string) string {
+	labelPairs := []string{}
+	for key, value := range labels {
+		labelPairs = append(labelPairs, fmt.Sprintf("%v=%q", key, value))
+	}
+	sort.Strings(labelPairs)
+	return metricName + "{" + strings.Join(labelPairs, ",") + "}"
+}
+
+func labelsMatch(seriesLabels map[string]string, wantedLabels map[string]string) bool {
+	for key, value := range wantedLabels {
+		if seriesLabels[key] != value {
+			return false
+		}
+	}
+	return true
+}
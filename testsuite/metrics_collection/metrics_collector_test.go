@@ -0,0 +1,105 @@
+package metrics_collection
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+const (
+	testGethTarget = "geth"
+	testOracleTarget = "oracle"
+	testChainHeadMetric = "chain_head_block"
+	testOracleHeadMetric = "head_tracker_current_head"
+)
+
+func TestGetLatestValuesFromSameScrape(t *testing.T) {
+	firstScrapeTime := time.Now()
+	secondScrapeTime := firstScrapeTime.Add(time.Second)
+	thirdScrapeTime := secondScrapeTime.Add(time.Second)
+	chainHeadQuery := SeriesQuery{TargetName: testGethTarget, MetricName: testChainHeadMetric, Labels: map[string]string{}}
+	oracleHeadQuery := SeriesQuery{TargetName: testOracleTarget, MetricName: testOracleHeadMetric, Labels: map[string]string{}}
+
+	testCases := []struct {
+		name string
+		// Target -> scrape time -> the samples scraped from the target then
+		scrapes map[string]map[time.Time][]Sample
+		expectedValues []float64
+		isErrorExpected bool
+	}{
+		{
+			name: "latest scrape has both",
+			scrapes: map[string]map[time.Time][]Sample{
+				testGethTarget: {
+					firstScrapeTime:  {{Name: testChainHeadMetric, Labels: map[string]string{}, Value: 10}},
+					secondScrapeTime: {{Name: testChainHeadMetric, Labels: map[string]string{}, Value: 12}},
+				},
+				testOracleTarget: {
+					firstScrapeTime:  {{Name: testOracleHeadMetric, Labels: map[string]string{}, Value: 9}},
+					secondScrapeTime: {{Name: testOracleHeadMetric, Labels: map[string]string{}, Value: 11}},
+				},
+			},
+			expectedValues: []float64{12, 11},
+		},
+		{
+			name: "latest scrape of one target failed",
+			scrapes: map[string]map[time.Time][]Sample{
+				testGethTarget: {
+					firstScrapeTime:  {{Name: testChainHeadMetric, Labels: map[string]string{}, Value: 10}},
+					secondScrapeTime: {{Name: testChainHeadMetric, Labels: map[string]string{}, Value: 12}},
+					thirdScrapeTime:  {{Name: testChainHeadMetric, Labels: map[string]string{}, Value: 14}},
+				},
+				testOracleTarget: {
+					firstScrapeTime:  {{Name: testOracleHeadMetric, Labels: map[string]string{}, Value: 9}},
+					secondScrapeTime: {{Name: testOracleHeadMetric, Labels: map[string]string{}, Value: 11}},
+				},
+			},
+			// The geth node's latest value is from a scrape the Oracle isn't in, so it'd overstate the lag
+			expectedValues: []float64{12, 11},
+		},
+		{
+			name: "no scrape has both",
+			scrapes: map[string]map[time.Time][]Sample{
+				testGethTarget: {
+					firstScrapeTime: {{Name: testChainHeadMetric, Labels: map[string]string{}, Value: 10}},
+				},
+				testOracleTarget: {
+					secondScrapeTime: {{Name: testOracleHeadMetric, Labels: map[string]string{}, Value: 11}},
+				},
+			},
+			isErrorExpected: true,
+		},
+		{
+			name: "metric never scraped",
+			scrapes: map[string]map[time.Time][]Sample{
+				testGethTarget: {
+					firstScrapeTime: {{Name: testChainHeadMetric, Labels: map[string]string{}, Value: 10}},
+				},
+			},
+			isErrorExpected: true,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			collector := NewMetricsCollector()
+			for targetName, targetScrapes := range testCase.scrapes {
+				for scrapeTime, samples := range targetScrapes {
+					collector.storeSamples(targetName, scrapeTime, samples)
+				}
+			}
+			values, err := collector.GetLatestValuesFromSameScrape(chainHeadQuery, oracleHeadQuery)
+			if testCase.isErrorExpected {
+				if err == nil {
+					t.Fatalf("Expected an error, but got values %v", values)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Getting the values failed: %v", err)
+			}
+			if !reflect.DeepEqual(values, testCase.expectedValues) {
+				t.Fatalf("Expected values %v, but got %v", testCase.expectedValues, values)
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package metrics_collection
+
+import (
+	"bufio"
+	"github.com/palantir/stacktrace"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const (
+	commentPrefix = "#"
+	labelsStart = '{'
+	labelsEnd = '}'
+	labelValueQuote = '"'
+	labelValueEscape = '\\'
+	labelSeparator = ','
+	labelKeyValueSeparator = '='
+)
+
+/*
+	A single sample line from a Prometheus text exposition, e.g. `head_tracker_current_head{chain="9"} 42`.
+ */
+type Sample struct {
+	Name string
+	Labels map[string]string
+	Value float64
+}
+
+/*
+	Parses the Prometheus text exposition format served by /metrics endpoints.
+	See: https://prometheus.io/docs/instrumenting/exposition_formats/#text-based-format
+ */
+func ParsePrometheusText(reader io.Reader) ([]Sample, error) {
+	samples := []Sample{}
+	scanner := bufio.NewScanner(reader)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, commentPrefix) {
+			continue
+		}
+		sample, err := parseSampleLine(line)
+		if err != nil {
+			return nil, stacktrace.Propagate(err, "An error occurred parsing metrics line %v: %v", lineNum, line)
+		}
+		samples = append(samples, sample)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, stacktrace.Propagate(err, "An error occurred reading the metrics exposition")
+	}
+	return samples, nil
+}
+
+// ==========================================================================================
+//								Helper methods
+// ==========================================================================================
+
+func parseSampleLine(line string) (Sample, error) {
+	labels := map[string]string{}
+	var name string
+	var rest string
+	labelsStartIdx := strings.IndexRune(line, labelsStart)
+	firstSpaceIdx := strings.IndexAny(line, " \t")
+	if labelsStartIdx >= 0 && (firstSpaceIdx < 0 || labelsStartIdx < firstSpaceIdx) {
+		name = line[:labelsStartIdx]
+		labelsEndIdx, err := parseLabels(line, labelsStartIdx + 1, labels)
+		if err != nil {
+			return Sample{}, stacktrace.Propagate(err, "An error occurred parsing the labels of metric %v", name)
+		}
+		rest = line[labelsEndIdx + 1:]
+	} else {
+		if firstSpaceIdx < 0 {
+			return Sample{}, stacktrace.NewError("Metric line has no value")
+		}
+		name = line[:firstSpaceIdx]
+		rest = line[firstSpaceIdx:]
+	}
+
+	// The value can be followed by an optional timestamp, which we ignore in favour of the scrape time
+	fields := strings.Fields(rest)
+	if len(fields) == 0 {
+		return Sample{}, stacktrace.NewError("Metric %v has no value", name)
+	}
+	value, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return Sample{}, stacktrace.Propagate(err, "An error occurred parsing value '%v' of metric %v", fields[0], name)
+	}
+	return Sample{
+		Name:   name,
+		Labels: labels,
+		Value:  value,
+	}, nil
+}
+
+/*
+	Parses `key="value",...` pairs starting at startIdx into labels, returning the index of the closing brace.
+ */
+func parseLabels(line string, startIdx int, labels map[string]string) (int, error) {
+	idx := startIdx
+	for idx < len(line) {
+		if line[idx] == labelsEnd {
+			return idx, nil
+		}
+		if line[idx] == labelSeparator || line[idx] == ' ' {
+			idx++
+			continue
+		}
+		keyValueSeparatorIdx := strings.IndexRune(line[idx:], labelKeyValueSeparator)
+		if keyValueSeparatorIdx < 0 {
+			return 0, stacktrace.NewError("Label at position %v has no value", idx)
+		}
+		key := strings.TrimSpace(line[idx:idx + keyValueSeparatorIdx])
+		idx += keyValueSeparatorIdx + 1
+		if idx >= len(line) || line[idx] != labelValueQuote {
+			return 0, stacktrace.NewError("Value of label %v isn't quoted", key)
+		}
+		idx++
+
+		var value strings.Builder
+		isClosed := false
+		for idx < len(line) && !isClosed {
+			char := line[idx]
+			switch {
+			case char == labelValueEscape && idx + 1 < len(line):
+				escaped := line[idx + 1]
+				if escaped == 'n' {
+					value.WriteByte('\n')
+				} else {
+					value.WriteByte(escaped)
+				}
+				idx += 2
+			case char == labelValueQuote:
+				isClosed = true
+				idx++
+			default:
+				value.WriteByte(char)
+				idx++
+			}
+		}
+		if !isClosed {
+			return 0, stacktrace.NewError("Value of label %v is never closed", key)
+		}
+		labels[key] = value.String()
+	}
+	return 0, stacktrace.NewError("Labels are never closed")
+}
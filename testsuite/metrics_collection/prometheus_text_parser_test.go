@@ -0,0 +1,134 @@
+package metrics_collection
+
+import (
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParsePrometheusText(t *testing.T) {
+	testCases := []struct {
+		name string
+		text string
+		expectedSamples []Sample
+	}{
+		{
+			name: "comments and blank lines",
+			text: "# HELP head_tracker_current_head The highest seen head number\n" +
+				"# TYPE head_tracker_current_head gauge\n" +
+				"\n" +
+				"head_tracker_current_head 42\n",
+			expectedSamples: []Sample{
+				{Name: "head_tracker_current_head", Labels: map[string]string{}, Value: 42},
+			},
+		},
+		{
+			name: "labels",
+			text: `run_status_update{job_spec_id="abc",status="completed"} 3` + "\n" +
+				`run_status_update{ job_spec_id="abc", status="errored", } 1`,
+			expectedSamples: []Sample{
+				{Name: "run_status_update", Labels: map[string]string{"job_spec_id": "abc", "status": "completed"}, Value: 3},
+				{Name: "run_status_update", Labels: map[string]string{"job_spec_id": "abc", "status": "errored"}, Value: 1},
+			},
+		},
+		{
+			name: "empty labels",
+			text: `chain_head_block{} 7`,
+			expectedSamples: []Sample{
+				{Name: "chain_head_block", Labels: map[string]string{}, Value: 7},
+			},
+		},
+		{
+			name: "escapes in label values",
+			text: `http_requests{path="C:\\data",query="say \"hi\"",body="line1\nline2"} 5`,
+			expectedSamples: []Sample{
+				{
+					Name:   "http_requests",
+					Labels: map[string]string{"path": `C:\data`, "query": `say "hi"`, "body": "line1\nline2"},
+					Value:  5,
+				},
+			},
+		},
+		{
+			name: "separators, braces and spaces inside label values",
+			text: `http_requests{path="/a b,c={d}"} 2`,
+			expectedSamples: []Sample{
+				{Name: "http_requests", Labels: map[string]string{"path": "/a b,c={d}"}, Value: 2},
+			},
+		},
+		{
+			name: "timestamps are ignored",
+			text: "tx_manager_num_gas_bumps 12 1395066363000\n" +
+				`run_status_update{status="completed"} 4 1395066363000`,
+			expectedSamples: []Sample{
+				{Name: "tx_manager_num_gas_bumps", Labels: map[string]string{}, Value: 12},
+				{Name: "run_status_update", Labels: map[string]string{"status": "completed"}, Value: 4},
+			},
+		},
+		{
+			name: "exponents and tabs",
+			text: "process_resident_memory_bytes\t1.5e+07",
+			expectedSamples: []Sample{
+				{Name: "process_resident_memory_bytes", Labels: map[string]string{}, Value: 1.5e+07},
+			},
+		},
+		{
+			name: "infinities",
+			text: `request_duration_seconds_bucket{le="+Inf"} +Inf` + "\n" +
+				"lowest_value -Inf",
+			expectedSamples: []Sample{
+				{Name: "request_duration_seconds_bucket", Labels: map[string]string{"le": "+Inf"}, Value: math.Inf(1)},
+				{Name: "lowest_value", Labels: map[string]string{}, Value: math.Inf(-1)},
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			samples, err := ParsePrometheusText(strings.NewReader(testCase.text))
+			if err != nil {
+				t.Fatalf("Parsing failed: %v", err)
+			}
+			if !reflect.DeepEqual(samples, testCase.expectedSamples) {
+				t.Fatalf("Expected samples %+v, but got %+v", testCase.expectedSamples, samples)
+			}
+		})
+	}
+}
+
+// NaN isn't equal to itself, so it can't be checked with the comparison above
+func TestParsePrometheusTextNaN(t *testing.T) {
+	samples, err := ParsePrometheusText(strings.NewReader(`request_duration_seconds{quantile="0.99"} NaN`))
+	if err != nil {
+		t.Fatalf("Parsing failed: %v", err)
+	}
+	if len(samples) != 1 || !math.IsNaN(samples[0].Value) {
+		t.Fatalf("Expected a single NaN sample, but got %+v", samples)
+	}
+	if quantile := samples[0].Labels["quantile"]; quantile != "0.99" {
+		t.Fatalf("Expected the sample's quantile label to be 0.99, but got '%v'", quantile)
+	}
+}
+
+func TestParsePrometheusTextRejectsMalformedLines(t *testing.T) {
+	testCases := []struct {
+		name string
+		text string
+	}{
+		{name: "no value", text: "head_tracker_current_head"},
+		{name: "no value after labels", text: `run_status_update{status="completed"}`},
+		{name: "value that isn't a number", text: "head_tracker_current_head forty-two"},
+		{name: "unquoted label value", text: `run_status_update{status=completed} 1`},
+		{name: "label without a value", text: `run_status_update{status} 1`},
+		{name: "unclosed label value", text: `run_status_update{status="completed} 1`},
+		{name: "unclosed labels", text: `run_status_update{status="completed" 1`},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			samples, err := ParsePrometheusText(strings.NewReader(testCase.text))
+			if err == nil {
+				t.Fatalf("Expected parsing '%v' to fail, but got samples %+v", testCase.text, samples)
+			}
+		})
+	}
+}
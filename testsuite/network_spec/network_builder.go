@@ -0,0 +1,117 @@
+package network_spec
+
+import (
+	"github.com/kurtosis-tech/kurtosis-libs/golang/lib/networks"
+	"github.com/kurtosis-tech/kurtosis-libs/golang/lib/services"
+	"github.com/kurtosistech/chainlink-testing/testsuite/dependency_graph"
+	"github.com/kurtosistech/chainlink-testing/testsuite/networks_impl"
+	"github.com/palantir/stacktrace"
+	"github.com/sirupsen/logrus"
+)
+
+/*
+	Builds the network a spec describes, starting each component after the ones it depends on.
+ */
+type NetworkBuilder struct {
+	gethDataDirArtifactId     services.FilesArtifactID
+	gethServiceImage          string
+	linkContractDeployerImage string
+	postgresImage             string
+	chainlinkOracleImage      string
+	priceFeedServerImage      string
+	versionLabel              string
+}
+
+func NewNetworkBuilder(gethDataDirArtifactId services.FilesArtifactID, gethServiceImage string, linkContractDeployerImage string,
+	postgresImage string, chainlinkOracleImage string, priceFeedServerImage string, versionLabel string) *NetworkBuilder {
+	return &NetworkBuilder{
+		gethDataDirArtifactId:     gethDataDirArtifactId,
+		gethServiceImage:          gethServiceImage,
+		linkContractDeployerImage: linkContractDeployerImage,
+		postgresImage:             postgresImage,
+		chainlinkOracleImage:      chainlinkOracleImage,
+		priceFeedServerImage:      priceFeedServerImage,
+		versionLabel:              versionLabel,
+	}
+}
+
+/*
+	Builds the network, starting every component as soon as the components it depends on are up, so components that
+	don't depend on each other start concurrently.
+ */
+func (builder NetworkBuilder) Build(networkCtx *networks.NetworkContext, spec NetworkSpec) (*networks_impl.ChainlinkNetwork, error) {
+	topology := spec.GetTopology()
+	topology.VersionLabel = builder.versionLabel
+	chainlinkNetwork := networks_impl.NewChainlinkNetwork(networkCtx,
+		builder.gethDataDirArtifactId,
+		builder.gethServiceImage,
+		builder.linkContractDeployerImage,
+		builder.postgresImage,
+		builder.chainlinkOracleImage,
+		builder.priceFeedServerImage,
+		topology)
+	graph := dependency_graph.NewDependencyGraph()
+	for _, component := range spec.Components {
+		component := component
+		err := graph.AddTask(component.Id, component.DependsOn, func() error {
+			logrus.Infof("Starting network spec component '%v' of type '%v'.", component.Id, component.Type)
+			return startComponent(chainlinkNetwork, component)
+		})
+		if err != nil {
+			return nil, stacktrace.Propagate(err, "An error occurred adding component '%v' of network spec '%v' to the startup graph", component.Id, spec.Name)
+		}
+	}
+	if err := graph.Run(); err != nil {
+		return nil, stacktrace.Propagate(err, "An error occurred starting the components of network spec '%v'", spec.Name)
+	}
+	return chainlinkNetwork, nil
+}
+
+// ==========================================================================================
+//								Helper methods
+// ==========================================================================================
+
+func startComponent(chainlinkNetwork *networks_impl.ChainlinkNetwork, component ComponentSpec) error {
+	switch component.Type {
+	case PostgresComponentType:
+		return chainlinkNetwork.AddPostgres()
+	case PriceFeedServerComponentType:
+		return chainlinkNetwork.AddPriceFeedServer()
+	case GethBootstrapperComponentType:
+		return chainlinkNetwork.AddBootstrapper()
+	case GethNodeComponentType:
+		serviceId, err := chainlinkNetwork.AddGethServiceWithImage(component.Image)
+		if err != nil {
+			return stacktrace.Propagate(err, "Failed to add an ethereum node.")
+		}
+		logrus.Infof("Added a geth service with id: %v", serviceId)
+		return nil
+	case PeerConnectionsComponentType:
+		return chainlinkNetwork.ManuallyConnectPeers()
+	case LinkContractsComponentType:
+		if err := chainlinkNetwork.DeployChainlinkContract(); err != nil {
+			return stacktrace.Propagate(err, "Failed to deploy the $LINK contract on the network.")
+		}
+		if err := chainlinkNetwork.FundLinkWallet(); err != nil {
+			return stacktrace.Propagate(err, "Failed to fund a $LINK wallet on the network.")
+		}
+		return nil
+	case OracleComponentType:
+		if component.DatabaseProxy {
+			if err := chainlinkNetwork.EnableOracleDatabaseProxy(); err != nil {
+				return stacktrace.Propagate(err, "Error enabling the Oracle database proxy.")
+			}
+		}
+		if err := chainlinkNetwork.AddOracleService(); err != nil {
+			return stacktrace.Propagate(err, "Error adding chainlink oracle to the network.")
+		}
+		if err := chainlinkNetwork.FundOracleEthAccounts(); err != nil {
+			return stacktrace.Propagate(err, "Error funding Oracle accounts.")
+		}
+		return nil
+	case PriceFeedJobComponentType:
+		return chainlinkNetwork.DeployOracleJob()
+	default:
+		return stacktrace.NewError("Unknown component type '%v'", component.Type)
+	}
+}
@@ -0,0 +1,327 @@
+package network_spec
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/kurtosistech/chainlink-testing/testsuite/networks_impl"
+	"github.com/palantir/stacktrace"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"sort"
+)
+
+const (
+	PostgresComponentType        = "postgres"
+	PriceFeedServerComponentType = "priceFeedServer"
+	GethBootstrapperComponentType = "gethBootstrapper"
+	GethNodeComponentType        = "gethNode"
+	// Connects every geth node to every other one; must depend on all of them
+	PeerConnectionsComponentType = "peerConnections"
+	// Deploys the $LINK and Oracle contracts and funds the consumer contract's $LINK wallet
+	LinkContractsComponentType = "linkContracts"
+	// Adds the network's oracles and funds their ethereum accounts
+	OracleComponentType = "oracle"
+	// Deploys the price feed job to every oracle
+	PriceFeedJobComponentType = "priceFeedJob"
+
+	specFileExtension = ".json"
+
+	defaultNumSigners = 1
+	defaultNumOracles = 1
+)
+
+// Spec names become part of test names, so they're kept to simple camelCase
+var specNameRegex = regexp.MustCompile("^[a-z][a-zA-Z0-9]*$")
+
+// Component type -> the component types it needs to be started after, directly or transitively
+var requiredDependencyTypes = map[string][]string{
+	PostgresComponentType:         {},
+	PriceFeedServerComponentType:  {},
+	GethBootstrapperComponentType: {},
+	GethNodeComponentType:         {GethBootstrapperComponentType},
+	PeerConnectionsComponentType:  {GethBootstrapperComponentType},
+	LinkContractsComponentType:    {PeerConnectionsComponentType},
+	OracleComponentType:           {PostgresComponentType, LinkContractsComponentType},
+	PriceFeedJobComponentType:     {OracleComponentType, PriceFeedServerComponentType},
+}
+
+// Every component type but gethNode describes something the network has at most one of
+var multipleAllowedTypes = map[string]bool{
+	GethNodeComponentType: true,
+}
+
+// Component types every network needs
+var requiredComponentTypes = []string{
+	GethBootstrapperComponentType,
+	PeerConnectionsComponentType,
+	LinkContractsComponentType,
+}
+
+/*
+	Declarative description of a ChainlinkNetwork and what to do with it once it's built, loaded from a JSON file.
+ */
+type NetworkSpec struct {
+	// Identifies the spec, and the test it's run as
+	Name string `json:"name"`
+
+	Description string `json:"description"`
+
+	// Number of geth nodes that seal blocks, starting with the bootstrapper; defaults to 1
+	NumSigners int `json:"numSigners"`
+
+	Components []ComponentSpec `json:"components"`
+
+	// Actions run in order once the network is built
+	Scenario []ScenarioAction `json:"scenario"`
+}
+
+type ComponentSpec struct {
+	// Name other components refer to this one by in their dependencies; unique within the spec
+	Id string `json:"id"`
+
+	Type string `json:"type"`
+
+	// IDs of the components this one is started after
+	DependsOn []string `json:"dependsOn"`
+
+	// Image override, for gethBootstrapper and gethNode components
+	Image string `json:"image"`
+
+	// Number of oracles, for oracle components; defaults to 1
+	Count int `json:"count"`
+
+	// Per-oracle image overrides by index, for oracle components
+	Images []string `json:"images"`
+
+	// Routes the primary oracle's database traffic through a proxy so scenarios can add latency, for oracle components
+	DatabaseProxy bool `json:"databaseProxy"`
+}
+
+/*
+	Loads every spec file in the given directory, sorted by filename.
+ */
+func LoadNetworkSpecs(dirpath string) ([]NetworkSpec, error) {
+	filepaths, err := filepath.Glob(filepath.Join(dirpath, "*" + specFileExtension))
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "An error occurred listing the network spec files in '%v'", dirpath)
+	}
+	sort.Strings(filepaths)
+
+	specs := []NetworkSpec{}
+	seenNames := map[string]string{}
+	for _, specFilepath := range filepaths {
+		spec, err := LoadNetworkSpec(specFilepath)
+		if err != nil {
+			return nil, stacktrace.Propagate(err, "An error occurred loading network spec file '%v'", specFilepath)
+		}
+		if otherFilepath, found := seenNames[spec.Name]; found {
+			return nil, stacktrace.NewError("Network spec files '%v' and '%v' both have name '%v'", otherFilepath, specFilepath, spec.Name)
+		}
+		seenNames[spec.Name] = specFilepath
+		specs = append(specs, spec)
+	}
+	return specs, nil
+}
+
+func LoadNetworkSpec(specFilepath string) (NetworkSpec, error) {
+	specBytes, err := ioutil.ReadFile(specFilepath)
+	if err != nil {
+		return NetworkSpec{}, stacktrace.Propagate(err, "An error occurred reading network spec file '%v'", specFilepath)
+	}
+	return ParseNetworkSpec(specBytes)
+}
+
+func ParseNetworkSpec(specBytes []byte) (NetworkSpec, error) {
+	var spec NetworkSpec
+	decoder := json.NewDecoder(bytes.NewReader(specBytes))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&spec); err != nil {
+		return NetworkSpec{}, stacktrace.Propagate(err, "An error occurred deserializing the network spec JSON")
+	}
+	if err := spec.Validate(); err != nil {
+		return NetworkSpec{}, stacktrace.Propagate(err, "Network spec '%v' is invalid", spec.Name)
+	}
+	return spec, nil
+}
+
+func (spec NetworkSpec) Validate() error {
+	if !specNameRegex.MatchString(spec.Name) {
+		return stacktrace.NewError("Spec name '%v' doesn't match %v", spec.Name, specNameRegex.String())
+	}
+
+	componentsById := map[string]ComponentSpec{}
+	numComponentsByType := map[string]int{}
+	for _, component := range spec.Components {
+		if component.Id == "" {
+			return stacktrace.NewError("Found a component of type '%v' without an ID", component.Type)
+		}
+		if _, found := componentsById[component.Id]; found {
+			return stacktrace.NewError("Component ID '%v' is used more than once", component.Id)
+		}
+		if _, found := requiredDependencyTypes[component.Type]; !found {
+			return stacktrace.NewError("Component '%v' has unknown type '%v'", component.Id, component.Type)
+		}
+		if err := component.validateFields(); err != nil {
+			return stacktrace.Propagate(err, "Component '%v' is invalid", component.Id)
+		}
+		componentsById[component.Id] = component
+		numComponentsByType[component.Type]++
+		if numComponentsByType[component.Type] > 1 && !multipleAllowedTypes[component.Type] {
+			return stacktrace.NewError("Spec has more than one component of type '%v'", component.Type)
+		}
+	}
+	for _, componentType := range requiredComponentTypes {
+		if numComponentsByType[componentType] == 0 {
+			return stacktrace.NewError("Spec has no component of type '%v', which every network needs", componentType)
+		}
+	}
+	for _, component := range spec.Components {
+		for _, dependencyId := range component.DependsOn {
+			if _, found := componentsById[dependencyId]; !found {
+				return stacktrace.NewError("Component '%v' depends on '%v', but there's no component with that ID", component.Id, dependencyId)
+			}
+		}
+	}
+
+	// Ordering also checks that there are no dependency cycles
+	orderedComponents, err := spec.GetComponentsInDependencyOrder()
+	if err != nil {
+		return stacktrace.Propagate(err, "Couldn't order the components by their dependencies")
+	}
+	ancestorTypes := map[string]map[string]bool{}
+	ancestorIds := map[string]map[string]bool{}
+	for _, component := range orderedComponents {
+		ancestorTypes[component.Id] = map[string]bool{}
+		ancestorIds[component.Id] = map[string]bool{}
+		for _, dependencyId := range component.DependsOn {
+			ancestorTypes[component.Id][componentsById[dependencyId].Type] = true
+			ancestorIds[component.Id][dependencyId] = true
+			for ancestorType := range ancestorTypes[dependencyId] {
+				ancestorTypes[component.Id][ancestorType] = true
+			}
+			for ancestorId := range ancestorIds[dependencyId] {
+				ancestorIds[component.Id][ancestorId] = true
+			}
+		}
+		for _, requiredType := range requiredDependencyTypes[component.Type] {
+			if !ancestorTypes[component.Id][requiredType] {
+				return stacktrace.NewError("Component '%v' of type '%v' must depend on a '%v' component", component.Id, component.Type, requiredType)
+			}
+		}
+	}
+	for _, component := range spec.Components {
+		if component.Type != PeerConnectionsComponentType {
+			continue
+		}
+		for _, otherComponent := range spec.Components {
+			isGethComponent := otherComponent.Type == GethNodeComponentType || otherComponent.Type == GethBootstrapperComponentType
+			if isGethComponent && !ancestorIds[component.Id][otherComponent.Id] {
+				return stacktrace.NewError("Peer connections component '%v' must depend on geth component '%v', or it wouldn't be connected", component.Id, otherComponent.Id)
+			}
+		}
+	}
+
+	if err := validateScenario(spec.Scenario, numComponentsByType, spec.hasOracleDatabaseProxy()); err != nil {
+		return stacktrace.Propagate(err, "The spec's scenario is invalid")
+	}
+	if err := spec.GetTopology().Validate(); err != nil {
+		return stacktrace.Propagate(err, "The spec describes an invalid network topology")
+	}
+	return nil
+}
+
+/*
+	Orders the components so that every component comes after its dependencies, keeping the spec's order between
+	components that don't depend on each other.
+ */
+func (spec NetworkSpec) GetComponentsInDependencyOrder() ([]ComponentSpec, error) {
+	isStarted := map[string]bool{}
+	orderedComponents := []ComponentSpec{}
+	for len(orderedComponents) < len(spec.Components) {
+		startedThisPass := false
+		for _, component := range spec.Components {
+			if isStarted[component.Id] {
+				continue
+			}
+			allDependenciesStarted := true
+			for _, dependencyId := range component.DependsOn {
+				allDependenciesStarted = allDependenciesStarted && isStarted[dependencyId]
+			}
+			if allDependenciesStarted {
+				isStarted[component.Id] = true
+				orderedComponents = append(orderedComponents, component)
+				startedThisPass = true
+			}
+		}
+		if !startedThisPass {
+			unstartedIds := []string{}
+			for _, component := range spec.Components {
+				if !isStarted[component.Id] {
+					unstartedIds = append(unstartedIds, component.Id)
+				}
+			}
+			return nil, stacktrace.NewError("Components %v have a dependency cycle", unstartedIds)
+		}
+	}
+	return orderedComponents, nil
+}
+
+/*
+	The node counts and images of the network the spec describes. Geth nodes are started concurrently, so their images
+	are passed to the network as each node is added rather than through the topology.
+ */
+func (spec NetworkSpec) GetTopology() networks_impl.NetworkTopology {
+	topology := networks_impl.NewDefaultNetworkTopology()
+	topology.NumSigners = defaultNumSigners
+	if spec.NumSigners != 0 {
+		topology.NumSigners = spec.NumSigners
+	}
+	topology.NumOracles = defaultNumOracles
+
+	numGethNodes := 0
+	for _, component := range spec.Components {
+		switch component.Type {
+		case GethBootstrapperComponentType:
+			numGethNodes++
+			topology.GethNodeImages = []string{component.Image}
+		case GethNodeComponentType:
+			numGethNodes++
+		case OracleComponentType:
+			if component.Count != 0 {
+				topology.NumOracles = component.Count
+			}
+			topology.OracleImages = component.Images
+		}
+	}
+	topology.NumGethNodes = numGethNodes
+	return topology
+}
+
+// ==========================================================================================
+//								Helper methods
+// ==========================================================================================
+
+func (component ComponentSpec) validateFields() error {
+	isGethComponent := component.Type == GethNodeComponentType || component.Type == GethBootstrapperComponentType
+	if component.Image != "" && !isGethComponent {
+		return stacktrace.NewError("Only geth components take an image, but got image '%v'", component.Image)
+	}
+	isOracleComponent := component.Type == OracleComponentType
+	if !isOracleComponent && (component.Count != 0 || len(component.Images) != 0 || component.DatabaseProxy) {
+		return stacktrace.NewError("Only oracle components take a count, images or a database proxy")
+	}
+	if component.Count < 0 {
+		return stacktrace.NewError("Count can't be negative, but was %v", component.Count)
+	}
+	return nil
+}
+
+func (spec NetworkSpec) hasOracleDatabaseProxy() bool {
+	for _, component := range spec.Components {
+		if component.Type == OracleComponentType && component.DatabaseProxy {
+			return true
+		}
+	}
+	return false
+}
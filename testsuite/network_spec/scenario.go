@@ -0,0 +1,164 @@
+package network_spec
+
+import (
+	"fmt"
+	"github.com/kurtosistech/chainlink-testing/testsuite/networks_impl"
+	"github.com/kurtosistech/chainlink-testing/testsuite/scenarios"
+	"github.com/palantir/stacktrace"
+	"time"
+)
+
+const (
+	// Requests data from every oracle and waits for each of them to fulfill it
+	RequestDataAction = "requestData"
+	StartTransactionLoadAction = "startTransactionLoad"
+	StopTransactionLoadAction = "stopTransactionLoad"
+	PauseDatabaseAction = "pauseDatabase"
+	ResumeDatabaseAction = "resumeDatabase"
+	RestartDatabaseAction = "restartDatabase"
+	TerminateDatabaseConnectionsAction = "terminateDatabaseConnections"
+	SetDatabaseLatencyAction = "setDatabaseLatency"
+	SleepAction = "sleep"
+)
+
+// Action -> the component types the network needs for the action to make sense
+var actionRequiredComponentTypes = map[string][]string{
+	RequestDataAction:                  {PriceFeedJobComponentType},
+	StartTransactionLoadAction:         {},
+	StopTransactionLoadAction:          {},
+	PauseDatabaseAction:                {OracleComponentType},
+	ResumeDatabaseAction:               {OracleComponentType},
+	RestartDatabaseAction:              {OracleComponentType},
+	TerminateDatabaseConnectionsAction: {OracleComponentType},
+	SetDatabaseLatencyAction:           {OracleComponentType},
+	SleepAction:                        {},
+}
+
+type ScenarioAction struct {
+	Action string `json:"action"`
+
+	// Number of times to run the action in a row; defaults to 1
+	Repeat int `json:"repeat"`
+
+	// Times a failed run of the action is retried before the scenario fails, on top of any retries the action has
+	// by default; only for actions that are safe to repeat
+	Retries int `json:"retries"`
+	TimeBetweenRetriesSeconds int `json:"timeBetweenRetriesSeconds"`
+
+	// For startTransactionLoad
+	TransactionsPerSecond int    `json:"transactionsPerSecond"`
+	GasPerTransaction     uint64 `json:"gasPerTransaction"`
+
+	// For setDatabaseLatency; zero removes the latency
+	LatencyMillis int `json:"latencyMillis"`
+
+	// For sleep
+	DurationSeconds int `json:"durationSeconds"`
+}
+
+/*
+	Builds a spec's scenario as scenario steps against a network built from the spec, so the actions are logged, timed,
+	retried and reported like any other test's steps.
+ */
+func BuildScenario(name string, network *networks_impl.ChainlinkNetwork, actions []ScenarioAction) (*scenarios.Scenario, error) {
+	steps, err := getScenarioSteps(actions)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "An error occurred building the steps of scenario '%v'", name)
+	}
+	return scenarios.NewScenario(name, network).AddSteps(steps...), nil
+}
+
+// ==========================================================================================
+//								Helper methods
+// ==========================================================================================
+
+/*
+	One step per run of each action, named after the action so repeated actions can be told apart in reports.
+ */
+func getScenarioSteps(actions []ScenarioAction) ([]scenarios.Step, error) {
+	steps := []scenarios.Step{}
+	for actionIdx, action := range actions {
+		numRepetitions := action.Repeat
+		if numRepetitions == 0 {
+			numRepetitions = 1
+		}
+		for repetition := 0; repetition < numRepetitions; repetition++ {
+			step, err := getActionStep(action)
+			if err != nil {
+				return nil, stacktrace.Propagate(err, "An error occurred building a step for action %v '%v'", actionIdx, action.Action)
+			}
+			step.Name = fmt.Sprintf("action %v '%v': %v", actionIdx, action.Action, step.Name)
+			if numRepetitions > 1 {
+				step.Name += fmt.Sprintf(" (%v of %v)", repetition + 1, numRepetitions)
+			}
+			if action.Retries > 0 {
+				step = step.WithRetries(step.NumRetries + action.Retries, time.Duration(action.TimeBetweenRetriesSeconds) * time.Second)
+			}
+			steps = append(steps, step)
+		}
+	}
+	return steps, nil
+}
+
+func getActionStep(action ScenarioAction) (scenarios.Step, error) {
+	switch action.Action {
+	case RequestDataAction:
+		return scenarios.RequestDataStep(), nil
+	case StartTransactionLoadAction:
+		return scenarios.StartTransactionLoadStep(action.TransactionsPerSecond, action.GasPerTransaction), nil
+	case StopTransactionLoadAction:
+		return scenarios.StopTransactionLoadStep(), nil
+	case PauseDatabaseAction:
+		return scenarios.PauseOracleDatabaseStep(), nil
+	case ResumeDatabaseAction:
+		return scenarios.ResumeOracleDatabaseStep(), nil
+	case RestartDatabaseAction:
+		return scenarios.RestartOracleDatabaseStep(), nil
+	case TerminateDatabaseConnectionsAction:
+		return scenarios.TerminateOracleDatabaseConnectionsStep(), nil
+	case SetDatabaseLatencyAction:
+		return scenarios.SetOracleDatabaseLatencyStep(time.Duration(action.LatencyMillis) * time.Millisecond), nil
+	case SleepAction:
+		return scenarios.SleepStep(time.Duration(action.DurationSeconds) * time.Second), nil
+	default:
+		return scenarios.Step{}, stacktrace.NewError("Unknown scenario action '%v'", action.Action)
+	}
+}
+
+func validateScenario(scenario []ScenarioAction, numComponentsByType map[string]int, hasOracleDatabaseProxy bool) error {
+	for actionIdx, action := range scenario {
+		requiredTypes, found := actionRequiredComponentTypes[action.Action]
+		if !found {
+			return stacktrace.NewError("Action %v has unknown type '%v'", actionIdx, action.Action)
+		}
+		for _, requiredType := range requiredTypes {
+			if numComponentsByType[requiredType] == 0 {
+				return stacktrace.NewError("Action %v '%v' needs a '%v' component in the network", actionIdx, action.Action, requiredType)
+			}
+		}
+		if action.Repeat < 0 {
+			return stacktrace.NewError("Action %v '%v' can't repeat a negative number of times", actionIdx, action.Action)
+		}
+		if action.Retries < 0 || action.TimeBetweenRetriesSeconds < 0 {
+			return stacktrace.NewError("Action %v '%v' can't have negative retries or time between them", actionIdx, action.Action)
+		}
+		switch action.Action {
+		case StartTransactionLoadAction:
+			if action.TransactionsPerSecond <= 0 || action.GasPerTransaction == 0 {
+				return stacktrace.NewError("Action %v '%v' needs positive transactionsPerSecond and gasPerTransaction", actionIdx, action.Action)
+			}
+		case SetDatabaseLatencyAction:
+			if !hasOracleDatabaseProxy {
+				return stacktrace.NewError("Action %v '%v' needs the oracle component to have a database proxy", actionIdx, action.Action)
+			}
+			if action.LatencyMillis < 0 {
+				return stacktrace.NewError("Action %v '%v' can't have negative latency", actionIdx, action.Action)
+			}
+		case SleepAction:
+			if action.DurationSeconds <= 0 {
+				return stacktrace.NewError("Action %v '%v' needs a positive durationSeconds", actionIdx, action.Action)
+			}
+		}
+	}
+	return nil
+}
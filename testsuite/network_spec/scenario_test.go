@@ -0,0 +1,85 @@
+package network_spec
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestGetScenarioSteps(t *testing.T) {
+	actions := []ScenarioAction{
+		{Action: RequestDataAction, Repeat: 2},
+		{Action: StartTransactionLoadAction, TransactionsPerSecond: 20, GasPerTransaction: 1000000},
+		{Action: TerminateDatabaseConnectionsAction, Retries: 3, TimeBetweenRetriesSeconds: 5},
+		{Action: SleepAction, DurationSeconds: 10},
+	}
+	steps, err := getScenarioSteps(actions)
+	if err != nil {
+		t.Fatalf("Building the steps failed: %v", err)
+	}
+
+	expectedSteps := []struct {
+		name string
+		numRetries int
+		timeBetweenRetries time.Duration
+		hasCleanup bool
+	}{
+		{name: "action 0 'requestData': fulfill request (1 of 2)"},
+		{name: "action 0 'requestData': fulfill request (2 of 2)"},
+		// Stops the load if the scenario fails before a stopTransactionLoad action does
+		{name: "action 1 'startTransactionLoad': start transaction load", hasCleanup: true},
+		// On top of the step's own retries
+		{name: "action 2 'terminateDatabaseConnections': terminate oracle database connections", numRetries: 5, timeBetweenRetries: 5 * time.Second},
+		{name: "action 3 'sleep': sleep for 10s"},
+	}
+	if len(steps) != len(expectedSteps) {
+		t.Fatalf("Expected %v steps, but got %v", len(expectedSteps), len(steps))
+	}
+	for stepIdx, expected := range expectedSteps {
+		step := steps[stepIdx]
+		if step.Name != expected.name {
+			t.Errorf("Expected step %v to be named \"%v\", but it was named \"%v\"", stepIdx, expected.name, step.Name)
+		}
+		if step.NumRetries != expected.numRetries {
+			t.Errorf("Expected step '%v' to have %v retries, but it has %v", step.Name, expected.numRetries, step.NumRetries)
+		}
+		if expected.numRetries > 0 && step.TimeBetweenRetries != expected.timeBetweenRetries {
+			t.Errorf("Expected step '%v' to wait %v between retries, but it waits %v", step.Name, expected.timeBetweenRetries, step.TimeBetweenRetries)
+		}
+		if hasCleanup := step.Cleanup != nil; hasCleanup != expected.hasCleanup {
+			t.Errorf("Expected step '%v' having a cleanup to be %v, but was %v", step.Name, expected.hasCleanup, hasCleanup)
+		}
+	}
+}
+
+func TestValidateScenario(t *testing.T) {
+	testCases := []struct {
+		action ScenarioAction
+		isErrorExpected bool
+	}{
+		{action: ScenarioAction{Action: RequestDataAction}},
+		{action: ScenarioAction{Action: "explode"}, isErrorExpected: true},
+		{action: ScenarioAction{Action: RequestDataAction, Repeat: -1}, isErrorExpected: true},
+		{action: ScenarioAction{Action: RequestDataAction, Retries: 2, TimeBetweenRetriesSeconds: 1}},
+		{action: ScenarioAction{Action: RequestDataAction, Retries: -1}, isErrorExpected: true},
+		{action: ScenarioAction{Action: RequestDataAction, Retries: 1, TimeBetweenRetriesSeconds: -1}, isErrorExpected: true},
+		{action: ScenarioAction{Action: StartTransactionLoadAction, TransactionsPerSecond: 20}, isErrorExpected: true},
+		{action: ScenarioAction{Action: SetDatabaseLatencyAction, LatencyMillis: -5}, isErrorExpected: true},
+		{action: ScenarioAction{Action: SleepAction}, isErrorExpected: true},
+	}
+	numComponentsByType := map[string]int{
+		OracleComponentType:       1,
+		PriceFeedJobComponentType: 1,
+	}
+	for _, testCase := range testCases {
+		t.Run(fmt.Sprintf("%+v", testCase.action), func(t *testing.T) {
+			err := validateScenario([]ScenarioAction{testCase.action}, numComponentsByType, true)
+			if testCase.isErrorExpected && err == nil {
+				t.Errorf("Expected the action to be rejected, but it was valid")
+			}
+			if !testCase.isErrorExpected && err != nil {
+				t.Errorf("Expected the action to be valid, but got an error: %v", err)
+			}
+		})
+	}
+}
@@ -2,27 +2,53 @@ package networks_impl
 
 import (
 	"fmt"
-	"github.com/kurtosis-tech/kurtosis-libs/golang/lib/networks"
 	"github.com/kurtosis-tech/kurtosis-libs/golang/lib/services"
+	"github.com/kurtosistech/chainlink-testing/testsuite/benchmarking"
+	"github.com/kurtosistech/chainlink-testing/testsuite/dependency_graph"
+	"github.com/kurtosistech/chainlink-testing/testsuite/load_generator"
+	"github.com/kurtosistech/chainlink-testing/testsuite/metrics_collection"
 	"github.com/kurtosistech/chainlink-testing/testsuite/services_impl/chainlink_contract_deployer"
 	"github.com/kurtosistech/chainlink-testing/testsuite/services_impl/chainlink_oracle"
 	"github.com/kurtosistech/chainlink-testing/testsuite/services_impl/geth"
 	"github.com/kurtosistech/chainlink-testing/testsuite/services_impl/postgres"
 	"github.com/kurtosistech/chainlink-testing/testsuite/services_impl/price_feed_server"
+	"github.com/kurtosistech/chainlink-testing/testsuite/test_reporting"
 	"github.com/palantir/stacktrace"
 	"github.com/sirupsen/logrus"
+	"math/big"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 )
 
 const (
 	ethereumBootstrapperId services.ServiceID = "ethereum-bootstrapper"
 	gethServiceIdPrefix                       = "ethereum-node-"
-	jobCompletedStatus string				  = "completed"
+	// Geth node service IDs are only assigned once the nodes start, so their startup tasks are named by node index
+	gethNodeTaskIdPrefix = "geth-node-"
 	linkContractDeployerId services.ServiceID = "link-contract-deployer"
 	postgresId services.ServiceID = "postgres"
 	priceFeedServerId services.ServiceID = "price-feed-server"
 	chainlinkOracleId services.ServiceID = "chainlink-oracle"
+	extraOracleIdPrefix = "chainlink-oracle-"
+	// The primary Oracle gets a new service each time it's upgraded, since Kurtosis can't restart a removed service
+	upgradedOracleIdPrefix = "chainlink-oracle-upgrade-"
+	// Gives the Oracle time to release its database lock and connections before the upgraded one starts
+	oracleStopTimeoutSeconds = 30
+
+	// The Oracle gets its own database and non-superuser role on the shared Postgres service
+	oracleDatabaseName = "chainlink_oracle"
+	oracleDatabaseUsername = "chainlink_oracle"
+	oracleDatabasePassword = "chainlink_oracle_password"
+	// Chainlink records the migrations it has applied in this table
+	oracleMigrationIdsQuery = "SELECT id FROM migrations ORDER BY id"
+	oracleJobIdsQuery = "SELECT id::text AS id FROM job_specs WHERE deleted_at IS NULL ORDER BY id"
+	// Extra oracles beyond the primary one get a database and role of their own, named with this prefix and a suffix
+	extraOracleDatabasePrefix = "chainlink_oracle_"
+
+	// After a database fault the Oracle has to notice its connections are gone and reconnect before resuming runs
+	waitForFaultRecoveryPolls = 120
 
 	waitForStartupTimeBetweenPolls = 1 * time.Second
 	waitForStartupMaxNumPolls = 30
@@ -30,60 +56,143 @@ const (
 	waitForTransactionFinalizationTimeBetweenPolls = 1 * time.Second
 	waitForTransactionFinalizationPolls = 30
 
-	waitForJobCompletionTimeBetweenPolls = 1 * time.Second
 	waitForJobCompletionPolls = 30
 
+	waitForJobRunsToFinishTimeBetweenPolls = 1 * time.Second
+	waitForJobRunsToFinishPolls = 120
+
+	// Selectors of the SimpleConsumer contract's getters, i.e. the first 4 bytes of the keccak256 of their signatures
+	simpleConsumerValueSelector = "0x3fa4f245" // value()
+	simpleConsumerNumWritesSelector = "0xda7dbca8" // numWrites()
+	eventEmitterEmitEventSelector = "0x2268e11c" // emitEvent(uint256,bytes32)
+	emitEventGasLimit = 100000
+	linkBalanceOfSelector = "0x70a08231" // balanceOf(address)
+	// Both only callable by the Oracle contract's owner, the first funded account
+	oracleWithdrawableSelector = "0x50188301" // withdrawable()
+	oracleWithdrawSelector = "0xf3fef3a3" // withdraw(address,uint256)
+	oracleGetAuthorizationStatusSelector = "0xd3e9c314" // getAuthorizationStatus(address)
+	// The consumer contract deployed by the migrations cancels requests for its owner, the first funded account
+	consumerCancelRequestSelector = "0xec65d0f8" // cancelRequest(bytes32,uint256,bytes4,uint256)
+	// The Oracle contract's owner, the first funded account, may fulfill requests as well as its authorized nodes
+	oracleFulfillOracleRequestSelector = "0x4ab0d190" // fulfillOracleRequest(bytes32,uint256,address,bytes4,uint256,bytes32)
+	// Set explicitly so transactions expected to revert are still sent, rather than failing gas estimation
+	requestTransactionGasLimit = 500000
+
+	// Requests made through the Oracle contract expire 5 minutes after they're made
+	waitForBlockTimestampTimeBetweenPolls = 1 * time.Second
+	waitForBlockTimestampPolls = 600
+
+	waitForLogRunTimeBetweenPolls = 500 * time.Millisecond
+	waitForLogRunPolls = 240
+
+	waitForBenchmarkCompletionTimeBetweenPolls = 1 * time.Second
+	waitForBenchmarkCompletionPolls = 300
+	noRunFailureReason = "Oracle didn't finish a run for the request"
+
 	oracleEthPreFundingAmount = "10000000000000000000000000000"
 
+	// Deploys the contracts and funds the Oracles, so it's unlocked on the bootstrapper
+	contractOwnerAddress = geth.FirstFundedAddress
+
+	// Receives the load; receiving doesn't use up nonces, so this can be an account the testsuite transacts from
+	loadRecipientAddress = geth.FirstFundedAddress
+
+	metricsScrapeInterval = 2 * time.Second
+
 	maxNumGethValidatorConnectednessVerifications = 3
 	timeBetweenGethValidatorConnectednessVerifications = 1 * time.Second
 )
 
+// Prefunded accounts that nothing else in the testsuite transacts from, to avoid nonce clashes
+var loadSenderAddresses = []string{geth.SecondFundedAddress, geth.ThirdFundedAddress}
+
 type ChainlinkNetwork struct {
-	networkCtx                  *networks.NetworkContext
+	networkCtx                  NetworkContext
+	// Guards every field that changes after construction, since services get added and read from many goroutines
+	// (concurrent startup tasks, load generation, test steps). Never held while calling out to a service.
+	mutex						*sync.RWMutex
 	gethDataDirArtifactId       services.FilesArtifactID
 	gethServiceImage            string
-	gethBootsrapperService      *geth.GethService
-	gethServices                map[services.ServiceID]*geth.GethService
+	gethBootsrapperService      geth.GethNode
+	gethServices                map[services.ServiceID]geth.GethNode
 	nextGethServiceId           int
 	linkContractAddress         string
 	oracleContractAddress		string
+	// Consumer contract the migrations deploy, which requests data unless another consumer is given
+	consumerContractAddress		string
 	linkContractDeployerImage   string
 	linkContractDeployerService *chainlink_contract_deployer.ChainlinkContractDeployerService
 	postgresImage               string
 	postgresService             *postgres.PostgresService
+	oracleDatabaseCredentials	postgres.DatabaseCredentials
+	oracleDatabaseProxy			*postgres.LatencyProxy
 	chainlinkOracleImage        string
-	chainlinkOracleService      *chainlink_oracle.ChainlinkOracleService
+	chainlinkOracleService      chainlink_oracle.ChainlinkOracle
+	// Kurtosis ID of the primary Oracle's current service, which changes when the Oracle is upgraded; the primary
+	// Oracle is still listed under chainlinkOracleId everywhere else
+	chainlinkOracleServiceId	services.ServiceID
+	// Oracles beyond the primary one above, when the topology asks for more than one
+	extraOracleServices			map[services.ServiceID]chainlink_oracle.ChainlinkOracle
+	extraOracleJobIds			map[services.ServiceID]string
+	numOracleUpgrades			int
+	topology					NetworkTopology
 	priceFeedServerImage		string
 	priceFeedServer				*price_feed_server.PriceFeedServer
 	priceFeedJobId				string
+	// Contract that jobs without an on-chain requester (cron and web initiated ones) write their results to
+	simpleConsumerAddress		string
+	// Contract that emits the events EthLog initiated jobs watch
+	eventEmitterAddress			string
+	transactionLoadGenerator	*load_generator.TransactionLoadGenerator
+	// Collector currently scraping, if any
+	metricsCollector			*metrics_collection.MetricsCollector
+	// The last collector to be stopped, which keeps its metrics for assertions
+	stoppedMetricsCollector		*metrics_collection.MetricsCollector
+	// Where RunTestStep records steps; nil when the test isn't being reported on
+	testReport					*test_reporting.TestReport
+}
+
+/*
+	$LINK balances, in juels, of the accounts requests move $LINK between.
+ */
+type LinkBalances struct {
+	// The consumer contract the migrations deployed, which pays for requests
+	Consumer *big.Int
+	// The Oracle contract, which holds payments in escrow until they're withdrawn
+	OracleContract *big.Int
+	// The ethereum keys of every Oracle node in the network, by address
+	OracleNodeKeys map[string]*big.Int
 }
 
-func NewChainlinkNetwork(networkCtx *networks.NetworkContext, gethDataDirArtifactId services.FilesArtifactID,
+func NewChainlinkNetwork(networkCtx NetworkContext, gethDataDirArtifactId services.FilesArtifactID,
 	gethServiceImage string, linkContractDeployerImage string, postgresImage string,
-	chainlinkOracleImage string, priceFeedServerImage string) *ChainlinkNetwork {
+	chainlinkOracleImage string, priceFeedServerImage string, topology NetworkTopology) *ChainlinkNetwork {
 	return &ChainlinkNetwork{
 		networkCtx:                networkCtx,
+		mutex:					   &sync.RWMutex{},
 		gethDataDirArtifactId:     gethDataDirArtifactId,
 		gethServiceImage:          gethServiceImage,
 		gethBootsrapperService:    nil,
-		gethServices:              map[services.ServiceID]*geth.GethService{},
+		gethServices:              map[services.ServiceID]geth.GethNode{},
 		nextGethServiceId:         0,
 		linkContractAddress:       "",
 		linkContractDeployerImage: linkContractDeployerImage,
 		postgresImage:             postgresImage,
 		chainlinkOracleImage:      chainlinkOracleImage,
 		priceFeedServerImage:	   priceFeedServerImage,
+		extraOracleServices:	   map[services.ServiceID]chainlink_oracle.ChainlinkOracle{},
+		extraOracleJobIds:		   map[services.ServiceID]string{},
+		topology:				   topology,
 	}
 }
 
 func (network *ChainlinkNetwork) DeployChainlinkContract() error {
-	if len(network.gethServices) == 0 {
+	if len(network.GetGethServices()) == 0 {
 		return stacktrace.NewError("Can not deploy contract because the network does not have non-bootstrapper nodes yet.")
 	}
 
 	// We could pick any node here, but we go with the bootstrapper arbitrarily.
-	deployService := network.gethBootsrapperService
+	deployService := network.GetBootstrapper()
 	initializer := chainlink_contract_deployer.NewChainlinkContractDeployerInitializer(network.linkContractDeployerImage)
 	uncastedContractDeployer, checker, err := network.networkCtx.AddService(linkContractDeployerId, initializer)
 	if err != nil {
@@ -93,276 +202,1576 @@ func (network *ChainlinkNetwork) DeployChainlinkContract() error {
 		return stacktrace.Propagate(err, "An error occurred waiting for the $LINK contract deployer service to start")
 	}
 	castedContractDeployer := uncastedContractDeployer.(*chainlink_contract_deployer.ChainlinkContractDeployerService)
+	network.mutex.Lock()
 	network.linkContractDeployerService = castedContractDeployer
+	network.mutex.Unlock()
 
-	linkContractAddress, oracleContractAddress, err := network.linkContractDeployerService.DeployContract(deployService.GetIPAddress(), strconv.Itoa(deployService.GetRpcPort()))
+	linkContractAddress, oracleContractAddress, consumerContractAddress, err := castedContractDeployer.DeployContract(
+		deployService.GetIPAddress(), strconv.Itoa(deployService.GetRpcPort()))
 	if err != nil {
 		return stacktrace.Propagate(err, "An error occurred deploying the $LINK contract to the testnet.")
 	}
+	network.mutex.Lock()
 	network.linkContractAddress = linkContractAddress
 	network.oracleContractAddress = oracleContractAddress
+	network.consumerContractAddress = consumerContractAddress
+	network.mutex.Unlock()
 	return nil
 }
 
 func (network *ChainlinkNetwork) DeployOracleJob() error {
-	if network.oracleContractAddress == "" {
+	oracleContractAddress := network.GetOracleContractAddress()
+	if oracleContractAddress == "" {
 		return stacktrace.NewError("Can not deploy Oracle job because Oracle contract has not yet been deployed.")
 	}
 	oracleService := network.GetChainlinkOracle()
-	jobId, err := oracleService.SetJobSpec(network.oracleContractAddress)
+	jobId, err := oracleService.SetJobSpec(oracleContractAddress)
 	if err != nil {
 		return stacktrace.Propagate(err, "Failed to set job spec.")
 	}
+	network.mutex.Lock()
 	network.priceFeedJobId = jobId
+	network.mutex.Unlock()
 	logrus.Debugf("Information for running smart contract: Oracle Address: %v, JobId: %v",
-		network.oracleContractAddress,
-		network.priceFeedJobId)
+		oracleContractAddress,
+		jobId)
+	for serviceId, extraOracleService := range network.getExtraOracleServices() {
+		extraJobId, err := extraOracleService.SetJobSpec(oracleContractAddress)
+		if err != nil {
+			return stacktrace.Propagate(err, "Failed to set job spec on Oracle %v.", serviceId)
+		}
+		network.mutex.Lock()
+		network.extraOracleJobIds[serviceId] = extraJobId
+		network.mutex.Unlock()
+		logrus.Debugf("Oracle %v has JobId: %v", serviceId, extraJobId)
+	}
 	return nil
 }
 
 func (network *ChainlinkNetwork) FundLinkWallet() error {
-	if network.linkContractDeployerService == nil {
+	linkContractDeployerService := network.getLinkContractDeployer()
+	if linkContractDeployerService == nil {
 		return stacktrace.NewError("Tried to fund $LINK wallet before deploying $LINK contract.")
 	}
-	err := network.linkContractDeployerService.FundLinkWalletContract()
+	err := linkContractDeployerService.FundLinkWalletContract()
 	if err != nil {
 		return stacktrace.Propagate(err, "An error occurred funding an initial $LINK wallet on the testnet.")
 	}
 	return nil
 }
 
-func (network *ChainlinkNetwork) FundOracleEthAccounts() error {
-	if network.chainlinkOracleService == nil {
-		return stacktrace.NewError("Tried to fund Oracle eth accounts before deploying Oracle.")
+/*
+	Transfers the given $LINK, in juels, to the consumer contract the migrations deployed.
+ */
+func (network *ChainlinkNetwork) FundLinkWalletWithAmount(linkAmountJuels string) error {
+	linkContractDeployerService := network.getLinkContractDeployer()
+	if linkContractDeployerService == nil {
+		return stacktrace.NewError("Tried to fund $LINK wallet before deploying $LINK contract.")
 	}
-	oracleEthAccounts, err := network.chainlinkOracleService.GetEthAccounts()
+	err := linkContractDeployerService.FundLinkWalletContractWithAmount(linkAmountJuels)
 	if err != nil {
-		return stacktrace.Propagate(err, "An error occurred getting the Oracle's ethereum accounts")
+		return stacktrace.Propagate(err, "An error occurred funding the $LINK wallet with %v juels.", linkAmountJuels)
 	}
-	for _, ethAccount := range oracleEthAccounts {
-		toAddress := ethAccount.Attributes.Address
-		err = network.gethBootsrapperService.SendTransaction(geth.FirstFundedAddress, toAddress, oracleEthPreFundingAmount)
-		if err != nil {
-			return stacktrace.Propagate(err, "An error occurred sending eth between accounts.")
+	return nil
+}
+
+func (network *ChainlinkNetwork) FundOracleEthAccounts() error {
+	if network.GetChainlinkOracle() == nil {
+		return stacktrace.NewError("Tried to fund Oracle eth accounts before deploying Oracle.")
+	}
+	for serviceId, oracleService := range network.GetOracleServices() {
+		if err := network.fundOracleEthAccounts(oracleService); err != nil {
+			return stacktrace.Propagate(err, "An error occurred funding the ethereum accounts of Oracle %v", serviceId)
 		}
 	}
+	return nil
+}
 
-	/*
-		Poll for transaction finalization so that we know that the Oracle's ethereum accounts are funded.
-		See: https://docs.chain.link/docs/running-a-chainlink-node#start-the-chainlink-node, "you will
-		need to send some ETH to your node's address in order for it to fulfill requests".
-	 */
-	ethAccountsFunded := false
-	numPolls := 0
-	for !ethAccountsFunded && numPolls < waitForTransactionFinalizationPolls {
-		time.Sleep(waitForTransactionFinalizationTimeBetweenPolls)
-		oracleEthAccounts, err = network.chainlinkOracleService.GetEthAccounts()
+/*
+	Runs scripts on the contract deployer container which request data from the Oracle.
+ */
+func (network *ChainlinkNetwork) RequestData() error {
+	oracleService := network.GetChainlinkOracle()
+	if oracleService == nil {
+		return stacktrace.NewError("Tried to request data before deploying the oracle service.")
+	}
+	requestTxHash, err := network.SendDataRequest()
+	if err != nil {
+		return stacktrace.Propagate(err, "An error occurred requesting data from the Oracle contract on-chain.")
+	}
+	priceFeedJobId := network.GetPriceFeedJobId()
+	if err := waitForRequestRun(oracleService, priceFeedJobId, requestTxHash, waitForJobCompletionPolls); err != nil {
+		return stacktrace.Propagate(err, "Oracle job %v failed.", priceFeedJobId)
+	}
+	// Every extra Oracle has its own copy of the job, so each one gets a request of its own
+	extraOracleJobIds := network.getExtraOracleJobIds()
+	for serviceId, extraOracleService := range network.getExtraOracleServices() {
+		requestTxHash, err := network.sendDataRequestForJob(extraOracleJobIds[serviceId])
 		if err != nil {
-			return stacktrace.Propagate(err, "An error occurred getting the Oracle's ethereum accounts")
+			return stacktrace.Propagate(err, "An error occurred requesting data from Oracle %v on-chain.", serviceId)
 		}
-		numPolls += 1
-
-		// Eth Accounts are considered funded if every eth account the Oracle owns is funded (has balance != 0)
-		allAccountsFunded := true
-		for _, account := range(oracleEthAccounts) {
-			allAccountsFunded = allAccountsFunded && (account.Attributes.EthBalance != "0")
+		if err := waitForRequestRun(extraOracleService, extraOracleJobIds[serviceId], requestTxHash, waitForJobCompletionPolls); err != nil {
+			return stacktrace.Propagate(err, "Oracle %v didn't fulfill its request.", serviceId)
 		}
-		ethAccountsFunded = ethAccountsFunded || allAccountsFunded
 	}
 	return nil
 }
 
 /*
-	Runs scripts on the contract deployer container which request data from the Oracle.
+	Requests data from the Oracle through the consumer contract without waiting for the Oracle to act on it,
+	returning the hash of the request transaction.
  */
-func (network *ChainlinkNetwork) RequestData() error {
-	if network.chainlinkOracleService == nil {
-		return stacktrace.NewError("Tried to request data before deploying the oracle service.")
+func (network *ChainlinkNetwork) SendDataRequest() (string, error) {
+	requestTxHash, err := network.sendDataRequestForJob(network.GetPriceFeedJobId())
+	if err != nil {
+		return "", stacktrace.Propagate(err, "An error occurred requesting data for the primary Oracle's job.")
 	}
-	if network.oracleContractAddress == "" {
-		return stacktrace.NewError("Tried to request data before deploying the oracle contract.")
+	return requestTxHash, nil
+}
+
+/*
+	Waits for the Oracle to complete the run for the request sent in the given transaction. The Oracle API is
+	expected to be unavailable while the Oracle's database is unhealthy, so errors getting runs are retried.
+ */
+func (network *ChainlinkNetwork) WaitForDataRequestFulfillment(requestTxHash string) error {
+	oracleService := network.GetChainlinkOracle()
+	if oracleService == nil {
+		return stacktrace.NewError("Tried to wait for a data request before deploying the oracle service.")
 	}
-	if network.linkContractDeployerService == nil {
-		return stacktrace.NewError("Tried to request data before deploying the link contract deployer service.")
+	if err := waitForRequestRun(oracleService, network.GetPriceFeedJobId(), requestTxHash, waitForFaultRecoveryPolls); err != nil {
+		return stacktrace.Propagate(err, "An error occurred waiting for the Oracle to fulfill request %v.", requestTxHash)
 	}
-	if network.priceFeedServer == nil {
-		return stacktrace.NewError("Tried to request data before deploying the in-network price feed server service.")
+	return nil
+}
+
+/*
+	Deploys the SimpleConsumer contract that cron and web initiated jobs write the price to.
+ */
+func (network *ChainlinkNetwork) DeploySimpleConsumer() error {
+	linkContractDeployerService := network.getLinkContractDeployer()
+	if linkContractDeployerService == nil {
+		return stacktrace.NewError("Tried to deploy the SimpleConsumer contract before deploying the $LINK contract.")
 	}
-	oracleEthAccounts, err := network.chainlinkOracleService.GetEthAccounts()
+	simpleConsumerAddress, err := linkContractDeployerService.DeploySimpleConsumerContract()
 	if err != nil {
-		return stacktrace.Propagate(err, "Error occurred requesting ethereum key information.")
+		return stacktrace.Propagate(err, "An error occurred deploying the SimpleConsumer contract.")
 	}
+	network.mutex.Lock()
+	network.simpleConsumerAddress = simpleConsumerAddress
+	network.mutex.Unlock()
+	logrus.Debugf("Deployed SimpleConsumer contract at %v", simpleConsumerAddress)
+	return nil
+}
 
-	for _, ethAccount := range oracleEthAccounts {
-		ethAddress := ethAccount.Attributes.Address
-		logrus.Infof("Setting permissions for address %v to run code from oracle contract %v.",
-			ethAddress,
-			network.oracleContractAddress)
-		err = network.linkContractDeployerService.SetFulfillmentPermissions(
-			network.GetBootstrapper().GetIPAddress(),
-			strconv.Itoa(network.GetBootstrapper().GetRpcPort()),
-			network.oracleContractAddress,
-			ethAddress,
-		)
-		if err != nil {
-			return stacktrace.Propagate(err, "Error occurred setting fulfillent permissions.")
-		}
+/*
+	Adds a job to the primary Oracle that writes the price to the SimpleConsumer contract on the given cron schedule,
+	returning the job's ID.
+ */
+func (network *ChainlinkNetwork) AddCronJob(schedule string) (string, error) {
+	jobId, err := network.addSimpleConsumerJob(func(priceFeedUrl string, simpleConsumerAddress string) chainlink_oracle.JobSpec {
+		return chainlink_oracle.NewCronJobSpec(schedule, priceFeedUrl, simpleConsumerAddress)
+	})
+	if err != nil {
+		return "", stacktrace.Propagate(err, "An error occurred adding a cron job with schedule '%v'.", schedule)
 	}
+	return jobId, nil
+}
 
-	logrus.Infof("Calling the Oracle contract to run job %v.", network.priceFeedJobId)
+/*
+	Adds a job to the primary Oracle that writes the price to the SimpleConsumer contract whenever it's run over the
+	operator API, returning the job's ID.
+ */
+func (network *ChainlinkNetwork) AddWebJob() (string, error) {
+	jobId, err := network.addSimpleConsumerJob(chainlink_oracle.NewWebJobSpec)
+	if err != nil {
+		return "", stacktrace.Propagate(err, "An error occurred adding a web initiated job.")
+	}
+	return jobId, nil
+}
 
-	priceFeedUrl := fmt.Sprintf("http://%v:%v/", network.priceFeedServer.GetIPAddress(), network.priceFeedServer.GetHTTPPort())
-	// Request data from the Oracle smart contract, starting a job.
-	err = network.linkContractDeployerService.RunRequestDataScript(network.oracleContractAddress, network.priceFeedJobId, priceFeedUrl)
+/*
+	Runs the web initiated job with the given ID on the primary Oracle, waiting for the run to complete.
+ */
+func (network *ChainlinkNetwork) RunWebJob(jobId string) (chainlink_oracle.Run, error) {
+	oracleService := network.GetChainlinkOracle()
+	if oracleService == nil {
+		return chainlink_oracle.Run{}, stacktrace.NewError("Tried to run a web job before deploying the oracle service.")
+	}
+	startedRun, err := oracleService.TriggerJobRun(jobId)
 	if err != nil {
-		return stacktrace.Propagate(err, "An error occurred requesting data from the Oracle contract on-chain.")
+		return chainlink_oracle.Run{}, stacktrace.Propagate(err, "An error occurred triggering a run of job %v.", jobId)
 	}
-	// Poll to see if the Oracle job has completed.
-	numPolls := 0
-	jobCompleted := false
-	for !jobCompleted && numPolls < waitForJobCompletionPolls {
-		time.Sleep(waitForJobCompletionTimeBetweenPolls)
-		runs, err := network.chainlinkOracleService.GetRuns()
+	isStartedRun := func(run chainlink_oracle.Run) bool {
+		return run.Attributes.Id == startedRun.Attributes.Id
+	}
+	completedRun, err := oracleService.WaitForRun(jobId, isStartedRun, waitForJobCompletionPolls)
+	if err != nil {
+		return chainlink_oracle.Run{}, stacktrace.Propagate(err, "Run %v of job %v didn't complete.", startedRun.Attributes.Id, jobId)
+	}
+	return completedRun, nil
+}
+
+/*
+	Waits until none of the runs of the given job on the primary Oracle are still in progress, returning them all. Only
+	meaningful for jobs that can't get new runs, e.g. archived ones.
+ */
+func (network *ChainlinkNetwork) WaitForJobRunsToFinish(jobId string) ([]chainlink_oracle.Run, error) {
+	oracleService := network.GetChainlinkOracle()
+	if oracleService == nil {
+		return nil, stacktrace.NewError("Tried to wait for job runs before deploying the oracle service.")
+	}
+	for numPolls := 0; numPolls < waitForJobRunsToFinishPolls; numPolls++ {
+		runs, err := oracleService.GetRunsForJob(jobId)
 		if err != nil {
-			return stacktrace.Propagate(err, "An error occurred getting data about job runs from the Oracle service.")
+			return nil, stacktrace.Propagate(err, "An error occurred getting the runs of job %v.", jobId)
 		}
-		for _, run := range(runs) {
-			// If the Oracle has a completed run with the same jobId as the priceFeed, job is complete.
-			if run.Attributes.JobId == network.priceFeedJobId {
-				jobCompleted = jobCompleted || run.Attributes.Status == jobCompletedStatus
+		numUnfinishedRuns := 0
+		for _, run := range runs {
+			if run.Attributes.Status != chainlink_oracle.RunStatusCompleted && run.Attributes.Status != chainlink_oracle.RunStatusErrored {
+				numUnfinishedRuns++
 			}
 		}
-		numPolls += 1
-	}
-	if !jobCompleted {
-		return stacktrace.NewError("Oracle job %v failed.", network.priceFeedJobId)
+		if numUnfinishedRuns == 0 {
+			return runs, nil
+		}
+		logrus.Debugf("Waiting for %v runs of job %v to finish.", numUnfinishedRuns, jobId)
+		time.Sleep(waitForJobRunsToFinishTimeBetweenPolls)
 	}
-	return nil
+	return nil, stacktrace.NewError("Runs of job %v were still in progress after %v polls.", jobId, waitForJobRunsToFinishPolls)
 }
 
-func (network *ChainlinkNetwork) AddBootstrapper() error {
-	if network.gethBootsrapperService != nil {
-		return stacktrace.NewError("Cannot add bootstrapper service to network; bootstrapper already exists!")
+/*
+	Reads the last value written to the SimpleConsumer contract and the number of writes it has had.
+ */
+func (network *ChainlinkNetwork) GetSimpleConsumerState() (value *big.Int, numWrites *big.Int, err error) {
+	simpleConsumerAddress := network.getSimpleConsumerAddress()
+	if simpleConsumerAddress == "" {
+		return nil, nil, stacktrace.NewError("Tried to read the SimpleConsumer contract before deploying it.")
 	}
-
-	initializer := geth.NewGethContainerInitializer(network.gethServiceImage, network.gethDataDirArtifactId, nil, true)
-	uncastedBootstrapper, checker, err := network.networkCtx.AddService(ethereumBootstrapperId, initializer)
+	value, err = network.callUint256Getter(simpleConsumerAddress, simpleConsumerValueSelector)
 	if err != nil {
-		return stacktrace.Propagate(err, "An error occurred adding the bootstrapper service")
+		return nil, nil, stacktrace.Propagate(err, "An error occurred reading the SimpleConsumer's value.")
 	}
-	if err := checker.WaitForStartup(waitForStartupTimeBetweenPolls, waitForStartupMaxNumPolls); err != nil {
-		return stacktrace.Propagate(err, "An error occurred waiting for the bootstrapper service to start")
+	numWrites, err = network.callUint256Getter(simpleConsumerAddress, simpleConsumerNumWritesSelector)
+	if err != nil {
+		return nil, nil, stacktrace.Propagate(err, "An error occurred reading the SimpleConsumer's number of writes.")
 	}
-	castedGethBootstrapperService := uncastedBootstrapper.(*geth.GethService)
-	network.gethBootsrapperService = castedGethBootstrapperService
-	return nil
+	return value, numWrites, nil
 }
 
-func (network *ChainlinkNetwork) AddPostgres() error {
-	if network.postgresService != nil {
-		return stacktrace.NewError("Cannot add postgres service to network; postgres service already exists!")
+/*
+	Deploys the EventEmitter contract that EthLog initiated jobs watch.
+ */
+func (network *ChainlinkNetwork) DeployEventEmitter() error {
+	linkContractDeployerService := network.getLinkContractDeployer()
+	if linkContractDeployerService == nil {
+		return stacktrace.NewError("Tried to deploy the EventEmitter contract before deploying the $LINK contract.")
 	}
-	initializer := postgres.NewPostgresContainerInitializer(network.postgresImage)
-	uncastedPostgres, checker, err := network.networkCtx.AddService(postgresId, initializer)
+	eventEmitterAddress, err := linkContractDeployerService.DeployEventEmitterContract()
 	if err != nil {
-		return stacktrace.Propagate(err, "An error occurred adding the postgres service")
-	}
-	if err := checker.WaitForStartup(waitForStartupTimeBetweenPolls, waitForStartupMaxNumPolls); err != nil {
-		return stacktrace.Propagate(err, "An error occurred waiting for the postgres service to start")
+		return stacktrace.Propagate(err, "An error occurred deploying the EventEmitter contract.")
 	}
-	castedPostgres := uncastedPostgres.(*postgres.PostgresService)
-	network.postgresService = castedPostgres
+	network.mutex.Lock()
+	network.eventEmitterAddress = eventEmitterAddress
+	network.mutex.Unlock()
+	logrus.Debugf("Deployed EventEmitter contract at %v", eventEmitterAddress)
 	return nil
 }
 
-func (network *ChainlinkNetwork) AddOracleService() error {
-	if network.linkContractAddress == "" {
-		return stacktrace.NewError("Tried to add an oracle service, but the $LINK token contract has not yet been deployed.")
+/*
+	Adds a job to the primary Oracle that runs once for every event the EventEmitter contract emits, returning the job's
+	ID.
+ */
+func (network *ChainlinkNetwork) AddEthLogJob() (string, error) {
+	oracleService := network.GetChainlinkOracle()
+	if oracleService == nil {
+		return "", stacktrace.NewError("Tried to add an EthLog job before deploying the oracle service.")
 	}
-	if network.oracleContractAddress == "" {
-		return stacktrace.NewError("Tried to add an oracle service, but the Oracle contract has not yet been deployed.")
+	eventEmitterAddress := network.getEventEmitterAddress()
+	if eventEmitterAddress == "" {
+		return "", stacktrace.NewError("Tried to add an EthLog job before deploying the EventEmitter contract.")
 	}
-	if network.chainlinkOracleService != nil {
-		return stacktrace.NewError("Tried to add an oracle service, but one has already been added!")
+	jobId, err := oracleService.CreateJob(chainlink_oracle.NewEthLogJobSpec(eventEmitterAddress))
+	if err != nil {
+		return "", stacktrace.Propagate(err, "An error occurred creating an EthLog job watching %v.", eventEmitterAddress)
+	}
+	return jobId, nil
+}
+
+/*
+	Has the EventEmitter contract emit an event with the given ID, as its indexed topic, and data, of at most 32 bytes,
+	waiting for the transaction to be mined.
+ */
+func (network *ChainlinkNetwork) EmitEvent(eventId uint64, data string) (*geth.TransactionReceipt, error) {
+	eventEmitterAddress := network.getEventEmitterAddress()
+	if eventEmitterAddress == "" {
+		return nil, stacktrace.NewError("Tried to emit an event before deploying the EventEmitter contract.")
 	}
-	initializer := chainlink_oracle.NewChainlinkOracleContainerInitializer(network.chainlinkOracleImage,
-		network.linkContractAddress, network.oracleContractAddress, network.gethBootsrapperService, network.postgresService)
-	uncastedChainlinkOracle, checker, err := network.networkCtx.AddService(chainlinkOracleId, initializer)
+	dataWord, err := geth.EncodeBytes32Word(data)
 	if err != nil {
-		return stacktrace.Propagate(err, "An error occurred adding the Chainlink Oracle service.")
+		return nil, stacktrace.Propagate(err, "An error occurred encoding the event data.")
 	}
-	if err := checker.WaitForStartup(waitForStartupTimeBetweenPolls, waitForStartupMaxNumPolls); err != nil {
-		return stacktrace.Propagate(err, "An error occurred waiting for an Oracle service to start up.")
+	// The bootstrapper keeps the first funded account unlocked
+	txHash, err := network.GetBootstrapper().SendRpcTransaction(geth.TransactionArgs{
+		From: contractOwnerAddress,
+		To:   eventEmitterAddress,
+		Gas:  geth.FormatHexQuantity(emitEventGasLimit),
+		Data: eventEmitterEmitEventSelector + geth.EncodeUint256Word(eventId) + dataWord,
+	})
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "An error occurred sending the transaction emitting event %v.", eventId)
 	}
-	castedChainlinkOracle := uncastedChainlinkOracle.(*chainlink_oracle.ChainlinkOracleService)
-	network.chainlinkOracleService = castedChainlinkOracle
-	return nil
+	receipt, err := network.waitForTransactionReceipt(txHash)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "An error occurred waiting for the transaction emitting event %v to be mined.", eventId)
+	}
+	if receipt.Status != geth.TransactionSucceededStatus {
+		return nil, stacktrace.NewError("The transaction emitting event %v reverted.", eventId)
+	}
+	return receipt, nil
 }
 
-func (network *ChainlinkNetwork) GetBootstrapper() *geth.GethService {
-	return network.gethBootsrapperService
+/*
+	Waits for the primary Oracle to complete the run of the given EthLog job for the log emitted in the given
+	transaction. Also returns the latest chain head at which the run was seen still waiting for incoming
+	confirmations, or 0 if it never was; the run completed at that head or a later one, which shows how many
+	confirmations the Oracle waited for at least.
+ */
+func (network *ChainlinkNetwork) WaitForLogRun(jobId string, txHash string) (chainlink_oracle.Run, uint64, error) {
+	oracleService := network.GetChainlinkOracle()
+	if oracleService == nil {
+		return chainlink_oracle.Run{}, 0, stacktrace.NewError("Tried to wait for a log run before deploying the oracle service.")
+	}
+	lastPendingHead := uint64(0)
+	for numPolls := 0; numPolls < waitForLogRunPolls; numPolls++ {
+		// Read before the runs, so a run seen pending was still pending once the chain reached this head
+		headBlock, err := network.GetBootstrapper().GetBlockNumber()
+		if err != nil {
+			return chainlink_oracle.Run{}, 0, stacktrace.Propagate(err, "An error occurred getting the chain head.")
+		}
+		runs, err := oracleService.GetRunsForJob(jobId)
+		if err != nil {
+			return chainlink_oracle.Run{}, 0, stacktrace.Propagate(err, "An error occurred getting the runs of job %v.", jobId)
+		}
+		for _, run := range runs {
+			if !strings.EqualFold(run.Attributes.RunRequest.TxHash, txHash) {
+				continue
+			}
+			switch run.Attributes.Status {
+			case chainlink_oracle.RunStatusCompleted:
+				return run, lastPendingHead, nil
+			case chainlink_oracle.RunStatusErrored:
+				return chainlink_oracle.Run{}, 0, stacktrace.NewError("Run %v for the log in transaction %v errored.", run.Attributes.Id, txHash)
+			case chainlink_oracle.RunStatusPendingIncomingConfirmations:
+				lastPendingHead = headBlock
+			}
+			logrus.Debugf("Run %v for the log in transaction %v is '%v' at block %v.", run.Attributes.Id, txHash, run.Attributes.Status, headBlock)
+		}
+		time.Sleep(waitForLogRunTimeBetweenPolls)
+	}
+	return chainlink_oracle.Run{}, 0, stacktrace.NewError("Job %v didn't complete a run for the log in transaction %v after %v polls.",
+		jobId, txHash, waitForLogRunPolls)
 }
 
-func (network *ChainlinkNetwork) GetLinkContractAddress() string {
-	return network.linkContractAddress
+/*
+	Deploys another consumer contract, which has no $LINK to pay for requests with, returning its address.
+ */
+func (network *ChainlinkNetwork) DeployUnfundedConsumer() (string, error) {
+	linkContractDeployerService := network.getLinkContractDeployer()
+	linkContractAddress := network.GetLinkContractAddress()
+	if linkContractDeployerService == nil || linkContractAddress == "" {
+		return "", stacktrace.NewError("Tried to deploy a consumer contract before deploying the $LINK contract.")
+	}
+	consumerAddress, err := linkContractDeployerService.DeployConsumerContract(linkContractAddress)
+	if err != nil {
+		return "", stacktrace.Propagate(err, "An error occurred deploying an unfunded consumer contract.")
+	}
+	return consumerAddress, nil
 }
 
-func (network *ChainlinkNetwork) GetChainlinkOracle() *chainlink_oracle.ChainlinkOracleService {
-	return network.chainlinkOracleService
+/*
+	Requests data from the primary Oracle's price feed job through the consumer contract at the given address (or the
+	one the migrations deployed, if empty), paying the given $LINK in juels. Returns the hash of the request
+	transaction, or isReverted if the consumer couldn't make the request, e.g. because it can't afford the payment.
+ */
+func (network *ChainlinkNetwork) SendDataRequestWithPayment(consumerAddress string, paymentJuels string) (txHash string, isReverted bool, err error) {
+	oracleContractAddress := network.GetOracleContractAddress()
+	linkContractDeployerService := network.getLinkContractDeployer()
+	if oracleContractAddress == "" || linkContractDeployerService == nil {
+		return "", false, stacktrace.NewError("Tried to request data before deploying the oracle contract.")
+	}
+	priceFeedUrl := network.getPriceFeedUrl()
+	priceFeedJobId := network.GetPriceFeedJobId()
+	if priceFeedUrl == "" || priceFeedJobId == "" {
+		return "", false, stacktrace.NewError("Tried to request data before deploying the price feed server and its Oracle job.")
+	}
+	if err := network.setFulfillmentPermissions(); err != nil {
+		return "", false, stacktrace.Propagate(err, "Error occurred setting fulfillment permissions.")
+	}
+	logrus.Infof("Requesting data from Oracle job %v, paying %v juels.", priceFeedJobId, paymentJuels)
+	txHash, isReverted, err = linkContractDeployerService.RequestDataWithPayment(consumerAddress, oracleContractAddress,
+		priceFeedJobId, priceFeedUrl, paymentJuels)
+	if err != nil {
+		return "", false, stacktrace.Propagate(err, "An error occurred requesting data paying %v juels.", paymentJuels)
+	}
+	return txHash, isReverted, nil
 }
 
-func (network *ChainlinkNetwork) AddPriceFeedServer() error {
-	initializer := price_feed_server.NewPriceFeedServerInitializer(network.priceFeedServerImage)
-	uncastedPriceFeedServer, checker, err := network.networkCtx.AddService(priceFeedServerId, initializer)
-	if err != nil {
-		return stacktrace.Propagate(err, "An error occurred adding the price feed server.")
+/*
+	Waits for the primary Oracle's run for the request sent in the given transaction to finish, returning it whether it
+	completed or errored, e.g. because the Oracle rejected the request.
+ */
+func (network *ChainlinkNetwork) WaitForRequestRunToFinish(requestTxHash string) (chainlink_oracle.Run, error) {
+	oracleService := network.GetChainlinkOracle()
+	if oracleService == nil {
+		return chainlink_oracle.Run{}, stacktrace.NewError("Tried to wait for a request run before deploying the oracle service.")
 	}
-	if err := checker.WaitForStartup(waitForStartupTimeBetweenPolls, waitForStartupMaxNumPolls); err != nil {
-		return stacktrace.Propagate(err, "An error occurred waiting for the price feed server to start")
+	priceFeedJobId := network.GetPriceFeedJobId()
+	for numPolls := 0; numPolls < waitForJobCompletionPolls; numPolls++ {
+		runs, err := oracleService.GetRunsForJob(priceFeedJobId)
+		if err != nil {
+			return chainlink_oracle.Run{}, stacktrace.Propagate(err, "An error occurred getting the runs of job %v.", priceFeedJobId)
+		}
+		for _, run := range runs {
+			isRequestRun := strings.EqualFold(run.Attributes.RunRequest.TxHash, requestTxHash)
+			isFinished := run.Attributes.Status == chainlink_oracle.RunStatusCompleted || run.Attributes.Status == chainlink_oracle.RunStatusErrored
+			if isRequestRun && isFinished {
+				return run, nil
+			}
+		}
+		time.Sleep(waitForJobRunsToFinishTimeBetweenPolls)
 	}
-	castedPriceFeedServer := uncastedPriceFeedServer.(*price_feed_server.PriceFeedServer)
-	network.priceFeedServer = castedPriceFeedServer
-	return nil
+	return chainlink_oracle.Run{}, stacktrace.NewError("Oracle didn't finish a run for request %v after %v polls.",
+		requestTxHash, waitForJobCompletionPolls)
 }
 
-func (network *ChainlinkNetwork) AddGethService() (services.ServiceID, error) {
-	if (network.gethBootsrapperService == nil) {
-		return "", stacktrace.NewError("Cannot add ethereum node to network; no bootstrap node exists")
+/*
+	Sets whether the price feed server answers with errors instead of the price, which keeps the Oracle's price feed
+	runs from fulfilling their requests.
+ */
+func (network *ChainlinkNetwork) SetPriceFeedFailing(isFailing bool) error {
+	priceFeedServer := network.getPriceFeedServer()
+	if priceFeedServer == nil {
+		return stacktrace.NewError("Tried to make the price feed fail before deploying the in-network price feed server service.")
 	}
+	if err := priceFeedServer.SetFailing(isFailing); err != nil {
+		return stacktrace.Propagate(err, "An error occurred setting whether the price feed server fails.")
+	}
+	logrus.Infof("Set the price feed server failing: %v", isFailing)
+	return nil
+}
 
-	serviceIdStr := gethServiceIdPrefix + strconv.Itoa(network.nextGethServiceId)
-	network.nextGethServiceId = network.nextGethServiceId + 1
-	serviceId := services.ServiceID(serviceIdStr)
-
-	initializer := geth.NewGethContainerInitializer(network.gethServiceImage, network.gethDataDirArtifactId, network.gethBootsrapperService, false)
-	uncastedGethService, checker, err := network.networkCtx.AddService(serviceId, initializer)
+/*
+	Gets the request made to the Oracle contract in the given transaction, waiting for it to be mined.
+ */
+func (network *ChainlinkNetwork) GetOracleRequest(requestTxHash string) (OracleRequest, error) {
+	oracleContractAddress := network.GetOracleContractAddress()
+	if oracleContractAddress == "" {
+		return OracleRequest{}, stacktrace.NewError("Tried to get an Oracle request before deploying the oracle contract.")
+	}
+	receipt, err := network.waitForTransactionReceipt(requestTxHash)
 	if err != nil {
-		return "", stacktrace.Propagate(err, "An error occurred adding the ethereum node")
+		return OracleRequest{}, stacktrace.Propagate(err, "An error occurred waiting for request transaction %v to be mined.", requestTxHash)
 	}
-	if err := checker.WaitForStartup(waitForStartupTimeBetweenPolls, waitForStartupMaxNumPolls); err != nil {
-		return "", stacktrace.Propagate(err, "An error occurred waiting for the ethereum node to start")
+	request, err := parseOracleRequest(receipt, oracleContractAddress)
+	if err != nil {
+		return OracleRequest{}, stacktrace.Propagate(err, "An error occurred parsing the Oracle request in transaction %v.", requestTxHash)
 	}
-	castedGethService := uncastedGethService.(*geth.GethService)
-
-	network.gethServices[serviceId] = castedGethService
-	return serviceId, nil
+	return request, nil
 }
 
-func (network *ChainlinkNetwork) ManuallyConnectPeers() error {
-	allServices := map[services.ServiceID]*geth.GethService{
-		ethereumBootstrapperId: network.gethBootsrapperService,
+/*
+	Gets the $LINK balance of the given address, in juels.
+ */
+func (network *ChainlinkNetwork) GetLinkBalance(address string) (*big.Int, error) {
+	linkContractAddress := network.GetLinkContractAddress()
+	if linkContractAddress == "" {
+		return nil, stacktrace.NewError("Tried to get a $LINK balance before deploying the $LINK contract.")
 	}
-	for id, service := range network.gethServices {
-		allServices[id] = service
+	addressWord, err := geth.EncodeAddressWord(address)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "An error occurred encoding address %v.", address)
+	}
+	balance, err := network.callUint256Getter(linkContractAddress, linkBalanceOfSelector + addressWord)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "An error occurred getting the $LINK balance of %v.", address)
 	}
+	return balance, nil
+}
 
-	// Connect all nodes to each other
-	for nodeId, nodeGethService := range allServices {
-		for peerId, peerGethService := range allServices {
-			if nodeId == peerId {
-				continue
-			}
-			peerGethServiceEnode, err := peerGethService.GetEnodeAddress()
+/*
+	Gets the $LINK balances of the consumer contract and the Oracle contract from the chain, and of the Oracle nodes'
+	keys as the nodes report them.
+ */
+func (network *ChainlinkNetwork) GetLinkBalances() (LinkBalances, error) {
+	consumerContractAddress := network.GetConsumerContractAddress()
+	oracleContractAddress := network.GetOracleContractAddress()
+	if consumerContractAddress == "" || oracleContractAddress == "" {
+		return LinkBalances{}, stacktrace.NewError("Tried to get $LINK balances before deploying the contracts.")
+	}
+	consumerBalance, err := network.GetLinkBalance(consumerContractAddress)
+	if err != nil {
+		return LinkBalances{}, stacktrace.Propagate(err, "An error occurred getting the consumer contract's $LINK balance.")
+	}
+	oracleContractBalance, err := network.GetLinkBalance(oracleContractAddress)
+	if err != nil {
+		return LinkBalances{}, stacktrace.Propagate(err, "An error occurred getting the Oracle contract's $LINK balance.")
+	}
+	oracleNodeKeyBalances := map[string]*big.Int{}
+	for serviceId, oracleService := range network.GetOracleServices() {
+		ethKeys, err := oracleService.GetEthAccounts()
+		if err != nil {
+			return LinkBalances{}, stacktrace.Propagate(err, "An error occurred getting the keys of Oracle %v.", serviceId)
+		}
+		for _, ethKey := range ethKeys {
+			keyBalance, err := ethKey.GetLinkBalance()
+			if err != nil {
+				return LinkBalances{}, stacktrace.Propagate(err, "Oracle %v reported an invalid $LINK balance.", serviceId)
+			}
+			oracleNodeKeyBalances[ethKey.Attributes.Address] = keyBalance
+		}
+	}
+	return LinkBalances{
+		Consumer:       consumerBalance,
+		OracleContract: oracleContractBalance,
+		OracleNodeKeys: oracleNodeKeyBalances,
+	}, nil
+}
+
+/*
+	Gets how much $LINK, in juels, the Oracle contract's owner can withdraw: the payments for fulfilled requests that
+	haven't been withdrawn yet.
+ */
+func (network *ChainlinkNetwork) GetOracleWithdrawable() (*big.Int, error) {
+	oracleContractAddress := network.GetOracleContractAddress()
+	if oracleContractAddress == "" {
+		return nil, stacktrace.NewError("Tried to get the Oracle contract's withdrawable $LINK before deploying it.")
+	}
+	returnData, err := network.GetBootstrapper().CallContractFrom(contractOwnerAddress, oracleContractAddress, oracleWithdrawableSelector)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "An error occurred calling withdrawable on the Oracle contract.")
+	}
+	withdrawable, err := geth.ParseHexUint256(returnData)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "The Oracle contract returned a withdrawable amount that isn't a uint256.")
+	}
+	return withdrawable, nil
+}
+
+/*
+	Withdraws the given $LINK, in juels, from the Oracle contract to the given address as the contract's owner. Returns
+	the receipt of the withdrawing transaction, which reverted if the Oracle contract rejected the withdrawal, e.g.
+	because it's more than is withdrawable.
+ */
+func (network *ChainlinkNetwork) WithdrawFromOracle(recipientAddress string, amountJuels *big.Int) (*geth.TransactionReceipt, error) {
+	oracleContractAddress := network.GetOracleContractAddress()
+	if oracleContractAddress == "" {
+		return nil, stacktrace.NewError("Tried to withdraw from the Oracle contract before deploying it.")
+	}
+	recipientWord, err := geth.EncodeAddressWord(recipientAddress)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "An error occurred encoding recipient address %v.", recipientAddress)
+	}
+	callData := oracleWithdrawSelector + recipientWord + geth.EncodeBigUint256Word(amountJuels)
+	logrus.Infof("Withdrawing %v juels from the Oracle contract to %v.", amountJuels, recipientAddress)
+	receipt, err := network.sendContractTransaction(oracleContractAddress, callData)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "An error occurred withdrawing %v juels from the Oracle contract.", amountJuels)
+	}
+	return receipt, nil
+}
+
+/*
+	Gets the ETH balance of the given address, in wei.
+ */
+func (network *ChainlinkNetwork) GetEthBalance(address string) (*big.Int, error) {
+	balance, err := network.GetBootstrapper().GetBalance(address)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "An error occurred getting the ETH balance of %v.", address)
+	}
+	return balance, nil
+}
+
+/*
+	Imports one of the accounts prefunded in the genesis block into the primary Oracle, which starts sending
+	transactions from it alongside its other keys. The account has to be one of the topology's OracleImportedAccounts,
+	which topology validation keeps clear of every other role, since the nonces of anything else sending transactions
+	from it would clash with the Oracle's.
+ */
+func (network *ChainlinkNetwork) ImportGethAccountIntoOracle(address string) error {
+	oracleService := network.GetChainlinkOracle()
+	if oracleService == nil {
+		return stacktrace.NewError("Tried to import an account into the Oracle before deploying the oracle service.")
+	}
+	if !containsAddress(network.topology.OracleImportedAccounts, address) {
+		return stacktrace.NewError("Tried to import account %v into the Oracle, but it isn't one of the topology's Oracle imported accounts %v.",
+			address, network.topology.OracleImportedAccounts)
+	}
+	keystoreJson, err := network.GetBootstrapper().GetKeystoreJson(address)
+	if err != nil {
+		return stacktrace.Propagate(err, "An error occurred getting the keystore of account %v.", address)
+	}
+	if _, err := oracleService.ImportEthKey(keystoreJson, geth.PrivateKeyPassword); err != nil {
+		return stacktrace.Propagate(err, "An error occurred importing account %v into the Oracle.", address)
+	}
+	logrus.Infof("Imported account %v into the Oracle.", address)
+	return nil
+}
+
+/*
+	Whether the Oracle contract allows the given address to fulfill requests.
+ */
+func (network *ChainlinkNetwork) IsAuthorizedOracleNode(address string) (bool, error) {
+	oracleContractAddress := network.GetOracleContractAddress()
+	if oracleContractAddress == "" {
+		return false, stacktrace.NewError("Tried to check the Oracle contract's authorized nodes before deploying it.")
+	}
+	addressWord, err := geth.EncodeAddressWord(address)
+	if err != nil {
+		return false, stacktrace.Propagate(err, "An error occurred encoding address %v.", address)
+	}
+	isAuthorized, err := network.callUint256Getter(oracleContractAddress, oracleGetAuthorizationStatusSelector + addressWord)
+	if err != nil {
+		return false, stacktrace.Propagate(err, "An error occurred getting the authorization status of %v.", address)
+	}
+	// The contract returns a bool, which is ABI-encoded as a uint256 of 0 or 1
+	return isAuthorized.Sign() != 0, nil
+}
+
+/*
+	Gets the transaction that a completed run of the primary Oracle's price feed job sent to fulfill its request.
+ */
+func (network *ChainlinkNetwork) GetFulfillmentTransaction(run chainlink_oracle.Run) (*geth.Transaction, error) {
+	fulfillmentTxHash, err := getFulfillmentTxHash(run)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "Couldn't get the fulfillment transaction of run %v.", run.Attributes.Id)
+	}
+	transaction, err := network.GetBootstrapper().GetTransactionByHash(fulfillmentTxHash)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "An error occurred getting fulfillment transaction %v.", fulfillmentTxHash)
+	}
+	return transaction, nil
+}
+
+/*
+	Waits for the chain to produce a block with a timestamp at or after the given Unix time, e.g. a request's cancel
+	expiration.
+ */
+func (network *ChainlinkNetwork) WaitForBlockTimestamp(unixTime uint64) error {
+	bootstrapper := network.GetBootstrapper()
+	for numPolls := 0; numPolls < waitForBlockTimestampPolls; numPolls++ {
+		headBlockNumber, err := bootstrapper.GetBlockNumber()
+		if err != nil {
+			return stacktrace.Propagate(err, "An error occurred getting the chain head.")
+		}
+		_, headBlockTime, err := network.getBlockNumberAndTime(geth.FormatHexQuantity(headBlockNumber))
+		if err != nil {
+			return stacktrace.Propagate(err, "An error occurred getting the time of block %v.", headBlockNumber)
+		}
+		if uint64(headBlockTime.Unix()) >= unixTime {
+			return nil
+		}
+		time.Sleep(waitForBlockTimestampTimeBetweenPolls)
+	}
+	return stacktrace.NewError("The chain didn't reach Unix time %v after %v polls with %v between polls.",
+		unixTime, waitForBlockTimestampPolls, waitForBlockTimestampTimeBetweenPolls)
+}
+
+/*
+	Has the requester cancel the given request, which the Oracle contract only allows once the request has expired,
+	refunding its payment to the requester. Returns the receipt of the cancelling transaction, which reverted if the
+	cancellation was rejected.
+ */
+func (network *ChainlinkNetwork) CancelRequest(request OracleRequest) (*geth.TransactionReceipt, error) {
+	requestIdWord, err := geth.EncodeFixedBytesWord(request.RequestId)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "An error occurred encoding the ID of request %v.", request.RequestId)
+	}
+	callbackFunctionIdWord, err := geth.EncodeFixedBytesWord(request.CallbackFunctionId)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "An error occurred encoding the callback function ID of request %v.", request.RequestId)
+	}
+	callData := consumerCancelRequestSelector +
+		requestIdWord +
+		geth.EncodeBigUint256Word(request.Payment) +
+		callbackFunctionIdWord +
+		geth.EncodeUint256Word(request.CancelExpiration)
+	logrus.Infof("Cancelling request %v through its requester %v.", request.RequestId, request.Requester)
+	receipt, err := network.sendContractTransaction(request.Requester, callData)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "An error occurred cancelling request %v.", request.RequestId)
+	}
+	return receipt, nil
+}
+
+/*
+	Fulfills the given request with the given value directly on the Oracle contract, as the contract's owner rather
+	than the Oracle node. Returns the receipt of the fulfilling transaction, which reverted if the Oracle contract
+	rejected the fulfillment, e.g. because the request was already fulfilled or cancelled.
+ */
+func (network *ChainlinkNetwork) FulfillRequest(request OracleRequest, value uint64) (*geth.TransactionReceipt, error) {
+	oracleContractAddress := network.GetOracleContractAddress()
+	if oracleContractAddress == "" {
+		return nil, stacktrace.NewError("Tried to fulfill a request before deploying the oracle contract.")
+	}
+	requestIdWord, err := geth.EncodeFixedBytesWord(request.RequestId)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "An error occurred encoding the ID of request %v.", request.RequestId)
+	}
+	callbackAddressWord, err := geth.EncodeAddressWord(request.CallbackAddress)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "An error occurred encoding the callback address of request %v.", request.RequestId)
+	}
+	callbackFunctionIdWord, err := geth.EncodeFixedBytesWord(request.CallbackFunctionId)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "An error occurred encoding the callback function ID of request %v.", request.RequestId)
+	}
+	callData := oracleFulfillOracleRequestSelector +
+		requestIdWord +
+		geth.EncodeBigUint256Word(request.Payment) +
+		callbackAddressWord +
+		callbackFunctionIdWord +
+		geth.EncodeUint256Word(request.CancelExpiration) +
+		geth.EncodeUint256Word(value)
+	logrus.Infof("Fulfilling request %v with %v as the Oracle contract's owner.", request.RequestId, value)
+	receipt, err := network.sendContractTransaction(oracleContractAddress, callData)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "An error occurred fulfilling request %v.", request.RequestId)
+	}
+	return receipt, nil
+}
+
+/*
+	Freezes the Oracle's Postgres service; the Oracle keeps its connections but gets no answers until it's resumed.
+ */
+func (network *ChainlinkNetwork) PauseOracleDatabase() error {
+	postgresService := network.getPostgres()
+	if postgresService == nil {
+		return stacktrace.NewError("Tried to pause the Oracle's database before adding the postgres service.")
+	}
+	logrus.Infof("Pausing the Oracle's database.")
+	if err := postgresService.Pause(); err != nil {
+		return stacktrace.Propagate(err, "An error occurred pausing the Oracle's database.")
+	}
+	return nil
+}
+
+func (network *ChainlinkNetwork) ResumeOracleDatabase() error {
+	postgresService := network.getPostgres()
+	if postgresService == nil {
+		return stacktrace.NewError("Tried to resume the Oracle's database before adding the postgres service.")
+	}
+	logrus.Infof("Resuming the Oracle's database.")
+	if err := postgresService.Resume(); err != nil {
+		return stacktrace.Propagate(err, "An error occurred resuming the Oracle's database.")
+	}
+	return nil
+}
+
+/*
+	Restarts the Oracle's Postgres service, dropping all of the Oracle's connections, and waits for it to come back.
+ */
+func (network *ChainlinkNetwork) RestartOracleDatabase() error {
+	postgresService := network.getPostgres()
+	if postgresService == nil {
+		return stacktrace.NewError("Tried to restart the Oracle's database before adding the postgres service.")
+	}
+	logrus.Infof("Restarting the Oracle's database.")
+	if err := postgresService.Restart(); err != nil {
+		return stacktrace.Propagate(err, "An error occurred restarting the Oracle's database.")
+	}
+	return nil
+}
+
+/*
+	Kills the Oracle's open database connections with pg_terminate_backend, returning how many were killed.
+ */
+func (network *ChainlinkNetwork) TerminateOracleDatabaseConnections() (int64, error) {
+	postgresService, databaseName := network.getOracleDatabase()
+	if postgresService == nil || databaseName == "" {
+		return 0, stacktrace.NewError("Tried to terminate the Oracle's database connections before its database was created.")
+	}
+	numTerminated, err := postgresService.TerminateConnections(databaseName)
+	if err != nil {
+		return 0, stacktrace.Propagate(err, "An error occurred terminating the Oracle's database connections.")
+	}
+	logrus.Infof("Terminated %v of the Oracle's database connections.", numTerminated)
+	return numTerminated, nil
+}
+
+/*
+	Routes the Oracle's database traffic through a proxy in the testsuite, so latency can be added with
+	SetOracleDatabaseLatency. Must be called before the Oracle is added.
+ */
+func (network *ChainlinkNetwork) EnableOracleDatabaseProxy() error {
+	postgresService := network.getPostgres()
+	if postgresService == nil {
+		return stacktrace.NewError("Tried to enable the Oracle database proxy before adding the postgres service.")
+	}
+	oracleDatabaseProxy := postgres.NewLatencyProxy(postgresService.GetIPAddress(), postgresService.GetPort())
+
+	// Checked once the service has been called, since the Oracle or another proxy could have been added meanwhile
+	network.mutex.Lock()
+	defer network.mutex.Unlock()
+	if network.chainlinkOracleService != nil {
+		return stacktrace.NewError("Tried to enable the Oracle database proxy after the Oracle was added.")
+	}
+	if network.oracleDatabaseProxy != nil {
+		return stacktrace.NewError("Tried to enable the Oracle database proxy, but it's already enabled.")
+	}
+	network.oracleDatabaseProxy = oracleDatabaseProxy
+	return nil
+}
+
+/*
+	Delays every chunk of traffic between the Oracle and its database by the given duration; zero removes the delay.
+ */
+func (network *ChainlinkNetwork) SetOracleDatabaseLatency(latency time.Duration) error {
+	network.mutex.RLock()
+	oracleDatabaseProxy := network.oracleDatabaseProxy
+	network.mutex.RUnlock()
+	if oracleDatabaseProxy == nil {
+		return stacktrace.NewError("Tried to set the Oracle's database latency without enabling the Oracle database proxy.")
+	}
+	logrus.Infof("Setting the Oracle's database latency to %v.", latency)
+	oracleDatabaseProxy.SetLatency(latency)
+	return nil
+}
+
+/*
+	Stops the primary Oracle and starts the given image in its place against the same database, which holds the
+	Oracle's jobs, runs and keystore, so the new image picks up where the old one left off after running whatever
+	database migrations it needs. Extra Oracles are left on their images.
+ */
+func (network *ChainlinkNetwork) UpgradeOracle(image string) error {
+	network.mutex.Lock()
+	oldOracleService := network.chainlinkOracleService
+	oldOracleServiceId := network.chainlinkOracleServiceId
+	databaseCredentials := network.oracleDatabaseCredentials
+	isProxyEnabled := network.oracleDatabaseProxy != nil
+	// Only counted once the upgrade succeeds, so a failed upgrade doesn't leave a gap in the IDs
+	numOracleUpgrades := network.numOracleUpgrades + 1
+	upgradedOracleId := services.ServiceID(upgradedOracleIdPrefix + strconv.Itoa(numOracleUpgrades))
+	network.mutex.Unlock()
+	if oldOracleService == nil {
+		return stacktrace.NewError("Tried to upgrade the Oracle before adding it.")
+	}
+	if isProxyEnabled {
+		return stacktrace.NewError("Upgrading the Oracle isn't supported when its database traffic goes through the proxy.")
+	}
+
+	logrus.Infof("Stopping Oracle service %v to upgrade it to image '%v'.", oldOracleServiceId, image)
+	if err := network.networkCtx.RemoveService(oldOracleServiceId, oracleStopTimeoutSeconds); err != nil {
+		return stacktrace.Propagate(err, "An error occurred stopping Oracle service %v.", oldOracleServiceId)
+	}
+	network.mutex.Lock()
+	network.chainlinkOracleService = nil
+	network.mutex.Unlock()
+
+	// The Oracle only finishes starting up once its migrations have run, so startup failing covers failed migrations
+	upgradedOracleService, err := network.addOracle(upgradedOracleId, image, databaseCredentials)
+	if err != nil {
+		return stacktrace.Propagate(err, "An error occurred starting the upgraded Oracle on image '%v'.", image)
+	}
+	network.mutex.Lock()
+	network.chainlinkOracleService = upgradedOracleService
+	network.chainlinkOracleServiceId = upgradedOracleId
+	network.numOracleUpgrades = numOracleUpgrades
+	metricsCollector := network.metricsCollector
+	network.mutex.Unlock()
+
+	// The upgraded Oracle has an address of its own, and its metrics carry on under the Oracle's target
+	if metricsCollector != nil {
+		if err := metricsCollector.SetTargetUrl(string(chainlinkOracleId), upgradedOracleService.GetMetricsUrl()); err != nil {
+			return stacktrace.Propagate(err, "An error occurred pointing Oracle metrics collection at the upgraded Oracle.")
+		}
+	}
+	return nil
+}
+
+/*
+	The IDs of the database migrations the Oracle has applied, in order.
+ */
+func (network *ChainlinkNetwork) GetOracleDatabaseMigrations() ([]string, error) {
+	rows, err := network.QueryOracleDatabase(oracleMigrationIdsQuery)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "An error occurred querying the Oracle's applied migrations.")
+	}
+	return getRowStrings(rows, "id"), nil
+}
+
+/*
+	The IDs of the jobs in the Oracle's database that haven't been archived, in order.
+ */
+func (network *ChainlinkNetwork) GetOracleDatabaseJobIds() ([]string, error) {
+	rows, err := network.QueryOracleDatabase(oracleJobIdsQuery)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "An error occurred querying the Oracle's jobs.")
+	}
+	return getRowStrings(rows, "id"), nil
+}
+
+/*
+	Starts sending transaction load to the geth nodes in the background, to congest blocks while the test runs.
+ */
+func (network *ChainlinkNetwork) StartTransactionLoad(targetTransactionsPerSecond int, gasPerTransaction uint64) error {
+	gethBootstrapperService := network.GetBootstrapper()
+	if gethBootstrapperService == nil {
+		return stacktrace.NewError("Tried to start transaction load before adding any ethereum nodes.")
+	}
+	if network.IsGeneratingTransactionLoad() {
+		return stacktrace.NewError("Tried to start transaction load, but load is already being generated.")
+	}
+	targetNodes := []geth.GethNode{}
+	for _, gethService := range network.GetGethServices() {
+		targetNodes = append(targetNodes, gethService)
+	}
+	if len(targetNodes) == 0 {
+		targetNodes = append(targetNodes, gethBootstrapperService)
+	}
+	config := load_generator.TransactionLoadConfig{
+		TargetTransactionsPerSecond: targetTransactionsPerSecond,
+		GasPerTransaction:           gasPerTransaction,
+		SenderAddresses:             loadSenderAddresses,
+		RecipientAddress:            loadRecipientAddress,
+	}
+	generator, err := load_generator.NewTransactionLoadGenerator(config, targetNodes)
+	if err != nil {
+		return stacktrace.Propagate(err, "An error occurred creating the transaction load generator.")
+	}
+
+	if err := generator.Start(); err != nil {
+		return stacktrace.Propagate(err, "An error occurred starting the transaction load generator.")
+	}
+
+	// Another caller may have started load while this generator was starting, in which case theirs wins
+	network.mutex.Lock()
+	if network.transactionLoadGenerator != nil {
+		network.mutex.Unlock()
+		if _, err := generator.Stop(); err != nil {
+			logrus.Warnf("Couldn't stop a redundant transaction load generator: %v", err)
+		}
+		return stacktrace.NewError("Tried to start transaction load, but load is already being generated.")
+	}
+	network.transactionLoadGenerator = generator
+	network.mutex.Unlock()
+	return nil
+}
+
+func (network *ChainlinkNetwork) StopTransactionLoad() (load_generator.TransactionLoadStats, error) {
+	network.mutex.Lock()
+	generator := network.transactionLoadGenerator
+	network.transactionLoadGenerator = nil
+	network.mutex.Unlock()
+	if generator == nil {
+		return load_generator.TransactionLoadStats{}, stacktrace.NewError("Tried to stop transaction load, but none is being generated.")
+	}
+	stats, err := generator.Stop()
+	if err != nil {
+		return load_generator.TransactionLoadStats{}, stacktrace.Propagate(err, "An error occurred stopping the transaction load generator.")
+	}
+	return stats, nil
+}
+
+func (network *ChainlinkNetwork) IsGeneratingTransactionLoad() bool {
+	network.mutex.RLock()
+	defer network.mutex.RUnlock()
+	return network.transactionLoadGenerator != nil
+}
+
+/*
+	Returns the average fraction of the block gas limit used by the blocks in the given (inclusive) range.
+ */
+func (network *ChainlinkNetwork) GetAverageBlockGasUtilization(fromBlock uint64, toBlock uint64) (float64, error) {
+	gethBootstrapperService := network.GetBootstrapper()
+	if gethBootstrapperService == nil {
+		return 0, stacktrace.NewError("Tried to inspect blocks before adding any ethereum nodes.")
+	}
+	if toBlock < fromBlock {
+		return 0, stacktrace.NewError("Block range end %v is before its start %v", toBlock, fromBlock)
+	}
+	totalUtilization := 0.0
+	for blockNumber := fromBlock; blockNumber <= toBlock; blockNumber++ {
+		block, err := gethBootstrapperService.GetBlockByNumber(blockNumber)
+		if err != nil {
+			return 0, stacktrace.Propagate(err, "An error occurred getting block %v", blockNumber)
+		}
+		gasUsed, err := geth.ParseHexQuantity(block.GasUsed)
+		if err != nil {
+			return 0, stacktrace.Propagate(err, "An error occurred parsing the gas used by block %v", blockNumber)
+		}
+		gasLimit, err := geth.ParseHexQuantity(block.GasLimit)
+		if err != nil {
+			return 0, stacktrace.Propagate(err, "An error occurred parsing the gas limit of block %v", blockNumber)
+		}
+		totalUtilization += float64(gasUsed) / float64(gasLimit)
+	}
+	return totalUtilization / float64(toBlock - fromBlock + 1), nil
+}
+
+/*
+	Fires the given number of concurrent requests at the Oracle through the consumer contract, and records when each
+	request and its fulfillment landed on-chain and what they cost.
+ */
+func (network *ChainlinkNetwork) BenchmarkFulfillment(numRequests int) ([]benchmarking.FulfillmentSample, error) {
+	oracleService := network.GetChainlinkOracle()
+	if oracleService == nil {
+		return nil, stacktrace.NewError("Tried to benchmark fulfillment before deploying the oracle service.")
+	}
+	linkContractDeployerService := network.getLinkContractDeployer()
+	if linkContractDeployerService == nil {
+		return nil, stacktrace.NewError("Tried to benchmark fulfillment before deploying the link contract deployer service.")
+	}
+	priceFeedUrl := network.getPriceFeedUrl()
+	priceFeedJobId := network.GetPriceFeedJobId()
+	if priceFeedUrl == "" || priceFeedJobId == "" {
+		return nil, stacktrace.NewError("Tried to benchmark fulfillment before deploying the price feed server and its Oracle job.")
+	}
+	if numRequests <= 0 {
+		return nil, stacktrace.NewError("Number of benchmark requests must be positive, but was %v", numRequests)
+	}
+	if err := network.setFulfillmentPermissions(); err != nil {
+		return nil, stacktrace.Propagate(err, "Error occurred setting fulfillment permissions.")
+	}
+
+	payment, ok := new(big.Int).SetString(chainlink_contract_deployer.DefaultLinkPaymentJuels, 10)
+	if !ok {
+		return nil, stacktrace.NewError("Couldn't parse the default $LINK payment '%v'", chainlink_contract_deployer.DefaultLinkPaymentJuels)
+	}
+	totalPayment := new(big.Int).Mul(payment, big.NewInt(int64(numRequests)))
+	if err := linkContractDeployerService.FundLinkWalletContractWithAmount(totalPayment.String()); err != nil {
+		return nil, stacktrace.Propagate(err, "An error occurred funding the consumer contract for %v requests.", numRequests)
+	}
+
+	logrus.Infof("Firing %v concurrent requests at Oracle job %v.", numRequests, priceFeedJobId)
+	oracleContractAddress := network.GetOracleContractAddress()
+	requestTxHashes := make([]string, numRequests)
+	requestErrs := make([]error, numRequests)
+	waitGroup := &sync.WaitGroup{}
+	for i := 0; i < numRequests; i++ {
+		waitGroup.Add(1)
+		go func(requestIdx int) {
+			defer waitGroup.Done()
+			requestTxHashes[requestIdx], requestErrs[requestIdx] = linkContractDeployerService.RunRequestDataScript(
+				oracleContractAddress, priceFeedJobId, priceFeedUrl)
+		}(i)
+	}
+	waitGroup.Wait()
+
+	samples := []benchmarking.FulfillmentSample{}
+	samplesByTxHash := map[string]*benchmarking.FulfillmentSample{}
+	for requestIdx, requestTxHash := range requestTxHashes {
+		if requestErrs[requestIdx] != nil {
+			samples = append(samples, benchmarking.FulfillmentSample{
+				FailureReason: fmt.Sprintf("Request couldn't be sent: %v", requestErrs[requestIdx]),
+				LinkPaidJuels: "0",
+			})
+			continue
+		}
+		receipt, err := network.waitForTransactionReceipt(requestTxHash)
+		if err != nil {
+			return nil, stacktrace.Propagate(err, "An error occurred waiting for request transaction %v to be mined", requestTxHash)
+		}
+		requestBlock, requestTime, err := network.getBlockNumberAndTime(receipt.BlockNumber)
+		if err != nil {
+			return nil, stacktrace.Propagate(err, "An error occurred getting the block of request transaction %v", requestTxHash)
+		}
+		failureReason := noRunFailureReason
+		if receipt.Status != geth.TransactionSucceededStatus {
+			failureReason = "Request transaction reverted"
+		}
+		samples = append(samples, benchmarking.FulfillmentSample{
+			RequestTxHash: requestTxHash,
+			RequestBlock:  requestBlock,
+			RequestTime:   requestTime,
+			FailureReason: failureReason,
+			LinkPaidJuels: "0",
+		})
+	}
+	for idx := range samples {
+		if samples[idx].FailureReason == noRunFailureReason {
+			samplesByTxHash[strings.ToLower(samples[idx].RequestTxHash)] = &samples[idx]
+		}
+	}
+
+	// Poll the Oracle until it has finished a run for every request that made it on-chain
+	runsByTxHash := map[string]chainlink_oracle.Run{}
+	numPolls := 0
+	for len(runsByTxHash) < len(samplesByTxHash) && numPolls < waitForBenchmarkCompletionPolls {
+		time.Sleep(waitForBenchmarkCompletionTimeBetweenPolls)
+		runs, err := oracleService.GetRunsForJob(priceFeedJobId)
+		if err != nil {
+			return nil, stacktrace.Propagate(err, "An error occurred getting data about job runs from the Oracle service.")
+		}
+		for _, run := range runs {
+			runTxHash := strings.ToLower(run.Attributes.RunRequest.TxHash)
+			_, isBenchmarkRequest := samplesByTxHash[runTxHash]
+			isFinished := run.Attributes.Status == chainlink_oracle.RunStatusCompleted || run.Attributes.Status == chainlink_oracle.RunStatusErrored
+			if isBenchmarkRequest && isFinished {
+				runsByTxHash[runTxHash] = run
+			}
+		}
+		numPolls += 1
+	}
+
+	for runTxHash, run := range runsByTxHash {
+		sample := samplesByTxHash[runTxHash]
+		sample.LinkPaidJuels = run.Attributes.Payment
+		if run.Attributes.Status != chainlink_oracle.RunStatusCompleted {
+			sample.FailureReason = fmt.Sprintf("Oracle run %v ended with status '%v'", run.Attributes.Id, run.Attributes.Status)
+			continue
+		}
+		if err := network.recordFulfillment(run, sample); err != nil {
+			return nil, stacktrace.Propagate(err, "An error occurred recording the fulfillment of request %v", sample.RequestTxHash)
+		}
+	}
+	return samples, nil
+}
+
+/*
+	Starts scraping the Prometheus metrics of the Oracle and every geth node in the background. Metrics are stored
+	under the service ID of the service they were scraped from.
+ */
+func (network *ChainlinkNetwork) StartMetricsCollection() error {
+	// The targets are snapshotted under one lock so the collector sees a consistent set of services, and the services
+	// themselves are only called once the lock is released
+	network.mutex.RLock()
+	if network.chainlinkOracleService == nil {
+		network.mutex.RUnlock()
+		return stacktrace.NewError("Tried to start metrics collection before deploying the oracle service.")
+	}
+	if network.metricsCollector != nil {
+		network.mutex.RUnlock()
+		return stacktrace.NewError("Tried to start metrics collection, but metrics are already being collected.")
+	}
+	oracleServices := map[services.ServiceID]chainlink_oracle.ChainlinkOracle{chainlinkOracleId: network.chainlinkOracleService}
+	for serviceId, oracleService := range network.extraOracleServices {
+		oracleServices[serviceId] = oracleService
+	}
+	gethServices := map[services.ServiceID]geth.GethNode{}
+	if network.gethBootsrapperService != nil {
+		gethServices[ethereumBootstrapperId] = network.gethBootsrapperService
+	}
+	for serviceId, gethService := range network.gethServices {
+		gethServices[serviceId] = gethService
+	}
+	network.mutex.RUnlock()
+
+	collector := metrics_collection.NewMetricsCollector()
+	for serviceId, oracleService := range oracleServices {
+		if err := collector.AddTarget(string(serviceId), oracleService.GetMetricsUrl()); err != nil {
+			return stacktrace.Propagate(err, "An error occurred adding Oracle %v as a metrics target.", serviceId)
+		}
+	}
+	for serviceId, gethService := range gethServices {
+		if err := collector.AddTarget(string(serviceId), gethService.GetMetricsUrl()); err != nil {
+			return stacktrace.Propagate(err, "An error occurred adding geth node %v as a metrics target.", serviceId)
+		}
+	}
+	if err := collector.Start(metricsScrapeInterval); err != nil {
+		return stacktrace.Propagate(err, "An error occurred starting metrics collection.")
+	}
+
+	// Another caller may have started collection while this collector was starting, in which case theirs wins
+	network.mutex.Lock()
+	if network.metricsCollector != nil {
+		network.mutex.Unlock()
+		if err := collector.Stop(); err != nil {
+			logrus.Warnf("Couldn't stop a redundant metrics collector: %v", err)
+		}
+		return stacktrace.NewError("Tried to start metrics collection, but metrics are already being collected.")
+	}
+	network.metricsCollector = collector
+	network.mutex.Unlock()
+	return nil
+}
+
+func (network *ChainlinkNetwork) StopMetricsCollection() error {
+	// Taking the collector out under the lock means only one caller gets to stop it
+	network.mutex.Lock()
+	collector := network.metricsCollector
+	network.metricsCollector = nil
+	network.mutex.Unlock()
+	if collector == nil {
+		return stacktrace.NewError("Tried to stop metrics collection, but metrics aren't being collected.")
+	}
+	if err := collector.Stop(); err != nil {
+		return stacktrace.Propagate(err, "An error occurred stopping metrics collection.")
+	}
+	network.mutex.Lock()
+	network.stoppedMetricsCollector = collector
+	network.mutex.Unlock()
+	return nil
+}
+
+/*
+	Gets the running metrics collector, or the last one stopped if none is running, since collectors keep the collected
+	metrics after collection has stopped.
+ */
+func (network *ChainlinkNetwork) GetMetricsCollector() (*metrics_collection.MetricsCollector, error) {
+	network.mutex.RLock()
+	defer network.mutex.RUnlock()
+	if network.metricsCollector != nil {
+		return network.metricsCollector, nil
+	}
+	if network.stoppedMetricsCollector != nil {
+		return network.stoppedMetricsCollector, nil
+	}
+	return nil, stacktrace.NewError("Metrics collection was never started.")
+}
+
+
+/*
+	Number of runs the Oracle completed while metrics were being collected.
+ */
+func (network *ChainlinkNetwork) GetOracleCompletedRunsIncrease() (float64, error) {
+	collector, err := network.GetMetricsCollector()
+	if err != nil {
+		return 0, stacktrace.Propagate(err, "An error occurred getting the metrics collector.")
+	}
+	completedRuns, err := collector.GetIncrease(string(chainlinkOracleId), chainlink_oracle.RunStatusUpdateMetric,
+		map[string]string{chainlink_oracle.RunStatusLabel: chainlink_oracle.RunStatusCompleted})
+	if err != nil {
+		return 0, stacktrace.Propagate(err, "An error occurred getting the Oracle's completed runs.")
+	}
+	return completedRuns, nil
+}
+
+/*
+	Number of blocks the Oracle's head tracker is behind the bootstrapper's chain head, as of the latest scrape that got
+	both, so the two heads are compared as of the same moment.
+ */
+func (network *ChainlinkNetwork) GetOracleHeadTrackerLag() (float64, error) {
+	collector, err := network.GetMetricsCollector()
+	if err != nil {
+		return 0, stacktrace.Propagate(err, "An error occurred getting the metrics collector.")
+	}
+	heads, err := collector.GetLatestValuesFromSameScrape(
+		metrics_collection.SeriesQuery{
+			TargetName: string(ethereumBootstrapperId),
+			MetricName: geth.ChainHeadBlockMetric,
+			Labels:     map[string]string{},
+		},
+		metrics_collection.SeriesQuery{
+			TargetName: string(chainlinkOracleId),
+			MetricName: chainlink_oracle.HeadTrackerCurrentHeadMetric,
+			Labels:     map[string]string{},
+		})
+	if err != nil {
+		return 0, stacktrace.Propagate(err, "An error occurred getting the bootstrapper's chain head and the Oracle's current head.")
+	}
+	chainHead, oracleHead := heads[0], heads[1]
+	return chainHead - oracleHead, nil
+}
+
+/*
+	Number of transactions the Oracle's tx manager saw revert while metrics were being collected.
+ */
+func (network *ChainlinkNetwork) GetOracleTxManagerRevertsIncrease() (float64, error) {
+	collector, err := network.GetMetricsCollector()
+	if err != nil {
+		return 0, stacktrace.Propagate(err, "An error occurred getting the metrics collector.")
+	}
+	numReverted, err := collector.GetIncrease(string(chainlinkOracleId), chainlink_oracle.TxManagerNumTxRevertedMetric, map[string]string{})
+	if err != nil {
+		return 0, stacktrace.Propagate(err, "An error occurred getting the Oracle's reverted transactions.")
+	}
+	return numReverted, nil
+}
+
+/*
+	Adds Postgres, the price feed server and every geth node in the topology, starting services concurrently where
+	they don't depend on each other. Only the geth nodes depend on anything, needing the bootstrapper's enode.
+ */
+func (network *ChainlinkNetwork) AddBaseServices() error {
+	graph := dependency_graph.NewDependencyGraph()
+	addTask := func(taskId string, dependencyIds []string, run func() error) error {
+		if err := graph.AddTask(taskId, dependencyIds, run); err != nil {
+			return stacktrace.Propagate(err, "An error occurred adding startup task '%v'", taskId)
+		}
+		return nil
+	}
+	if err := addTask(string(postgresId), []string{}, network.AddPostgres); err != nil {
+		return err
+	}
+	if err := addTask(string(priceFeedServerId), []string{}, network.AddPriceFeedServer); err != nil {
+		return err
+	}
+	if err := addTask(string(ethereumBootstrapperId), []string{}, network.AddBootstrapper); err != nil {
+		return err
+	}
+	// The bootstrapper counts towards the topology's geth nodes
+	for nodeIdx := 1; nodeIdx < network.topology.NumGethNodes; nodeIdx++ {
+		err := addTask(gethNodeTaskIdPrefix + strconv.Itoa(nodeIdx), []string{string(ethereumBootstrapperId)}, func() error {
+			serviceId, err := network.AddGethService()
+			if err != nil {
+				return stacktrace.Propagate(err, "Failed to add an ethereum node.")
+			}
+			logrus.Infof("Added a geth service with id: %v", serviceId)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	if err := graph.Run(); err != nil {
+		return stacktrace.Propagate(err, "An error occurred starting the network's base services.")
+	}
+	return nil
+}
+
+func (network *ChainlinkNetwork) AddBootstrapper() error {
+	if network.GetBootstrapper() != nil {
+		return stacktrace.NewError("Cannot add bootstrapper service to network; bootstrapper already exists!")
+	}
+
+	// The bootstrapper is always the first signer
+	signerAddresses := network.topology.GetSignerAddresses()
+	image := getImageOverride(network.topology.GethNodeImages, 0, network.gethServiceImage)
+	initializer := geth.NewGethContainerInitializer(image, network.gethDataDirArtifactId, nil, signerAddresses[0], signerAddresses)
+	uncastedBootstrapper, checker, err := network.networkCtx.AddService(ethereumBootstrapperId, initializer)
+	if err != nil {
+		return stacktrace.Propagate(err, "An error occurred adding the bootstrapper service")
+	}
+	if err := checker.WaitForStartup(waitForStartupTimeBetweenPolls, waitForStartupMaxNumPolls); err != nil {
+		return stacktrace.Propagate(err, "An error occurred waiting for the bootstrapper service to start")
+	}
+	castedGethBootstrapperService := uncastedBootstrapper.(geth.GethNode)
+	network.mutex.Lock()
+	network.gethBootsrapperService = castedGethBootstrapperService
+	network.mutex.Unlock()
+	return nil
+}
+
+func (network *ChainlinkNetwork) AddPostgres() error {
+	if network.getPostgres() != nil {
+		return stacktrace.NewError("Cannot add postgres service to network; postgres service already exists!")
+	}
+	initializer := postgres.NewPostgresContainerInitializer(network.postgresImage)
+	uncastedPostgres, checker, err := network.networkCtx.AddService(postgresId, initializer)
+	if err != nil {
+		return stacktrace.Propagate(err, "An error occurred adding the postgres service")
+	}
+	if err := checker.WaitForStartup(waitForStartupTimeBetweenPolls, waitForStartupMaxNumPolls); err != nil {
+		return stacktrace.Propagate(err, "An error occurred waiting for the postgres service to start")
+	}
+	castedPostgres := uncastedPostgres.(*postgres.PostgresService)
+	network.mutex.Lock()
+	network.postgresService = castedPostgres
+	network.mutex.Unlock()
+	return nil
+}
+
+func (network *ChainlinkNetwork) AddOracleService() error {
+	network.mutex.RLock()
+	linkContractAddress := network.linkContractAddress
+	oracleContractAddress := network.oracleContractAddress
+	chainlinkOracleService := network.chainlinkOracleService
+	postgresService := network.postgresService
+	oracleDatabaseProxy := network.oracleDatabaseProxy
+	network.mutex.RUnlock()
+	if linkContractAddress == "" {
+		return stacktrace.NewError("Tried to add an oracle service, but the $LINK token contract has not yet been deployed.")
+	}
+	if oracleContractAddress == "" {
+		return stacktrace.NewError("Tried to add an oracle service, but the Oracle contract has not yet been deployed.")
+	}
+	if chainlinkOracleService != nil {
+		return stacktrace.NewError("Tried to add an oracle service, but one has already been added!")
+	}
+	if postgresService == nil {
+		return stacktrace.NewError("Tried to add an oracle service, but the postgres service has not yet been added.")
+	}
+	databaseCredentials, err := postgresService.CreateDatabaseWithOwner(oracleDatabaseName, oracleDatabaseUsername, oracleDatabasePassword)
+	if err != nil {
+		return stacktrace.Propagate(err, "An error occurred creating the Oracle's database.")
+	}
+	network.mutex.Lock()
+	network.oracleDatabaseCredentials = databaseCredentials
+	network.mutex.Unlock()
+	oracleFacingCredentials := databaseCredentials
+	if oracleDatabaseProxy != nil {
+		proxyHost, proxyPort, err := oracleDatabaseProxy.Start()
+		if err != nil {
+			return stacktrace.Propagate(err, "An error occurred starting the Oracle database proxy.")
+		}
+		oracleFacingCredentials.Host = proxyHost
+		oracleFacingCredentials.Port = proxyPort
+	}
+	image := getImageOverride(network.topology.OracleImages, 0, network.chainlinkOracleImage)
+	chainlinkOracleService, err = network.addOracle(chainlinkOracleId, image, oracleFacingCredentials)
+	if err != nil {
+		return stacktrace.Propagate(err, "An error occurred adding the Chainlink Oracle service.")
+	}
+	network.mutex.Lock()
+	network.chainlinkOracleService = chainlinkOracleService
+	network.chainlinkOracleServiceId = chainlinkOracleId
+	network.mutex.Unlock()
+
+	for oracleIdx := 1; oracleIdx < network.topology.NumOracles; oracleIdx++ {
+		serviceId := services.ServiceID(extraOracleIdPrefix + strconv.Itoa(oracleIdx))
+		extraDatabaseName := extraOracleDatabasePrefix + strconv.Itoa(oracleIdx)
+		extraDatabaseCredentials, err := postgresService.CreateDatabaseWithOwner(extraDatabaseName, extraDatabaseName, oracleDatabasePassword)
+		if err != nil {
+			return stacktrace.Propagate(err, "An error occurred creating the database of Oracle %v.", serviceId)
+		}
+		image := getImageOverride(network.topology.OracleImages, oracleIdx, network.chainlinkOracleImage)
+		extraOracleService, err := network.addOracle(serviceId, image, extraDatabaseCredentials)
+		if err != nil {
+			return stacktrace.Propagate(err, "An error occurred adding Oracle %v.", serviceId)
+		}
+		network.mutex.Lock()
+		network.extraOracleServices[serviceId] = extraOracleService
+		network.mutex.Unlock()
+	}
+	return nil
+}
+
+func (network *ChainlinkNetwork) GetBootstrapper() geth.GethNode {
+	network.mutex.RLock()
+	defer network.mutex.RUnlock()
+	return network.gethBootsrapperService
+}
+
+func (network *ChainlinkNetwork) GetLinkContractAddress() string {
+	network.mutex.RLock()
+	defer network.mutex.RUnlock()
+	return network.linkContractAddress
+}
+
+func (network *ChainlinkNetwork) GetConsumerContractAddress() string {
+	network.mutex.RLock()
+	defer network.mutex.RUnlock()
+	return network.consumerContractAddress
+}
+
+func (network *ChainlinkNetwork) GetOracleContractAddress() string {
+	network.mutex.RLock()
+	defer network.mutex.RUnlock()
+	return network.oracleContractAddress
+}
+
+func (network *ChainlinkNetwork) GetChainlinkOracle() chainlink_oracle.ChainlinkOracle {
+	network.mutex.RLock()
+	defer network.mutex.RUnlock()
+	return network.chainlinkOracleService
+}
+
+/*
+	The ID of the price feed job on the primary Oracle, or empty if the job hasn't been deployed yet.
+ */
+func (network *ChainlinkNetwork) GetPriceFeedJobId() string {
+	network.mutex.RLock()
+	defer network.mutex.RUnlock()
+	return network.priceFeedJobId
+}
+
+/*
+	A snapshot of the non-bootstrapper geth nodes by service ID. Nodes added after the call aren't in the snapshot, so
+	callers can iterate over it while other goroutines keep adding nodes.
+ */
+func (network *ChainlinkNetwork) GetGethServices() map[services.ServiceID]geth.GethNode {
+	network.mutex.RLock()
+	defer network.mutex.RUnlock()
+	gethServices := map[services.ServiceID]geth.GethNode{}
+	for serviceId, gethService := range network.gethServices {
+		gethServices[serviceId] = gethService
+	}
+	return gethServices
+}
+
+/*
+	A snapshot of the primary Oracle and any extra ones, by service ID.
+ */
+func (network *ChainlinkNetwork) GetOracleServices() map[services.ServiceID]chainlink_oracle.ChainlinkOracle {
+	network.mutex.RLock()
+	defer network.mutex.RUnlock()
+	allOracleServices := map[services.ServiceID]chainlink_oracle.ChainlinkOracle{}
+	if network.chainlinkOracleService != nil {
+		allOracleServices[chainlinkOracleId] = network.chainlinkOracleService
+	}
+	for serviceId, oracleService := range network.extraOracleServices {
+		allOracleServices[serviceId] = oracleService
+	}
+	return allOracleServices
+}
+
+func (network *ChainlinkNetwork) AddPriceFeedServer() error {
+	initializer := price_feed_server.NewPriceFeedServerInitializer(network.priceFeedServerImage)
+	uncastedPriceFeedServer, checker, err := network.networkCtx.AddService(priceFeedServerId, initializer)
+	if err != nil {
+		return stacktrace.Propagate(err, "An error occurred adding the price feed server.")
+	}
+	if err := checker.WaitForStartup(waitForStartupTimeBetweenPolls, waitForStartupMaxNumPolls); err != nil {
+		return stacktrace.Propagate(err, "An error occurred waiting for the price feed server to start")
+	}
+	castedPriceFeedServer := uncastedPriceFeedServer.(*price_feed_server.PriceFeedServer)
+	network.mutex.Lock()
+	network.priceFeedServer = castedPriceFeedServer
+	network.mutex.Unlock()
+	return nil
+}
+
+func (network *ChainlinkNetwork) AddGethService() (services.ServiceID, error) {
+	return network.AddGethServiceWithImage("")
+}
+
+/*
+	Adds a geth node running the given image rather than the one the topology picks for it, unless the image is empty.
+	Useful when nodes are added concurrently, since the topology's per-node images go by the order nodes get added in.
+ */
+func (network *ChainlinkNetwork) AddGethServiceWithImage(imageOverride string) (services.ServiceID, error) {
+	network.mutex.Lock()
+	gethBootstrapperService := network.gethBootsrapperService
+	if gethBootstrapperService == nil {
+		network.mutex.Unlock()
+		return "", stacktrace.NewError("Cannot add ethereum node to network; no bootstrap node exists")
+	}
+	serviceIdStr := gethServiceIdPrefix + strconv.Itoa(network.nextGethServiceId)
+	network.nextGethServiceId = network.nextGethServiceId + 1
+	serviceId := services.ServiceID(serviceIdStr)
+
+	// Node indexes count the bootstrapper as 0, and the topology's signers are the first nodes added
+	nodeIdx := network.nextGethServiceId
+	network.mutex.Unlock()
+	signerAddresses := network.topology.GetSignerAddresses()
+	signerAddress := ""
+	if nodeIdx < len(signerAddresses) {
+		signerAddress = signerAddresses[nodeIdx]
+	}
+	image := getImageOverride(network.topology.GethNodeImages, nodeIdx, network.gethServiceImage)
+	if imageOverride != "" {
+		image = imageOverride
+	}
+	initializer := geth.NewGethContainerInitializer(image, network.gethDataDirArtifactId, gethBootstrapperService, signerAddress, signerAddresses)
+	uncastedGethService, checker, err := network.networkCtx.AddService(serviceId, initializer)
+	if err != nil {
+		return "", stacktrace.Propagate(err, "An error occurred adding the ethereum node")
+	}
+	if err := checker.WaitForStartup(waitForStartupTimeBetweenPolls, waitForStartupMaxNumPolls); err != nil {
+		return "", stacktrace.Propagate(err, "An error occurred waiting for the ethereum node to start")
+	}
+	castedGethService := uncastedGethService.(geth.GethNode)
+
+	network.mutex.Lock()
+	network.gethServices[serviceId] = castedGethService
+	network.mutex.Unlock()
+	return serviceId, nil
+}
+
+func (network *ChainlinkNetwork) ManuallyConnectPeers() error {
+	if network.GetBootstrapper() == nil {
+		return stacktrace.NewError("Cannot connect peers; no bootstrap node exists")
+	}
+	allServices := network.getAllGethServices()
+
+	// Connect all nodes to each other
+	for nodeId, nodeGethService := range allServices {
+		for peerId, peerGethService := range allServices {
+			if nodeId == peerId {
+				continue
+			}
+			peerGethServiceEnode, err := peerGethService.GetEnodeAddress()
 			if err != nil {
 				return stacktrace.Propagate(err, "Failed to get enode from peer %v", peerId)
 			}
@@ -401,10 +1810,444 @@ func (network *ChainlinkNetwork) ManuallyConnectPeers() error {
 	return nil
 }
 
-func (network *ChainlinkNetwork) GetGethService(serviceId services.ServiceID) (*geth.GethService, error) {
+/*
+	Queries the Oracle's database directly, e.g. to assert on Chainlink tables like job_runs and eth_txes that the
+	operator API doesn't fully expose.
+ */
+func (network *ChainlinkNetwork) QueryOracleDatabase(query string, args ...interface{}) ([]map[string]interface{}, error) {
+	postgresService, databaseName := network.getOracleDatabase()
+	if postgresService == nil || databaseName == "" {
+		return nil, stacktrace.NewError("Tried to query the Oracle's database before it was created.")
+	}
+	rows, err := postgresService.Query(databaseName, query, args...)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "An error occurred querying the Oracle's database.")
+	}
+	return rows, nil
+}
+
+/*
+	Sets the report that the steps of the test using this network get recorded in.
+ */
+func (network *ChainlinkNetwork) SetTestReport(report *test_reporting.TestReport) {
+	network.mutex.Lock()
+	defer network.mutex.Unlock()
+	network.testReport = report
+}
+
+/*
+	Runs a step of the test using this network, recording it in the test's report if there is one, and returns the
+	step's error.
+ */
+func (network *ChainlinkNetwork) RunTestStep(name string, step func() error) error {
+	network.mutex.RLock()
+	testReport := network.testReport
+	network.mutex.RUnlock()
+	if testReport == nil {
+		return step()
+	}
+	return testReport.RunStep(name, step)
+}
+
+func (network *ChainlinkNetwork) GetTopology() NetworkTopology {
+	return network.topology
+}
+
+func (network *ChainlinkNetwork) GetChainlinkOracleImage() string {
+	return network.chainlinkOracleImage
+}
+
+func (network *ChainlinkNetwork) GetGethService(serviceId services.ServiceID) (geth.GethNode, error) {
+	network.mutex.RLock()
+	defer network.mutex.RUnlock()
 	service, found := network.gethServices[serviceId]
 	if !found {
 		return nil, stacktrace.NewError("No geth service with ID '%v' has been added", serviceId)
 	}
 	return service, nil
-}
\ No newline at end of file
+}
+
+// ==========================================================================================
+//								Helper methods
+// ==========================================================================================
+
+/*
+	Allows every ethereum account owned by the Oracle node to fulfill requests made to the Oracle contract.
+ */
+func (network *ChainlinkNetwork) setFulfillmentPermissions() error {
+	oracleEthAccounts := []chainlink_oracle.OracleEthereumKey{}
+	for serviceId, oracleService := range network.GetOracleServices() {
+		serviceEthAccounts, err := oracleService.GetEthAccounts()
+		if err != nil {
+			return stacktrace.Propagate(err, "Error occurred requesting ethereum key information from Oracle %v.", serviceId)
+		}
+		oracleEthAccounts = append(oracleEthAccounts, serviceEthAccounts...)
+	}
+
+	oracleContractAddress := network.GetOracleContractAddress()
+	gethBootstrapperService := network.GetBootstrapper()
+	linkContractDeployerService := network.getLinkContractDeployer()
+	for _, ethAccount := range oracleEthAccounts {
+		ethAddress := ethAccount.Attributes.Address
+		logrus.Infof("Setting permissions for address %v to run code from oracle contract %v.",
+			ethAddress,
+			oracleContractAddress)
+		err := linkContractDeployerService.SetFulfillmentPermissions(
+			gethBootstrapperService.GetIPAddress(),
+			strconv.Itoa(gethBootstrapperService.GetRpcPort()),
+			oracleContractAddress,
+			ethAddress,
+		)
+		if err != nil {
+			return stacktrace.Propagate(err, "Error occurred setting fulfillent permissions for address %v.", ethAddress)
+		}
+	}
+	return nil
+}
+
+/*
+	Fills in the on-chain details of the transaction that fulfilled a completed run.
+ */
+func (network *ChainlinkNetwork) recordFulfillment(run chainlink_oracle.Run, sample *benchmarking.FulfillmentSample) error {
+	fulfillmentTxHash, err := getFulfillmentTxHash(run)
+	if err != nil {
+		return stacktrace.Propagate(err, "Couldn't get the fulfillment transaction of run %v", run.Attributes.Id)
+	}
+	receipt, err := network.waitForTransactionReceipt(fulfillmentTxHash)
+	if err != nil {
+		return stacktrace.Propagate(err, "An error occurred waiting for fulfillment transaction %v to be mined", fulfillmentTxHash)
+	}
+	transaction, err := network.GetBootstrapper().GetTransactionByHash(fulfillmentTxHash)
+	if err != nil {
+		return stacktrace.Propagate(err, "An error occurred getting fulfillment transaction %v", fulfillmentTxHash)
+	}
+	fulfillmentBlock, fulfillmentTime, err := network.getBlockNumberAndTime(receipt.BlockNumber)
+	if err != nil {
+		return stacktrace.Propagate(err, "An error occurred getting the block of fulfillment transaction %v", fulfillmentTxHash)
+	}
+	gasUsed, err := geth.ParseHexQuantity(receipt.GasUsed)
+	if err != nil {
+		return stacktrace.Propagate(err, "An error occurred parsing the gas used by fulfillment transaction %v", fulfillmentTxHash)
+	}
+	gasPrice, ok := new(big.Int).SetString(strings.TrimPrefix(transaction.GasPrice, "0x"), 16)
+	if !ok {
+		return stacktrace.NewError("Couldn't parse gas price '%v' of fulfillment transaction %v", transaction.GasPrice, fulfillmentTxHash)
+	}
+
+	sample.Fulfilled = true
+	sample.FailureReason = ""
+	sample.FulfillmentTxHash = fulfillmentTxHash
+	sample.FulfillmentBlock = fulfillmentBlock
+	sample.FulfillmentTime = fulfillmentTime
+	sample.FulfillmentGasUsed = gasUsed
+	sample.FulfillmentGasPriceWei = gasPrice.String()
+	return nil
+}
+
+func (network *ChainlinkNetwork) waitForTransactionReceipt(txHash string) (*geth.TransactionReceipt, error) {
+	gethBootstrapperService := network.GetBootstrapper()
+	numPolls := 0
+	for numPolls < waitForTransactionFinalizationPolls {
+		receipt, err := gethBootstrapperService.GetTransactionReceipt(txHash)
+		if err != nil {
+			return nil, stacktrace.Propagate(err, "An error occurred getting the receipt of transaction %v", txHash)
+		}
+		if receipt != nil {
+			return receipt, nil
+		}
+		time.Sleep(waitForTransactionFinalizationTimeBetweenPolls)
+		numPolls += 1
+	}
+	return nil, stacktrace.NewError("Transaction %v still wasn't mined after %v polls with %v between polls",
+		txHash,
+		waitForTransactionFinalizationPolls,
+		waitForTransactionFinalizationTimeBetweenPolls)
+}
+
+func (network *ChainlinkNetwork) getBlockNumberAndTime(blockNumberHex string) (uint64, time.Time, error) {
+	blockNumber, err := geth.ParseHexQuantity(blockNumberHex)
+	if err != nil {
+		return 0, time.Time{}, stacktrace.Propagate(err, "An error occurred parsing block number '%v'", blockNumberHex)
+	}
+	block, err := network.GetBootstrapper().GetBlockByNumber(blockNumber)
+	if err != nil {
+		return 0, time.Time{}, stacktrace.Propagate(err, "An error occurred getting block %v", blockNumber)
+	}
+	blockTimestamp, err := geth.ParseHexQuantity(block.Timestamp)
+	if err != nil {
+		return 0, time.Time{}, stacktrace.Propagate(err, "An error occurred parsing the timestamp of block %v", blockNumber)
+	}
+	return blockNumber, time.Unix(int64(blockTimestamp), 0), nil
+}
+
+func (network *ChainlinkNetwork) fundOracleEthAccounts(oracleService chainlink_oracle.ChainlinkOracle) error {
+	oracleEthAccounts, err := oracleService.GetEthAccounts()
+	if err != nil {
+		return stacktrace.Propagate(err, "An error occurred getting the Oracle's ethereum accounts")
+	}
+	gethBootstrapperService := network.GetBootstrapper()
+	for _, ethAccount := range oracleEthAccounts {
+		toAddress := ethAccount.Attributes.Address
+		err = gethBootstrapperService.SendTransaction(contractOwnerAddress, toAddress, oracleEthPreFundingAmount)
+		if err != nil {
+			return stacktrace.Propagate(err, "An error occurred sending eth between accounts.")
+		}
+	}
+
+	/*
+		Poll for transaction finalization so that we know that the Oracle's ethereum accounts are funded.
+		See: https://docs.chain.link/docs/running-a-chainlink-node#start-the-chainlink-node, "you will
+		need to send some ETH to your node's address in order for it to fulfill requests".
+	 */
+	ethAccountsFunded := false
+	numPolls := 0
+	for !ethAccountsFunded && numPolls < waitForTransactionFinalizationPolls {
+		time.Sleep(waitForTransactionFinalizationTimeBetweenPolls)
+		oracleEthAccounts, err = oracleService.GetEthAccounts()
+		if err != nil {
+			return stacktrace.Propagate(err, "An error occurred getting the Oracle's ethereum accounts")
+		}
+		numPolls += 1
+
+		// Eth Accounts are considered funded if every eth account the Oracle owns is funded (has balance != 0)
+		allAccountsFunded := true
+		for _, account := range(oracleEthAccounts) {
+			allAccountsFunded = allAccountsFunded && (account.Attributes.EthBalance != "0")
+		}
+		ethAccountsFunded = ethAccountsFunded || allAccountsFunded
+	}
+	return nil
+}
+
+/*
+	Every geth node, including the bootstrapper, by service ID.
+ */
+func (network *ChainlinkNetwork) getAllGethServices() map[services.ServiceID]geth.GethNode {
+	allGethServices := network.GetGethServices()
+	if gethBootstrapperService := network.GetBootstrapper(); gethBootstrapperService != nil {
+		allGethServices[ethereumBootstrapperId] = gethBootstrapperService
+	}
+	return allGethServices
+}
+
+func (network *ChainlinkNetwork) getExtraOracleServices() map[services.ServiceID]chainlink_oracle.ChainlinkOracle {
+	network.mutex.RLock()
+	defer network.mutex.RUnlock()
+	extraOracleServices := map[services.ServiceID]chainlink_oracle.ChainlinkOracle{}
+	for serviceId, oracleService := range network.extraOracleServices {
+		extraOracleServices[serviceId] = oracleService
+	}
+	return extraOracleServices
+}
+
+func (network *ChainlinkNetwork) getExtraOracleJobIds() map[services.ServiceID]string {
+	network.mutex.RLock()
+	defer network.mutex.RUnlock()
+	extraOracleJobIds := map[services.ServiceID]string{}
+	for serviceId, jobId := range network.extraOracleJobIds {
+		extraOracleJobIds[serviceId] = jobId
+	}
+	return extraOracleJobIds
+}
+
+func (network *ChainlinkNetwork) getLinkContractDeployer() *chainlink_contract_deployer.ChainlinkContractDeployerService {
+	network.mutex.RLock()
+	defer network.mutex.RUnlock()
+	return network.linkContractDeployerService
+}
+
+func (network *ChainlinkNetwork) getPostgres() *postgres.PostgresService {
+	network.mutex.RLock()
+	defer network.mutex.RUnlock()
+	return network.postgresService
+}
+
+/*
+	The Postgres service and the name of the Oracle's database on it, which is empty until the database is created.
+ */
+func (network *ChainlinkNetwork) getOracleDatabase() (*postgres.PostgresService, string) {
+	network.mutex.RLock()
+	defer network.mutex.RUnlock()
+	return network.postgresService, network.oracleDatabaseCredentials.DatabaseName
+}
+
+func (network *ChainlinkNetwork) getPriceFeedServer() *price_feed_server.PriceFeedServer {
+	network.mutex.RLock()
+	defer network.mutex.RUnlock()
+	return network.priceFeedServer
+}
+
+func (network *ChainlinkNetwork) getEventEmitterAddress() string {
+	network.mutex.RLock()
+	defer network.mutex.RUnlock()
+	return network.eventEmitterAddress
+}
+
+func (network *ChainlinkNetwork) getSimpleConsumerAddress() string {
+	network.mutex.RLock()
+	defer network.mutex.RUnlock()
+	return network.simpleConsumerAddress
+}
+
+/*
+	Creates a job on the primary Oracle from the spec the given function builds out of the price feed server's URL and
+	the SimpleConsumer contract's address.
+ */
+func (network *ChainlinkNetwork) addSimpleConsumerJob(buildJobSpec func(priceFeedUrl string, simpleConsumerAddress string) chainlink_oracle.JobSpec) (string, error) {
+	oracleService := network.GetChainlinkOracle()
+	if oracleService == nil {
+		return "", stacktrace.NewError("Tried to add a job before deploying the oracle service.")
+	}
+	priceFeedUrl := network.getPriceFeedUrl()
+	if priceFeedUrl == "" {
+		return "", stacktrace.NewError("Tried to add a job before deploying the in-network price feed server service.")
+	}
+	simpleConsumerAddress := network.getSimpleConsumerAddress()
+	if simpleConsumerAddress == "" {
+		return "", stacktrace.NewError("Tried to add a job before deploying the SimpleConsumer contract.")
+	}
+	jobId, err := oracleService.CreateJob(buildJobSpec(priceFeedUrl, simpleConsumerAddress))
+	if err != nil {
+		return "", stacktrace.Propagate(err, "An error occurred creating the job on the Oracle.")
+	}
+	return jobId, nil
+}
+
+/*
+	Calls a contract getter that returns a uint256, with the given call data: its selector followed by its ABI-encoded
+	arguments, if any.
+ */
+func (network *ChainlinkNetwork) callUint256Getter(contractAddress string, callData string) (*big.Int, error) {
+	bootstrapper := network.GetBootstrapper()
+	if bootstrapper == nil {
+		return nil, stacktrace.NewError("Tried to call a contract before adding the bootstrapper.")
+	}
+	returnData, err := bootstrapper.CallContract(contractAddress, callData)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "An error occurred calling %v on contract %v.", callData, contractAddress)
+	}
+	value, err := geth.ParseHexUint256(returnData)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "Contract %v returned data that isn't a uint256 from %v.", contractAddress, callData)
+	}
+	return value, nil
+}
+
+/*
+	Sends a transaction calling a contract from the first funded account, which the bootstrapper keeps unlocked, and
+	waits for it to be mined, returning its receipt whether it succeeded or reverted.
+ */
+func (network *ChainlinkNetwork) sendContractTransaction(contractAddress string, callData string) (*geth.TransactionReceipt, error) {
+	bootstrapper := network.GetBootstrapper()
+	if bootstrapper == nil {
+		return nil, stacktrace.NewError("Tried to call a contract before adding the bootstrapper.")
+	}
+	txHash, err := bootstrapper.SendRpcTransaction(geth.TransactionArgs{
+		From: contractOwnerAddress,
+		To:   contractAddress,
+		Gas:  geth.FormatHexQuantity(requestTransactionGasLimit),
+		Data: callData,
+	})
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "An error occurred sending a transaction to contract %v.", contractAddress)
+	}
+	receipt, err := network.waitForTransactionReceipt(txHash)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "An error occurred waiting for transaction %v to be mined.", txHash)
+	}
+	return receipt, nil
+}
+
+/*
+	The URL jobs fetch the price from, or empty if the price feed server hasn't been added yet.
+ */
+func (network *ChainlinkNetwork) getPriceFeedUrl() string {
+	priceFeedServer := network.getPriceFeedServer()
+	if priceFeedServer == nil {
+		return ""
+	}
+	return fmt.Sprintf("http://%v:%v/", priceFeedServer.GetIPAddress(), priceFeedServer.GetHTTPPort())
+}
+
+func (network *ChainlinkNetwork) sendDataRequestForJob(jobId string) (string, error) {
+	oracleContractAddress := network.GetOracleContractAddress()
+	if oracleContractAddress == "" {
+		return "", stacktrace.NewError("Tried to request data before deploying the oracle contract.")
+	}
+	linkContractDeployerService := network.getLinkContractDeployer()
+	if linkContractDeployerService == nil {
+		return "", stacktrace.NewError("Tried to request data before deploying the link contract deployer service.")
+	}
+	priceFeedUrl := network.getPriceFeedUrl()
+	if priceFeedUrl == "" {
+		return "", stacktrace.NewError("Tried to request data before deploying the in-network price feed server service.")
+	}
+	err := network.setFulfillmentPermissions()
+	if err != nil {
+		return "", stacktrace.Propagate(err, "Error occurred setting fulfillment permissions.")
+	}
+
+	logrus.Infof("Calling the Oracle contract to run job %v.", jobId)
+
+	// Request data from the Oracle smart contract, starting a job.
+	requestTxHash, err := linkContractDeployerService.RunRequestDataScript(oracleContractAddress, jobId, priceFeedUrl)
+	if err != nil {
+		return "", stacktrace.Propagate(err, "An error occurred requesting data from the Oracle contract on-chain.")
+	}
+	return requestTxHash, nil
+}
+
+/*
+	The hash of the transaction a completed price feed run sent to fulfill its request.
+ */
+func getFulfillmentTxHash(run chainlink_oracle.Run) (string, error) {
+	taskRuns := run.Attributes.TaskRuns
+	if len(taskRuns) == 0 {
+		return "", stacktrace.NewError("Completed run %v has no task runs", run.Attributes.Id)
+	}
+	// The last task of the job is the EthTx that fulfills the request, whose result is the transaction hash
+	fulfillmentTxHash, ok := taskRuns[len(taskRuns) - 1].Result.Data.Result.(string)
+	if !ok {
+		return "", stacktrace.NewError("Final task of run %v didn't produce a transaction hash", run.Attributes.Id)
+	}
+	return fulfillmentTxHash, nil
+}
+
+/*
+	The given column of every row of a query result, formatted as strings.
+ */
+func getRowStrings(rows []map[string]interface{}, column string) []string {
+	result := []string{}
+	for _, row := range rows {
+		result = append(result, fmt.Sprintf("%v", row[column]))
+	}
+	return result
+}
+
+/*
+	Waits for the given Oracle to complete the run of the given job for the request sent in the given transaction.
+ */
+func waitForRequestRun(oracleService chainlink_oracle.ChainlinkOracle, jobId string, requestTxHash string, maxNumPolls int) error {
+	isRequestRun := func(run chainlink_oracle.Run) bool {
+		return strings.EqualFold(run.Attributes.RunRequest.TxHash, requestTxHash)
+	}
+	if _, err := oracleService.WaitForRun(jobId, isRequestRun, maxNumPolls); err != nil {
+		return stacktrace.Propagate(err, "Oracle didn't complete a run for request %v.", requestTxHash)
+	}
+	return nil
+}
+
+func (network *ChainlinkNetwork) addOracle(serviceId services.ServiceID, image string, databaseCredentials postgres.DatabaseCredentials) (chainlink_oracle.ChainlinkOracle, error) {
+	initializer := chainlink_oracle.NewChainlinkOracleContainerInitializer(image,
+		network.GetLinkContractAddress(), network.GetOracleContractAddress(), network.GetBootstrapper(), databaseCredentials,
+		network.topology.OracleEnvironment)
+	uncastedChainlinkOracle, checker, err := network.networkCtx.AddService(serviceId, initializer)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "An error occurred adding Oracle service %v.", serviceId)
+	}
+	if err := checker.WaitForStartup(waitForStartupTimeBetweenPolls, waitForStartupMaxNumPolls); err != nil {
+		return nil, stacktrace.Propagate(err, "An error occurred waiting for Oracle service %v to start up.", serviceId)
+	}
+	castedChainlinkOracle := uncastedChainlinkOracle.(chainlink_oracle.ChainlinkOracle)
+	return castedChainlinkOracle, nil
+}
@@ -0,0 +1,402 @@
+package networks_impl
+
+import (
+	"fmt"
+	"github.com/kurtosis-tech/kurtosis-libs/golang/lib/services"
+	"github.com/kurtosistech/chainlink-testing/testsuite/services_impl/chainlink_contract_deployer"
+	"github.com/kurtosistech/chainlink-testing/testsuite/services_impl/chainlink_oracle"
+	"github.com/kurtosistech/chainlink-testing/testsuite/services_impl/chainlink_oracle/oracletest"
+	"github.com/kurtosistech/chainlink-testing/testsuite/services_impl/geth"
+	"github.com/kurtosistech/chainlink-testing/testsuite/services_impl/price_feed_server"
+	"github.com/kurtosistech/chainlink-testing/testsuite/services_impl/testutil"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+const (
+	// The fake geth nodes only serve RPC, so the other ports just need to be set
+	testGethWsPort = 8546
+	testGethMetricsPort = 6060
+
+	testOracleContractAddress = "0x1234567890123456789012345678901234567890"
+	testOracleKeyAddress = "0xabcdefabcdefabcdefabcdefabcdefabcdefabcd"
+	testRequestTxHash = "0x1111111111111111111111111111111111111111111111111111111111111111"
+	testFulfillmentTxHash = "0x2222222222222222222222222222222222222222222222222222222222222222"
+	testPriceFeedServerIpAddress = "172.23.0.9"
+	testPriceFeedServerPort = 8080
+	testContractDeployerIpAddress = "172.23.0.10"
+
+	// Load of plain transfers, light enough for the fake nodes to keep up with
+	testLoadTransactionsPerSecond = 200
+	testLoadGasPerTransaction = 21000
+	numConcurrentGethNodeAdditions = 8
+	minNumLoadTransactionsSent = 10
+	loadTransactionsWaitTimeout = 10 * time.Second
+
+	// Scripts the contract deployer runs to request data, and to let the Oracle's keys fulfill requests
+	requestDataScriptFragment = "request-data.js"
+	setFulfillmentPermissionsScriptFragment = "setOracleFulfillmentPermissions.js"
+)
+
+func TestManuallyConnectPeers(t *testing.T) {
+	testCases := []struct {
+		name string
+		numNodes int
+		// Makes some of the fake nodes misbehave; the first fake is the bootstrapper
+		breakNodes func(fakes []*testutil.FakeGethRpcServer)
+		isErrorExpected bool
+	}{
+		{
+			name:     "bootstrapper only",
+			numNodes: 1,
+		},
+		{
+			name:     "bootstrapper and two nodes",
+			numNodes: 3,
+		},
+		{
+			name:            "no nodes",
+			numNodes:        0,
+			isErrorExpected: true,
+		},
+		{
+			name:     "enode lookup fails",
+			numNodes: 3,
+			breakNodes: func(fakes []*testutil.FakeGethRpcServer) {
+				fakes[1].SetMethodError("admin_nodeInfo", "node is shutting down")
+			},
+			isErrorExpected: true,
+		},
+		{
+			name:     "peer rejected",
+			numNodes: 3,
+			breakNodes: func(fakes []*testutil.FakeGethRpcServer) {
+				fakes[2].SetMethodResult("admin_addPeer", false)
+			},
+			isErrorExpected: true,
+		},
+		{
+			name:     "peers never connect",
+			numNodes: 2,
+			breakNodes: func(fakes []*testutil.FakeGethRpcServer) {
+				fakes[0].SetMethodResult("admin_peers", []interface{}{})
+			},
+			isErrorExpected: true,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			network := newTestNetwork()
+			fakes := addFakeGethNodes(t, network, testCase.numNodes)
+			if testCase.breakNodes != nil {
+				testCase.breakNodes(fakes)
+			}
+
+			err := network.ManuallyConnectPeers()
+			if testCase.isErrorExpected {
+				if err == nil {
+					t.Fatalf("Expected connecting the peers to fail, but it succeeded")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected connecting the peers to succeed, but got an error: %v", err)
+			}
+			for nodeIdx, fake := range fakes {
+				expectedPeerEnodes := []string{}
+				for peerIdx := range fakes {
+					if peerIdx != nodeIdx {
+						expectedPeerEnodes = append(expectedPeerEnodes, getTestEnode(peerIdx))
+					}
+				}
+				actualPeerEnodes := fake.GetPeerEnodes()
+				sort.Strings(actualPeerEnodes)
+				if fmt.Sprint(actualPeerEnodes) != fmt.Sprint(expectedPeerEnodes) {
+					t.Errorf("Expected node %v to be given peers %v, but it was given %v", nodeIdx, expectedPeerEnodes, actualPeerEnodes)
+				}
+			}
+		})
+	}
+}
+
+func TestRequestData(t *testing.T) {
+	testCases := []struct {
+		name string
+		// Output of the request data script, which has the request transaction in it when the request was made
+		requestDataOutput string
+		// How the run the Oracle starts for the request goes
+		runTransitions []oracletest.RunTransition
+		isErrorExpected bool
+	}{
+		{
+			name:              "fulfilled",
+			requestDataOutput: "Using network 'cldev'.\n\nRequest transaction: " + testRequestTxHash + "\n",
+			runTransitions:    oracletest.CompleteRunAfterPolls(1, testFulfillmentTxHash),
+		},
+		{
+			name:              "run errored",
+			requestDataOutput: "Using network 'cldev'.\n\nRequest transaction: " + testRequestTxHash + "\n",
+			runTransitions: []oracletest.RunTransition{
+				{AfterNumPolls: 1, Status: chainlink_oracle.RunStatusErrored, ErrorMessage: "insufficient funds"},
+			},
+			isErrorExpected: true,
+		},
+		{
+			name:              "request not sent",
+			requestDataOutput: "Using network 'cldev'.\n\nError: Returned error: VM Exception while processing transaction: revert\n",
+			isErrorExpected:   true,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			network := newTestNetwork()
+			addFakeGethNodes(t, network, 1)
+			mock := oracletest.NewMockChainlinkServer()
+			defer mock.Close()
+			mock.AddEthKey(testOracleKeyAddress, "1000000000000000000", "0")
+			oracleService := mock.NewOracleService()
+			jobId, err := oracleService.SetJobSpec(testOracleContractAddress)
+			if err != nil {
+				t.Fatalf("Setting the job spec failed: %v", err)
+			}
+
+			// The deployer's request data script is what makes the request on-chain, which the Oracle starts a run for
+			deployerCtx := testutil.NewFakeServiceContext(linkContractDeployerId, testContractDeployerIpAddress)
+			deployerCtx.SetCommandHandler(func(command []string) (int32, string, error) {
+				script := command[len(command) - 1]
+				if !strings.Contains(script, requestDataScriptFragment) {
+					return 0, "", nil
+				}
+				if testCase.runTransitions != nil {
+					runRequest := chainlink_oracle.RunRequest{TxHash: testRequestTxHash}
+					if _, err := mock.AddRun(jobId, runRequest, chainlink_oracle.RunStatusInProgress, testCase.runTransitions); err != nil {
+						return 0, "", err
+					}
+				}
+				return 0, testCase.requestDataOutput, nil
+			})
+
+			network.mutex.Lock()
+			network.oracleContractAddress = testOracleContractAddress
+			network.linkContractDeployerService = chainlink_contract_deployer.NewChainlinkContractDeployerService(deployerCtx)
+			network.priceFeedServer = price_feed_server.NewPriceFeedServerService(
+				testutil.NewFakeServiceContext(priceFeedServerId, testPriceFeedServerIpAddress), testPriceFeedServerPort)
+			network.chainlinkOracleService = oracleService
+			network.priceFeedJobId = jobId
+			network.mutex.Unlock()
+
+			err = network.RequestData()
+			if testCase.isErrorExpected {
+				if err == nil {
+					t.Fatalf("Expected requesting data to fail, but it succeeded")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected the request to be fulfilled, but got an error: %v", err)
+			}
+			// The Oracle's key has to be allowed to fulfill requests before the request is made
+			isKeyPermitted := false
+			for _, command := range deployerCtx.GetExecutedCommands() {
+				script := command[len(command) - 1]
+				if strings.Contains(script, setFulfillmentPermissionsScriptFragment) && strings.Contains(script, testOracleKeyAddress) {
+					isKeyPermitted = true
+				}
+				if strings.Contains(script, requestDataScriptFragment) && !isKeyPermitted {
+					t.Errorf("Expected the Oracle's key to be allowed to fulfill requests before data was requested")
+				}
+			}
+			if !isKeyPermitted {
+				t.Errorf("Expected the Oracle's key %v to be allowed to fulfill requests", testOracleKeyAddress)
+			}
+		})
+	}
+}
+
+/*
+	Adds geth nodes and reads the network's services from several goroutines while transaction load is running, which
+	is how tests use the network; run with -race to catch unguarded state.
+ */
+func TestAddGethNodesUnderLoad(t *testing.T) {
+	networkCtx := newFakeNetworkContext(t)
+	network := NewChainlinkNetwork(networkCtx, "", "", "", "", "", "", NetworkTopology{NumSigners: 1})
+	if err := network.AddBootstrapper(); err != nil {
+		t.Fatalf("Adding the bootstrapper failed: %v", err)
+	}
+	if err := network.StartTransactionLoad(testLoadTransactionsPerSecond, testLoadGasPerTransaction); err != nil {
+		t.Fatalf("Starting transaction load failed: %v", err)
+	}
+
+	stopReadingChan := make(chan struct{})
+	readersWaitGroup := &sync.WaitGroup{}
+	for readerIdx := 0; readerIdx < 2; readerIdx++ {
+		readersWaitGroup.Add(1)
+		go func() {
+			defer readersWaitGroup.Done()
+			for {
+				select {
+				case <-stopReadingChan:
+					return
+				default:
+				}
+				for _, gethService := range network.getAllGethServices() {
+					gethService.GetIPAddress()
+				}
+				network.GetBootstrapper()
+				network.IsGeneratingTransactionLoad()
+			}
+		}()
+	}
+
+	addersWaitGroup := &sync.WaitGroup{}
+	addErrs := make(chan error, numConcurrentGethNodeAdditions)
+	for additionIdx := 0; additionIdx < numConcurrentGethNodeAdditions; additionIdx++ {
+		addersWaitGroup.Add(1)
+		go func() {
+			defer addersWaitGroup.Done()
+			if _, err := network.AddGethService(); err != nil {
+				addErrs <- err
+			}
+		}()
+	}
+	addersWaitGroup.Wait()
+	close(addErrs)
+	for err := range addErrs {
+		t.Errorf("Adding a geth node failed: %v", err)
+	}
+
+	// Give the load generator time to send some transactions while the readers keep going
+	deadline := time.Now().Add(loadTransactionsWaitTimeout)
+	for networkCtx.getNumSentTransactions() < minNumLoadTransactionsSent && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	close(stopReadingChan)
+	readersWaitGroup.Wait()
+
+	stats, err := network.StopTransactionLoad()
+	if err != nil {
+		t.Fatalf("Stopping transaction load failed: %v", err)
+	}
+	if stats.NumSent < minNumLoadTransactionsSent {
+		t.Errorf("Expected at least %v load transactions to be sent, but %v were", minNumLoadTransactionsSent, stats.NumSent)
+	}
+	if numReceived := networkCtx.getNumSentTransactions(); int64(numReceived) != stats.NumSent {
+		t.Errorf("Expected the nodes to receive the %v load transactions sent, but they received %v", stats.NumSent, numReceived)
+	}
+	gethServices := network.GetGethServices()
+	if len(gethServices) != numConcurrentGethNodeAdditions {
+		t.Fatalf("Expected %v geth nodes besides the bootstrapper, but got %v", numConcurrentGethNodeAdditions, len(gethServices))
+	}
+	for nodeIdx := 0; nodeIdx < numConcurrentGethNodeAdditions; nodeIdx++ {
+		serviceId := services.ServiceID(gethServiceIdPrefix + fmt.Sprint(nodeIdx))
+		if _, found := gethServices[serviceId]; !found {
+			t.Errorf("Expected a geth node with ID %v, but the nodes were %v", serviceId, gethServices)
+		}
+	}
+}
+
+// ==========================================================================================
+//								Helper methods
+// ==========================================================================================
+
+/*
+	A network that only has the services tests give it; anything that would add a service to Kurtosis panics.
+ */
+func newTestNetwork() *ChainlinkNetwork {
+	return NewChainlinkNetwork(nil, "", "", "", "", "", "", NetworkTopology{})
+}
+
+/*
+	Gives the network a bootstrapper and then regular geth nodes backed by fake RPC servers, which are closed when the
+	test ends. The returned fakes are in that order.
+ */
+func addFakeGethNodes(t *testing.T, network *ChainlinkNetwork, numNodes int) []*testutil.FakeGethRpcServer {
+	fakes := []*testutil.FakeGethRpcServer{}
+	for nodeIdx := 0; nodeIdx < numNodes; nodeIdx++ {
+		fake := testutil.NewFakeGethRpcServer(getTestEnode(nodeIdx))
+		t.Cleanup(fake.Close)
+		fakes = append(fakes, fake)
+
+		serviceId := services.ServiceID(gethServiceIdPrefix + fmt.Sprint(nodeIdx))
+		if nodeIdx == 0 {
+			serviceId = ethereumBootstrapperId
+		}
+		gethService := geth.NewGethService(fake.NewServiceContext(serviceId), fake.GetPort(), testGethWsPort, testGethMetricsPort)
+
+		network.mutex.Lock()
+		if nodeIdx == 0 {
+			network.gethBootsrapperService = gethService
+		} else {
+			network.gethServices[serviceId] = gethService
+		}
+		network.mutex.Unlock()
+	}
+	return fakes
+}
+
+func getTestEnode(nodeIdx int) string {
+	return fmt.Sprintf("enode://%0128x@172.23.0.%v:30303", nodeIdx + 1, nodeIdx + 2)
+}
+
+/*
+	Stands in for Kurtosis by backing every geth node the network adds with a fake RPC server, which is closed when the
+	test ends. Safe for concurrent use.
+ */
+type fakeNetworkContext struct {
+	t *testing.T
+
+	// Mutex protecting the fakes
+	mutex *sync.Mutex
+	fakes map[services.ServiceID]*testutil.FakeGethRpcServer
+}
+
+func newFakeNetworkContext(t *testing.T) *fakeNetworkContext {
+	return &fakeNetworkContext{
+		t:     t,
+		mutex: &sync.Mutex{},
+		fakes: map[services.ServiceID]*testutil.FakeGethRpcServer{},
+	}
+}
+
+func (networkCtx *fakeNetworkContext) AddService(serviceId services.ServiceID, initializer services.DockerContainerInitializer) (services.Service, services.AvailabilityChecker, error) {
+	if _, isGethNode := initializer.(*geth.GethContainerInitializer); !isGethNode {
+		return nil, nil, fmt.Errorf("the fake network context can only add geth nodes, but was asked to add %v", serviceId)
+	}
+	networkCtx.mutex.Lock()
+	defer networkCtx.mutex.Unlock()
+	if _, found := networkCtx.fakes[serviceId]; found {
+		return nil, nil, fmt.Errorf("service %v has already been added", serviceId)
+	}
+	fake := testutil.NewFakeGethRpcServer(getTestEnode(len(networkCtx.fakes)))
+	networkCtx.t.Cleanup(fake.Close)
+	networkCtx.fakes[serviceId] = fake
+	gethService := geth.NewGethService(fake.NewServiceContext(serviceId), fake.GetPort(), testGethWsPort, testGethMetricsPort)
+	return gethService, startedAvailabilityChecker{}, nil
+}
+
+func (networkCtx *fakeNetworkContext) RemoveService(serviceId services.ServiceID, containerStopTimeoutSeconds uint64) error {
+	return fmt.Errorf("the fake network context can't remove services, but was asked to remove %v", serviceId)
+}
+
+/*
+	Transactions sent to any of the fake nodes so far.
+ */
+func (networkCtx *fakeNetworkContext) getNumSentTransactions() int {
+	networkCtx.mutex.Lock()
+	defer networkCtx.mutex.Unlock()
+	numSentTransactions := 0
+	for _, fake := range networkCtx.fakes {
+		numSentTransactions += fake.GetNumSentTransactions()
+	}
+	return numSentTransactions
+}
+
+// Fake services are up as soon as they're added
+type startedAvailabilityChecker struct {}
+
+func (checker startedAvailabilityChecker) WaitForStartup(timeBetweenPolls time.Duration, maxNumRetries int) error {
+	return nil
+}
@@ -0,0 +1,198 @@
+package networks_impl
+
+import (
+	"encoding/json"
+	"github.com/kurtosistech/chainlink-testing/testsuite/services_impl/chainlink_oracle"
+	"github.com/kurtosistech/chainlink-testing/testsuite/services_impl/geth"
+	"github.com/palantir/stacktrace"
+	"github.com/sirupsen/logrus"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+)
+
+const (
+	failureArtifactsDirname = "failure-artifacts"
+
+	artifactDirPerms = 0755
+	artifactFilePerms = 0644
+
+	truffleOutputFilename = "contract-deployer-commands.log"
+	artifactErrorsFilename = "artifact-collection-errors.txt"
+	gethSnapshotFilenameSuffix = "-rpc-snapshot.json"
+)
+
+// Chainlink tables that capture the state of jobs, runs and the transactions they sent
+var oracleTablesToDump = []string{
+	"job_specs",
+	"initiators",
+	"task_specs",
+	"job_runs",
+	"task_runs",
+	"run_requests",
+	"run_results",
+	"eth_txes",
+	"eth_tx_attempts",
+	"keys",
+	"heads",
+}
+
+// Artifact filename -> Oracle operator API endpoint to save the response of
+var oracleApiEndpointsToDump = map[string]string{
+	"oracle-runs.json":     chainlink_oracle.RunsEndpoint,
+	"oracle-jobs.json":     chainlink_oracle.JobSpecsEndpoint,
+	"oracle-eth-keys.json": chainlink_oracle.EthKeysEndpoint,
+}
+
+// RPC methods to snapshot the result of on every geth node
+var gethRpcMethodsToSnapshot = []string{
+	"eth_blockNumber",
+	"admin_peers",
+	"txpool_status",
+}
+
+/*
+	Meant to be deferred at the start of a test's Run. Kurtosis fails tests by panicking, so if a panic is in flight
+	this dumps an artifact bundle for the test into the suite execution volume before letting the panic continue.
+ */
+func (network *ChainlinkNetwork) DumpArtifactsOnFailure(testName string) {
+	panicValue := recover()
+	if panicValue == nil {
+		return
+	}
+	label := testName
+	if network.topology.VersionLabel != "" {
+		label = testName + "_" + network.topology.VersionLabel
+	}
+	logrus.Errorf("Test %v failed; collecting failure artifacts...", label)
+	artifactsDirpath, err := network.DumpArtifacts(label)
+	if err != nil {
+		logrus.Errorf("An error occurred collecting failure artifacts: %v", err)
+	} else {
+		logrus.Infof("Failure artifacts written to %v", artifactsDirpath)
+	}
+	panic(panicValue)
+}
+
+/*
+	Dumps service logs, Oracle API responses, geth RPC snapshots, Oracle database tables and the contract deployer's
+	truffle output into a directory for the given label on the suite execution volume, returning the directory path.
+	Collection is best-effort: whatever can't be collected is listed in an errors file in the bundle, since the
+	network is usually in a bad state when this is called.
+ */
+func (network *ChainlinkNetwork) DumpArtifacts(label string) (string, error) {
+	relativeDirpath := path.Join(failureArtifactsDirname, label)
+	artifactsDirpath := path.Join(geth.SuiteExecutionVolumeMountpoint, relativeDirpath)
+	if err := os.MkdirAll(artifactsDirpath, artifactDirPerms); err != nil {
+		return "", stacktrace.Propagate(err, "An error occurred creating artifacts directory '%v'", artifactsDirpath)
+	}
+
+	collectionErrs := []error{}
+	collectionErrs = append(collectionErrs, network.dumpGethArtifacts(relativeDirpath)...)
+	collectionErrs = append(collectionErrs, network.dumpOracleArtifacts(relativeDirpath)...)
+	collectionErrs = append(collectionErrs, network.dumpPostgresArtifacts(relativeDirpath)...)
+	if linkContractDeployerService := network.getLinkContractDeployer(); linkContractDeployerService != nil {
+		truffleOutput := []byte(linkContractDeployerService.GetCommandLog())
+		if err := writeArtifactFile(relativeDirpath, truffleOutputFilename, truffleOutput); err != nil {
+			collectionErrs = append(collectionErrs, err)
+		}
+	}
+
+	if len(collectionErrs) > 0 {
+		errStrs := []string{}
+		for _, err := range collectionErrs {
+			logrus.Warnf("Couldn't collect a failure artifact: %v", err)
+			errStrs = append(errStrs, err.Error())
+		}
+		if err := writeArtifactFile(relativeDirpath, artifactErrorsFilename, []byte(strings.Join(errStrs, "\n\n"))); err != nil {
+			return "", stacktrace.Propagate(err, "An error occurred writing the list of artifact collection errors")
+		}
+	}
+	return artifactsDirpath, nil
+}
+
+// ==========================================================================================
+//								Helper methods
+// ==========================================================================================
+
+func (network *ChainlinkNetwork) dumpGethArtifacts(relativeDirpath string) []error {
+	collectionErrs := []error{}
+	for serviceId, gethService := range network.getAllGethServices() {
+		serviceDirpath := path.Join(relativeDirpath, string(serviceId))
+		if err := gethService.CopyLogsToTestVolume(serviceDirpath); err != nil {
+			collectionErrs = append(collectionErrs, stacktrace.Propagate(err, "An error occurred copying the logs of %v", serviceId))
+		}
+
+		snapshot := map[string]json.RawMessage{}
+		for _, method := range gethRpcMethodsToSnapshot {
+			result, err := gethService.GetRawRpcResult(method, []interface{}{})
+			if err != nil {
+				collectionErrs = append(collectionErrs, stacktrace.Propagate(err, "An error occurred snapshotting %v on %v", method, serviceId))
+				continue
+			}
+			snapshot[method] = result
+		}
+		snapshotBytes, err := json.MarshalIndent(snapshot, "", "  ")
+		if err != nil {
+			collectionErrs = append(collectionErrs, stacktrace.Propagate(err, "An error occurred serializing the RPC snapshot of %v", serviceId))
+			continue
+		}
+		if err := writeArtifactFile(serviceDirpath, string(serviceId) + gethSnapshotFilenameSuffix, snapshotBytes); err != nil {
+			collectionErrs = append(collectionErrs, err)
+		}
+	}
+	return collectionErrs
+}
+
+func (network *ChainlinkNetwork) dumpOracleArtifacts(relativeDirpath string) []error {
+	collectionErrs := []error{}
+	for serviceId, oracleService := range network.GetOracleServices() {
+		serviceDirpath := path.Join(relativeDirpath, string(serviceId))
+		if err := oracleService.CopyLogsToTestVolume(serviceDirpath); err != nil {
+			collectionErrs = append(collectionErrs, stacktrace.Propagate(err, "An error occurred copying the logs of Oracle %v", serviceId))
+		}
+		for filename, endpoint := range oracleApiEndpointsToDump {
+			responseBytes, err := oracleService.GetRawApiResponse(endpoint)
+			if err != nil {
+				collectionErrs = append(collectionErrs, stacktrace.Propagate(err, "An error occurred getting endpoint %v of Oracle %v", endpoint, serviceId))
+				continue
+			}
+			if err := writeArtifactFile(serviceDirpath, filename, responseBytes); err != nil {
+				collectionErrs = append(collectionErrs, err)
+			}
+		}
+	}
+	return collectionErrs
+}
+
+func (network *ChainlinkNetwork) dumpPostgresArtifacts(relativeDirpath string) []error {
+	postgresService, databaseName := network.getOracleDatabase()
+	if postgresService == nil || databaseName == "" {
+		return []error{}
+	}
+	serviceDirpath := path.Join(relativeDirpath, string(postgresId))
+	collectionErrs := []error{}
+	for _, tableName := range oracleTablesToDump {
+		err := postgresService.DumpTableToTestVolume(databaseName, tableName, serviceDirpath)
+		if err != nil {
+			collectionErrs = append(collectionErrs, stacktrace.Propagate(err, "An error occurred dumping table %v", tableName))
+		}
+	}
+	return collectionErrs
+}
+
+/*
+	Writes a file from the testsuite side, into a directory relative to the root of the suite execution volume.
+ */
+func writeArtifactFile(relativeDirpath string, filename string, contents []byte) error {
+	dirpath := path.Join(geth.SuiteExecutionVolumeMountpoint, relativeDirpath)
+	if err := os.MkdirAll(dirpath, artifactDirPerms); err != nil {
+		return stacktrace.Propagate(err, "An error occurred creating artifacts directory '%v'", dirpath)
+	}
+	filepath := path.Join(dirpath, filename)
+	if err := ioutil.WriteFile(filepath, contents, artifactFilePerms); err != nil {
+		return stacktrace.Propagate(err, "An error occurred writing artifact file '%v'", filepath)
+	}
+	return nil
+}
@@ -0,0 +1,19 @@
+package networks_impl
+
+import (
+	"github.com/kurtosis-tech/kurtosis-libs/golang/lib/networks"
+	"github.com/kurtosis-tech/kurtosis-libs/golang/lib/services"
+)
+
+/*
+	The parts of the Kurtosis network context that ChainlinkNetwork uses. Depending on this rather than the Kurtosis
+	struct lets the network be handed stand-in services, so its logic can run without live containers.
+ */
+type NetworkContext interface {
+	AddService(serviceId services.ServiceID, initializer services.DockerContainerInitializer) (services.Service, services.AvailabilityChecker, error)
+
+	RemoveService(serviceId services.ServiceID, containerStopTimeoutSeconds uint64) error
+}
+
+// The real Kurtosis network context is what the network gets in the testsuite
+var _ NetworkContext = (*networks.NetworkContext)(nil)
@@ -0,0 +1,168 @@
+package networks_impl
+
+import (
+	"github.com/kurtosistech/chainlink-testing/testsuite/services_impl/geth"
+	"github.com/palantir/stacktrace"
+	"strings"
+)
+
+const (
+	// What the network uses prefunded accounts for, as named in topology errors
+	contractOwnerAccountRole = "contract owner"
+	loadSenderAccountRole = "transaction load sender"
+	signerAccountRole = "clique signer"
+	oracleImportedAccountRole = "oracle imported account"
+
+	defaultNumGethNodes = 3
+	defaultNumSigners = 1
+	defaultNumOracles = 1
+
+	// Contracts are deployed through the bootstrapper, but the network needs at least one other node to deploy them
+	minNumGethNodes = 2
+	// Keeps the network comfortably inside the testsuite's network width, alongside the other services
+	maxNumGethNodes = 16
+	maxNumOracles = 8
+)
+
+/*
+	How many of each service a ChainlinkNetwork is made of, and which images they run.
+ */
+type NetworkTopology struct {
+	// Total number of geth nodes, including the bootstrapper
+	NumGethNodes int
+
+	// Number of geth nodes that seal blocks as clique signers, starting with the bootstrapper
+	NumSigners int
+
+	NumOracles int
+
+	// Images for individual geth nodes by index, where 0 is the bootstrapper; missing or empty entries use the
+	// network's geth image
+	GethNodeImages []string
+
+	// Images for individual oracles by index, where 0 is the primary oracle; missing or empty entries use the
+	// network's oracle image
+	OracleImages []string
+
+	// Prefunded accounts the primary oracle may import with ChainlinkNetwork.ImportGethAccountIntoOracle. The oracle
+	// sends transactions from the accounts it imports, so they can't have any other role in the network.
+	OracleImportedAccounts []string
+
+	// Environment variables set on every oracle, replacing the testsuite's defaults for the same variables (e.g.
+	// chainlink_oracle.MinIncomingConfirmationsEnvVar)
+	OracleEnvironment map[string]string
+
+	// Identifies the geth and oracle versions the network runs when the testsuite runs tests against several of them,
+	// so per-test outputs like failure artifacts don't collide across versions; empty otherwise
+	VersionLabel string
+}
+
+func NewDefaultNetworkTopology() NetworkTopology {
+	return NetworkTopology{
+		NumGethNodes:   defaultNumGethNodes,
+		NumSigners:     defaultNumSigners,
+		NumOracles:     defaultNumOracles,
+		GethNodeImages: []string{},
+		OracleImages:   []string{},
+		OracleImportedAccounts: []string{},
+		OracleEnvironment: map[string]string{},
+	}
+}
+
+/*
+	Returns a copy of the topology whose primary oracle may import the given account; the original is left untouched,
+	since topologies are shared between tests.
+ */
+func (topology NetworkTopology) WithOracleImportedAccount(address string) NetworkTopology {
+	topology.OracleImportedAccounts = append(append([]string{}, topology.OracleImportedAccounts...), address)
+	return topology
+}
+
+/*
+	Returns a copy of the topology whose oracles get the given environment variable; the original is left untouched,
+	since topologies are shared between tests.
+ */
+func (topology NetworkTopology) WithOracleEnvironment(name string, value string) NetworkTopology {
+	oracleEnvironment := map[string]string{}
+	for existingName, existingValue := range topology.OracleEnvironment {
+		oracleEnvironment[existingName] = existingValue
+	}
+	oracleEnvironment[name] = value
+	topology.OracleEnvironment = oracleEnvironment
+	return topology
+}
+
+func (topology NetworkTopology) Validate() error {
+	if topology.NumGethNodes < minNumGethNodes || topology.NumGethNodes > maxNumGethNodes {
+		return stacktrace.NewError("Number of geth nodes must be between %v and %v, but was %v", minNumGethNodes, maxNumGethNodes, topology.NumGethNodes)
+	}
+	maxNumSigners := len(geth.SignerCandidateAddresses)
+	if topology.NumGethNodes < maxNumSigners {
+		maxNumSigners = topology.NumGethNodes
+	}
+	if topology.NumSigners < 1 || topology.NumSigners > maxNumSigners {
+		return stacktrace.NewError("Number of signers must be between 1 and %v, but was %v", maxNumSigners, topology.NumSigners)
+	}
+	if topology.NumOracles < 1 || topology.NumOracles > maxNumOracles {
+		return stacktrace.NewError("Number of oracles must be between 1 and %v, but was %v", maxNumOracles, topology.NumOracles)
+	}
+	if len(topology.GethNodeImages) > topology.NumGethNodes {
+		return stacktrace.NewError("Got images for %v geth nodes, but the network only has %v", len(topology.GethNodeImages), topology.NumGethNodes)
+	}
+	if len(topology.OracleImages) > topology.NumOracles {
+		return stacktrace.NewError("Got images for %v oracles, but the network only has %v", len(topology.OracleImages), topology.NumOracles)
+	}
+	accountRoles := topology.getAccountRoles()
+	for _, address := range topology.OracleImportedAccounts {
+		if !containsAddress(geth.KeystoreAddresses, address) {
+			return stacktrace.NewError("Account %v can't be imported into the oracle, since it isn't a prefunded account with a key in the nodes' keystores", address)
+		}
+		if role, found := accountRoles[strings.ToLower(address)]; found {
+			return stacktrace.NewError("Account %v can't be imported into the oracle, since it's already a %v and their transactions' nonces would clash", address, role)
+		}
+		accountRoles[strings.ToLower(address)] = oracleImportedAccountRole
+	}
+	return nil
+}
+
+/*
+	The accounts that seal blocks in a network of this topology, which every node needs for its genesis.
+ */
+func (topology NetworkTopology) GetSignerAddresses() []string {
+	return geth.SignerCandidateAddresses[:topology.NumSigners]
+}
+
+// ==========================================================================================
+//								Helper methods
+// ==========================================================================================
+
+func getImageOverride(images []string, idx int, defaultImage string) string {
+	if idx < len(images) && strings.TrimSpace(images[idx]) != "" {
+		return images[idx]
+	}
+	return defaultImage
+}
+
+/*
+	What the network uses each prefunded account for besides oracle imports, by lowercased address.
+ */
+func (topology NetworkTopology) getAccountRoles() map[string]string {
+	accountRoles := map[string]string{}
+	for _, address := range topology.GetSignerAddresses() {
+		accountRoles[strings.ToLower(address)] = signerAccountRole
+	}
+	for _, address := range loadSenderAddresses {
+		accountRoles[strings.ToLower(address)] = loadSenderAccountRole
+	}
+	accountRoles[strings.ToLower(contractOwnerAddress)] = contractOwnerAccountRole
+	return accountRoles
+}
+
+func containsAddress(addresses []string, target string) bool {
+	for _, address := range addresses {
+		if strings.EqualFold(address, target) {
+			return true
+		}
+	}
+	return false
+}
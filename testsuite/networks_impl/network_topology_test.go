@@ -0,0 +1,103 @@
+package networks_impl
+
+import (
+	"github.com/kurtosistech/chainlink-testing/testsuite/services_impl/geth"
+	"strings"
+	"testing"
+)
+
+const (
+	// Well-formed, but not one of the prefunded accounts
+	testUnfundedAddress = "0x000000000000000000000000000000000000dead"
+)
+
+func TestValidateTopology(t *testing.T) {
+	testCases := []struct {
+		name string
+		topology NetworkTopology
+		isErrorExpected bool
+	}{
+		{
+			name:     "default",
+			topology: NewDefaultNetworkTopology(),
+		},
+		{
+			name:            "too few geth nodes",
+			topology:        withNumGethNodes(NewDefaultNetworkTopology(), 1),
+			isErrorExpected: true,
+		},
+		{
+			name:            "more signers than signer candidates",
+			topology:        withNumSigners(withNumGethNodes(NewDefaultNetworkTopology(), 8), len(geth.SignerCandidateAddresses) + 1),
+			isErrorExpected: true,
+		},
+		{
+			name:     "key import account imported",
+			topology: NewDefaultNetworkTopology().WithOracleImportedAccount(geth.KeyImportFundedAddress),
+		},
+		{
+			name:     "key import account imported in another case",
+			topology: NewDefaultNetworkTopology().WithOracleImportedAccount(strings.ToUpper(geth.KeyImportFundedAddress)),
+		},
+		{
+			name:            "contract owner imported",
+			topology:        NewDefaultNetworkTopology().WithOracleImportedAccount(geth.FirstFundedAddress),
+			isErrorExpected: true,
+		},
+		{
+			name:            "load sender imported",
+			topology:        NewDefaultNetworkTopology().WithOracleImportedAccount(geth.ThirdFundedAddress),
+			isErrorExpected: true,
+		},
+		{
+			name:            "signer imported",
+			topology:        withNumSigners(NewDefaultNetworkTopology(), 2).WithOracleImportedAccount(geth.SecondFundedAddress),
+			isErrorExpected: true,
+		},
+		{
+			name: "account imported twice",
+			topology: NewDefaultNetworkTopology().
+				WithOracleImportedAccount(geth.KeyImportFundedAddress).
+				WithOracleImportedAccount(geth.KeyImportFundedAddress),
+			isErrorExpected: true,
+		},
+		{
+			name:            "account without a key imported",
+			topology:        NewDefaultNetworkTopology().WithOracleImportedAccount(testUnfundedAddress),
+			isErrorExpected: true,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := testCase.topology.Validate()
+			if testCase.isErrorExpected && err == nil {
+				t.Errorf("Expected topology %+v to be rejected, but it was valid", testCase.topology)
+			}
+			if !testCase.isErrorExpected && err != nil {
+				t.Errorf("Expected topology %+v to be valid, but got an error: %v", testCase.topology, err)
+			}
+		})
+	}
+}
+
+func TestWithOracleImportedAccountLeavesOriginalUntouched(t *testing.T) {
+	original := NewDefaultNetworkTopology().WithOracleImportedAccount(geth.KeyImportFundedAddress)
+	original.WithOracleImportedAccount(geth.SecondFundedAddress)
+	if len(original.OracleImportedAccounts) != 1 {
+		t.Errorf("Expected the original topology to keep its 1 imported account, but it has %v", original.OracleImportedAccounts)
+	}
+}
+
+// ==========================================================================================
+//								Helper methods
+// ==========================================================================================
+
+func withNumGethNodes(topology NetworkTopology, numGethNodes int) NetworkTopology {
+	topology.NumGethNodes = numGethNodes
+	return topology
+}
+
+func withNumSigners(topology NetworkTopology, numSigners int) NetworkTopology {
+	topology.NumSigners = numSigners
+	return topology
+}
@@ -0,0 +1,83 @@
+package networks_impl
+
+import (
+	"github.com/kurtosistech/chainlink-testing/testsuite/services_impl/geth"
+	"github.com/palantir/stacktrace"
+	"math/big"
+	"strings"
+)
+
+const (
+	// Topic of the event the Oracle contract emits for every request, i.e. the keccak256 of
+	// OracleRequest(bytes32,address,bytes32,uint256,address,bytes4,uint256,uint256,bytes)
+	oracleRequestEventTopic = "0xd8d7ecc4800d25fa53ce0372f13a416d98907a7ef3d8d3bdd79cf4fe75529c65"
+
+	// Positions of the OracleRequest event's fields among the words of its data; the spec ID is an indexed topic
+	oracleRequestRequesterWordIndex = 0
+	oracleRequestIdWordIndex = 1
+	oracleRequestPaymentWordIndex = 2
+	oracleRequestCallbackAddressWordIndex = 3
+	oracleRequestCallbackFunctionIdWordIndex = 4
+	oracleRequestCancelExpirationWordIndex = 5
+	oracleRequestMinNumWords = 6
+
+	// Addresses take up the last 20 bytes of their word, and function selectors the first 4 bytes of theirs
+	addressWordOffsetHexChars = 24
+	functionSelectorHexChars = 8
+)
+
+/*
+	A request to the Oracle contract, as recorded in the OracleRequest event it emitted; cancelling or fulfilling the
+	request takes these same parameters.
+ */
+type OracleRequest struct {
+	// Consumer contract that made the request, and gets the payment back if it cancels
+	Requester string
+	// 0x-prefixed bytes32
+	RequestId string
+	// In juels, i.e. 10^-18 $LINK
+	Payment *big.Int
+	CallbackAddress string
+	// 0x-prefixed bytes4 selector of the function called on the callback address to fulfill the request
+	CallbackFunctionId string
+	// Unix time after which the requester may cancel the request
+	CancelExpiration uint64
+}
+
+/*
+	Finds the OracleRequest event the Oracle contract at the given address emitted in the given transaction.
+ */
+func parseOracleRequest(receipt *geth.TransactionReceipt, oracleContractAddress string) (OracleRequest, error) {
+	for _, log := range receipt.Logs {
+		if !strings.EqualFold(log.Address, oracleContractAddress) || len(log.Topics) == 0 ||
+				!strings.EqualFold(log.Topics[0], oracleRequestEventTopic) {
+			continue
+		}
+		words, err := geth.SplitAbiWords(log.Data)
+		if err != nil {
+			return OracleRequest{}, stacktrace.Propagate(err, "Couldn't split the OracleRequest event data into words.")
+		}
+		if len(words) < oracleRequestMinNumWords {
+			return OracleRequest{}, stacktrace.NewError("Expected the OracleRequest event data to have at least %v words, but it had %v.",
+				oracleRequestMinNumWords, len(words))
+		}
+		payment, err := geth.ParseHexUint256(words[oracleRequestPaymentWordIndex])
+		if err != nil {
+			return OracleRequest{}, stacktrace.Propagate(err, "Couldn't parse the OracleRequest event's payment.")
+		}
+		cancelExpiration, err := geth.ParseHexQuantity(words[oracleRequestCancelExpirationWordIndex])
+		if err != nil {
+			return OracleRequest{}, stacktrace.Propagate(err, "Couldn't parse the OracleRequest event's cancel expiration.")
+		}
+		return OracleRequest{
+			Requester:          "0x" + words[oracleRequestRequesterWordIndex][addressWordOffsetHexChars:],
+			RequestId:          "0x" + words[oracleRequestIdWordIndex],
+			Payment:            payment,
+			CallbackAddress:    "0x" + words[oracleRequestCallbackAddressWordIndex][addressWordOffsetHexChars:],
+			CallbackFunctionId: "0x" + words[oracleRequestCallbackFunctionIdWordIndex][:functionSelectorHexChars],
+			CancelExpiration:   cancelExpiration,
+		}, nil
+	}
+	return OracleRequest{}, stacktrace.NewError("Transaction %v has no OracleRequest event from Oracle contract %v.",
+		receipt.TransactionHash, oracleContractAddress)
+}
@@ -0,0 +1,276 @@
+package scenarios
+
+import (
+	"fmt"
+	"github.com/kurtosistech/chainlink-testing/testsuite/networks_impl"
+	"github.com/palantir/stacktrace"
+	"github.com/sirupsen/logrus"
+	"time"
+)
+
+const (
+	// Keys of the jobs the job steps add in State.JobIds
+	PriceFeedJobName = "priceFeed"
+	CronJobName = "cron"
+	WebJobName = "web"
+	EthLogJobName = "ethLog"
+
+	connectGethPeersRetries = 2
+	timeBetweenConnectGethPeersRetries = 5 * time.Second
+
+	terminateDatabaseConnectionsRetries = 2
+	timeBetweenTerminateDatabaseConnectionsRetries = 2 * time.Second
+)
+
+/*
+	Connects every geth node to every other one. Connecting already-connected peers is harmless, so this is retried.
+ */
+func ConnectGethPeersStep() Step {
+	return NewStep("connect geth peers", func(network *networks_impl.ChainlinkNetwork, state *State) error {
+		return network.ManuallyConnectPeers()
+	}).WithRetries(connectGethPeersRetries, timeBetweenConnectGethPeersRetries)
+}
+
+/*
+	Deploys the $LINK token, Oracle and consumer contracts, recording their addresses in the state.
+ */
+func DeployContractsStep() Step {
+	return NewStep("deploy contracts", func(network *networks_impl.ChainlinkNetwork, state *State) error {
+		if err := network.DeployChainlinkContract(); err != nil {
+			return stacktrace.Propagate(err, "An error occurred deploying the contracts")
+		}
+		state.LinkContractAddress = network.GetLinkContractAddress()
+		state.OracleContractAddress = network.GetOracleContractAddress()
+		state.ConsumerContractAddress = network.GetConsumerContractAddress()
+		logrus.Infof("Deployed the $LINK contract at %v, the Oracle contract at %v and the consumer contract at %v.",
+			state.LinkContractAddress, state.OracleContractAddress, state.ConsumerContractAddress)
+		return nil
+	})
+}
+
+/*
+	Deploys the SimpleConsumer contract that cron and web initiated jobs write their results to.
+ */
+func DeploySimpleConsumerStep() Step {
+	return NewStep("deploy simple consumer", func(network *networks_impl.ChainlinkNetwork, state *State) error {
+		return network.DeploySimpleConsumer()
+	})
+}
+
+/*
+	Deploys the EventEmitter contract whose events start EthLog jobs.
+ */
+func DeployEventEmitterStep() Step {
+	return NewStep("deploy event emitter", func(network *networks_impl.ChainlinkNetwork, state *State) error {
+		return network.DeployEventEmitter()
+	})
+}
+
+func FundLinkWalletStep() Step {
+	return NewStep("fund $LINK wallet", func(network *networks_impl.ChainlinkNetwork, state *State) error {
+		return network.FundLinkWallet()
+	})
+}
+
+/*
+	Funds the consumer contract with the given amount of $LINK, in juels, rather than the default amount.
+ */
+func FundLinkWalletWithAmountStep(linkAmountJuels string) Step {
+	return NewStep(fmt.Sprintf("fund $LINK wallet with %v juels", linkAmountJuels), func(network *networks_impl.ChainlinkNetwork, state *State) error {
+		return network.FundLinkWalletWithAmount(linkAmountJuels)
+	})
+}
+
+/*
+	Starts the Oracles the network's topology asks for.
+ */
+func StartOracleStep() Step {
+	return NewStep("start oracle", func(network *networks_impl.ChainlinkNetwork, state *State) error {
+		if err := network.AddOracleService(); err != nil {
+			return stacktrace.Propagate(err, "An error occurred adding the Oracle")
+		}
+		logrus.Infof("Chainlink Oracle started and responsive on: %v:%v",
+			network.GetChainlinkOracle().GetIPAddress(),
+			network.GetChainlinkOracle().GetOperatorPort())
+		return nil
+	})
+}
+
+func FundOracleEthAccountsStep() Step {
+	return NewStep("fund oracle eth accounts", func(network *networks_impl.ChainlinkNetwork, state *State) error {
+		return network.FundOracleEthAccounts()
+	})
+}
+
+func StartMetricsCollectionStep() Step {
+	return NewStep("start metrics collection", func(network *networks_impl.ChainlinkNetwork, state *State) error {
+		return network.StartMetricsCollection()
+	})
+}
+
+/*
+	Adds the price feed job that on-chain requests are made for, recording its ID in the state under PriceFeedJobName.
+ */
+func DeployPriceFeedJobStep() Step {
+	return NewStep("set job spec", func(network *networks_impl.ChainlinkNetwork, state *State) error {
+		if err := network.DeployOracleJob(); err != nil {
+			return stacktrace.Propagate(err, "An error occurred deploying the price feed job")
+		}
+		state.JobIds[PriceFeedJobName] = network.GetPriceFeedJobId()
+		return nil
+	})
+}
+
+/*
+	Adds a job run on the given cron schedule, recording its ID in the state under CronJobName.
+ */
+func AddCronJobStep(schedule string) Step {
+	return NewStep("add cron job", func(network *networks_impl.ChainlinkNetwork, state *State) error {
+		jobId, err := network.AddCronJob(schedule)
+		if err != nil {
+			return stacktrace.Propagate(err, "An error occurred adding the cron job")
+		}
+		state.JobIds[CronJobName] = jobId
+		logrus.Infof("Added cron job %v on schedule '%v'.", jobId, schedule)
+		return nil
+	})
+}
+
+/*
+	Adds a job run through the Oracle's API, recording its ID in the state under WebJobName.
+ */
+func AddWebJobStep() Step {
+	return NewStep("add web initiated job", func(network *networks_impl.ChainlinkNetwork, state *State) error {
+		jobId, err := network.AddWebJob()
+		if err != nil {
+			return stacktrace.Propagate(err, "An error occurred adding the web initiated job")
+		}
+		state.JobIds[WebJobName] = jobId
+		return nil
+	})
+}
+
+/*
+	Adds a job started by the EventEmitter's events, recording its ID in the state under EthLogJobName.
+ */
+func AddEthLogJobStep() Step {
+	return NewStep("add EthLog job", func(network *networks_impl.ChainlinkNetwork, state *State) error {
+		jobId, err := network.AddEthLogJob()
+		if err != nil {
+			return stacktrace.Propagate(err, "An error occurred adding the EthLog job")
+		}
+		state.JobIds[EthLogJobName] = jobId
+		return nil
+	})
+}
+
+/*
+	Requests data on-chain from every Oracle and waits for the requests to be fulfilled.
+ */
+func RequestDataStep() Step {
+	return NewStep("fulfill request", func(network *networks_impl.ChainlinkNetwork, state *State) error {
+		return network.RequestData()
+	})
+}
+
+/*
+	Makes the price feed server fail every request, or serve prices again.
+ */
+func SetPriceFeedFailingStep(isFailing bool) Step {
+	return NewStep(fmt.Sprintf("set price feed failing to %v", isFailing), func(network *networks_impl.ChainlinkNetwork, state *State) error {
+		return network.SetPriceFeedFailing(isFailing)
+	})
+}
+
+/*
+	Replaces the primary Oracle with one on the given image, against the same database.
+ */
+func UpgradeOracleStep(image string) Step {
+	return NewStep(fmt.Sprintf("upgrade oracle to image '%v'", image), func(network *networks_impl.ChainlinkNetwork, state *State) error {
+		return network.UpgradeOracle(image)
+	})
+}
+
+/*
+	Starts transaction load that keeps running until it's stopped, or until the scenario's cleanup stops it.
+ */
+func StartTransactionLoadStep(targetTransactionsPerSecond int, gasPerTransaction uint64) Step {
+	return NewStep("start transaction load", func(network *networks_impl.ChainlinkNetwork, state *State) error {
+		return network.StartTransactionLoad(targetTransactionsPerSecond, gasPerTransaction)
+	}).WithCleanup(func(network *networks_impl.ChainlinkNetwork, state *State) error {
+		// A later step may already have stopped it to get the stats
+		if !network.IsGeneratingTransactionLoad() {
+			return nil
+		}
+		if _, err := network.StopTransactionLoad(); err != nil {
+			return stacktrace.Propagate(err, "An error occurred stopping the transaction load")
+		}
+		return nil
+	})
+}
+
+/*
+	Stops the transaction load a StartTransactionLoadStep started, logging what was sent.
+ */
+func StopTransactionLoadStep() Step {
+	return NewStep("stop transaction load", func(network *networks_impl.ChainlinkNetwork, state *State) error {
+		loadStats, err := network.StopTransactionLoad()
+		if err != nil {
+			return stacktrace.Propagate(err, "An error occurred stopping the transaction load")
+		}
+		logrus.Infof("Load generator submitted %v transactions with %v failures over %v.", loadStats.NumSent, loadStats.NumFailed, loadStats.Duration)
+		return nil
+	})
+}
+
+func PauseOracleDatabaseStep() Step {
+	return NewStep("pause oracle database", func(network *networks_impl.ChainlinkNetwork, state *State) error {
+		return network.PauseOracleDatabase()
+	})
+}
+
+func ResumeOracleDatabaseStep() Step {
+	return NewStep("resume oracle database", func(network *networks_impl.ChainlinkNetwork, state *State) error {
+		return network.ResumeOracleDatabase()
+	})
+}
+
+func RestartOracleDatabaseStep() Step {
+	return NewStep("restart oracle database", func(network *networks_impl.ChainlinkNetwork, state *State) error {
+		return network.RestartOracleDatabase()
+	})
+}
+
+/*
+	Kills the Oracle's database connections. Killing them again is harmless, so this is retried.
+ */
+func TerminateOracleDatabaseConnectionsStep() Step {
+	return NewStep("terminate oracle database connections", func(network *networks_impl.ChainlinkNetwork, state *State) error {
+		numTerminated, err := network.TerminateOracleDatabaseConnections()
+		if err != nil {
+			return stacktrace.Propagate(err, "An error occurred terminating the Oracle's database connections")
+		}
+		logrus.Infof("Terminated %v of the Oracle's database connections.", numTerminated)
+		return nil
+	}).WithRetries(terminateDatabaseConnectionsRetries, timeBetweenTerminateDatabaseConnectionsRetries)
+}
+
+/*
+	Delays the traffic between the Oracle and its database, which needs the Oracle to have been started with a database
+	proxy; zero removes the delay.
+ */
+func SetOracleDatabaseLatencyStep(latency time.Duration) Step {
+	return NewStep(fmt.Sprintf("set oracle database latency to %v", latency), func(network *networks_impl.ChainlinkNetwork, state *State) error {
+		return network.SetOracleDatabaseLatency(latency)
+	})
+}
+
+/*
+	Lets the network run undisturbed for the given duration, e.g. so transaction load can fill some blocks.
+ */
+func SleepStep(duration time.Duration) Step {
+	return NewStep(fmt.Sprintf("sleep for %v", duration), func(network *networks_impl.ChainlinkNetwork, state *State) error {
+		time.Sleep(duration)
+		return nil
+	})
+}
@@ -0,0 +1,173 @@
+package scenarios
+
+import (
+	"github.com/kurtosistech/chainlink-testing/testsuite/networks_impl"
+	"github.com/palantir/stacktrace"
+	"github.com/sirupsen/logrus"
+	"time"
+)
+
+/*
+	Values that steps hand on to the steps after them, so later steps don't need to know how earlier ones got them.
+ */
+type State struct {
+	LinkContractAddress string
+	OracleContractAddress string
+	ConsumerContractAddress string
+
+	// Job name (e.g. PriceFeedJobName) -> ID of the job on the primary Oracle
+	JobIds map[string]string
+}
+
+type StepFunc func(network *networks_impl.ChainlinkNetwork, state *State) error
+
+/*
+	A named piece of a scenario, usually wrapping one ChainlinkNetwork operation.
+ */
+type Step struct {
+	Name string
+	Run StepFunc
+
+	// Times a failed run is retried before the step fails; the step's run must be safe to repeat
+	NumRetries int
+	TimeBetweenRetries time.Duration
+
+	// Optional; run once the scenario is done if the step was started, whether or not the scenario succeeded
+	Cleanup StepFunc
+}
+
+func NewStep(name string, run StepFunc) Step {
+	return Step{
+		Name: name,
+		Run:  run,
+	}
+}
+
+/*
+	Returns a copy of the step that's retried the given number of times before it fails.
+ */
+func (step Step) WithRetries(numRetries int, timeBetweenRetries time.Duration) Step {
+	step.NumRetries = numRetries
+	step.TimeBetweenRetries = timeBetweenRetries
+	return step
+}
+
+/*
+	Returns a copy of the step under the given name, e.g. to tell apart steps a scenario runs more than once.
+ */
+func (step Step) WithName(name string) Step {
+	step.Name = name
+	return step
+}
+
+/*
+	Returns a copy of the step with the given cleanup.
+ */
+func (step Step) WithCleanup(cleanup StepFunc) Step {
+	step.Cleanup = cleanup
+	return step
+}
+
+/*
+	A sequence of steps run against a network, stopping at the first step that fails. Every step is logged, timed and
+	recorded in the report of the test using the network.
+ */
+type Scenario struct {
+	name string
+	network *networks_impl.ChainlinkNetwork
+	state *State
+	steps []Step
+}
+
+func NewScenario(name string, network *networks_impl.ChainlinkNetwork) *Scenario {
+	return &Scenario{
+		name:    name,
+		network: network,
+		state: &State{
+			JobIds: map[string]string{},
+		},
+		steps: []Step{},
+	}
+}
+
+func (scenario *Scenario) AddSteps(steps ...Step) *Scenario {
+	scenario.steps = append(scenario.steps, steps...)
+	return scenario
+}
+
+/*
+	The state the steps have built up so far, for assertions once the scenario has run.
+ */
+func (scenario *Scenario) GetState() *State {
+	return scenario.state
+}
+
+/*
+	Runs the steps in order, then the cleanups of every step that was started in reverse order. A cleanup failing
+	only fails the scenario if no step did.
+ */
+func (scenario *Scenario) Run() (resultErr error) {
+	startedSteps := []Step{}
+	defer func() {
+		cleanupErr := scenario.runCleanups(startedSteps)
+		if resultErr == nil && cleanupErr != nil {
+			resultErr = stacktrace.Propagate(cleanupErr, "Scenario '%v' passed, but cleaning up after it failed", scenario.name)
+		}
+	}()
+
+	for stepIdx, step := range scenario.steps {
+		logrus.Infof("Running step %v of %v of scenario '%v': %v", stepIdx + 1, len(scenario.steps), scenario.name, step.Name)
+		startedSteps = append(startedSteps, step)
+		startTime := time.Now()
+		step := step
+		err := scenario.network.RunTestStep(step.Name, func() error {
+			return scenario.runWithRetries(step)
+		})
+		if err != nil {
+			return stacktrace.Propagate(err, "Step '%v' of scenario '%v' failed", step.Name, scenario.name)
+		}
+		logrus.Infof("Step '%v' of scenario '%v' passed in %v.", step.Name, scenario.name, time.Since(startTime))
+	}
+	return nil
+}
+
+// ==========================================================================================
+//								Helper methods
+// ==========================================================================================
+
+func (scenario *Scenario) runWithRetries(step Step) error {
+	numAttempts := step.NumRetries + 1
+	var err error
+	for attempt := 1; attempt <= numAttempts; attempt++ {
+		err = step.Run(scenario.network, scenario.state)
+		if err == nil {
+			return nil
+		}
+		if attempt < numAttempts {
+			logrus.Warnf("Step '%v' failed on attempt %v of %v; retrying in %v: %v", step.Name, attempt, numAttempts, step.TimeBetweenRetries, err)
+			time.Sleep(step.TimeBetweenRetries)
+		}
+	}
+	return stacktrace.Propagate(err, "Step '%v' failed on all %v attempts", step.Name, numAttempts)
+}
+
+/*
+	Runs every cleanup even if earlier ones fail, returning the first error.
+ */
+func (scenario *Scenario) runCleanups(startedSteps []Step) error {
+	var firstErr error
+	for stepIdx := len(startedSteps) - 1; stepIdx >= 0; stepIdx-- {
+		step := startedSteps[stepIdx]
+		if step.Cleanup == nil {
+			continue
+		}
+		logrus.Infof("Cleaning up after step '%v' of scenario '%v'.", step.Name, scenario.name)
+		if err := step.Cleanup(scenario.network, scenario.state); err != nil {
+			logrus.Errorf("An error occurred cleaning up after step '%v' of scenario '%v': %v", step.Name, scenario.name, err)
+			if firstErr == nil {
+				firstErr = stacktrace.Propagate(err, "An error occurred cleaning up after step '%v'", step.Name)
+			}
+		}
+	}
+	return firstErr
+}
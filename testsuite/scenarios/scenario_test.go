@@ -0,0 +1,121 @@
+package scenarios
+
+import (
+	"errors"
+	"fmt"
+	"github.com/kurtosistech/chainlink-testing/testsuite/networks_impl"
+	"testing"
+)
+
+func TestScenarioRun(t *testing.T) {
+	testCases := []struct {
+		name string
+		// Number of times each step fails before it succeeds
+		numFailuresBeforeSuccess []int
+		// Retries each step gets
+		numRetries []int
+		// Whether each step's cleanup fails
+		isCleanupFailing []bool
+		expectedEvents []string
+		isErrorExpected bool
+	}{
+		{
+			name:                     "every step passes",
+			numFailuresBeforeSuccess: []int{0, 0, 0},
+			numRetries:               []int{0, 0, 0},
+			isCleanupFailing:         []bool{false, false, false},
+			expectedEvents:           []string{"run 0", "run 1", "run 2", "cleanup 2", "cleanup 1", "cleanup 0"},
+		},
+		{
+			name:                     "step passes on a retry",
+			numFailuresBeforeSuccess: []int{0, 2, 0},
+			numRetries:               []int{0, 2, 0},
+			isCleanupFailing:         []bool{false, false, false},
+			expectedEvents:           []string{"run 0", "run 1", "run 1", "run 1", "run 2", "cleanup 2", "cleanup 1", "cleanup 0"},
+		},
+		{
+			name:                     "step runs out of retries",
+			numFailuresBeforeSuccess: []int{0, 2, 0},
+			numRetries:               []int{0, 1, 0},
+			isCleanupFailing:         []bool{false, false, false},
+			// Later steps aren't started, so only the started steps are cleaned up
+			expectedEvents:  []string{"run 0", "run 1", "run 1", "cleanup 1", "cleanup 0"},
+			isErrorExpected: true,
+		},
+		{
+			name:                     "cleanup fails",
+			numFailuresBeforeSuccess: []int{0, 0},
+			numRetries:               []int{0, 0},
+			isCleanupFailing:         []bool{true, false},
+			expectedEvents:           []string{"run 0", "run 1", "cleanup 1", "cleanup 0"},
+			isErrorExpected:          true,
+		},
+		{
+			name:                     "cleanup fails after a step failed",
+			numFailuresBeforeSuccess: []int{0, 1},
+			numRetries:               []int{0, 0},
+			isCleanupFailing:         []bool{false, true},
+			// Every cleanup still runs
+			expectedEvents:  []string{"run 0", "run 1", "cleanup 1", "cleanup 0"},
+			isErrorExpected: true,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			network := networks_impl.NewChainlinkNetwork(nil, "", "", "", "", "", "", networks_impl.NetworkTopology{})
+			events := []string{}
+			scenario := NewScenario(testCase.name, network)
+			for stepIdx := range testCase.numFailuresBeforeSuccess {
+				stepIdx := stepIdx
+				numFailuresLeft := testCase.numFailuresBeforeSuccess[stepIdx]
+				step := NewStep(fmt.Sprintf("step %v", stepIdx), func(network *networks_impl.ChainlinkNetwork, state *State) error {
+					events = append(events, fmt.Sprintf("run %v", stepIdx))
+					if numFailuresLeft > 0 {
+						numFailuresLeft--
+						return errors.New("step failed")
+					}
+					return nil
+				}).WithRetries(testCase.numRetries[stepIdx], 0).WithCleanup(func(network *networks_impl.ChainlinkNetwork, state *State) error {
+					events = append(events, fmt.Sprintf("cleanup %v", stepIdx))
+					if testCase.isCleanupFailing[stepIdx] {
+						return errors.New("cleanup failed")
+					}
+					return nil
+				})
+				scenario.AddSteps(step)
+			}
+
+			err := scenario.Run()
+			if testCase.isErrorExpected && err == nil {
+				t.Errorf("Expected the scenario to fail, but it passed")
+			}
+			if !testCase.isErrorExpected && err != nil {
+				t.Errorf("Expected the scenario to pass, but got an error: %v", err)
+			}
+			if fmt.Sprint(events) != fmt.Sprint(testCase.expectedEvents) {
+				t.Errorf("Expected the scenario to go %v, but it went %v", testCase.expectedEvents, events)
+			}
+		})
+	}
+}
+
+func TestScenarioStateIsShared(t *testing.T) {
+	network := networks_impl.NewChainlinkNetwork(nil, "", "", "", "", "", "", networks_impl.NetworkTopology{})
+	scenario := NewScenario("shared state", network).AddSteps(
+		NewStep("record job", func(network *networks_impl.ChainlinkNetwork, state *State) error {
+			state.JobIds[PriceFeedJobName] = "job-1"
+			return nil
+		}),
+		NewStep("read job", func(network *networks_impl.ChainlinkNetwork, state *State) error {
+			if state.JobIds[PriceFeedJobName] != "job-1" {
+				return fmt.Errorf("expected job ID job-1, but got '%v'", state.JobIds[PriceFeedJobName])
+			}
+			return nil
+		}))
+	if err := scenario.Run(); err != nil {
+		t.Fatalf("Expected the scenario to pass, but got an error: %v", err)
+	}
+	if jobId := scenario.GetState().JobIds[PriceFeedJobName]; jobId != "job-1" {
+		t.Errorf("Expected the final state to have job ID job-1, but it has '%v'", jobId)
+	}
+}
@@ -1,12 +1,15 @@
 package chainlink_contract_deployer
 
 import (
+	"encoding/json"
 	"fmt"
-	"github.com/kurtosis-tech/kurtosis-libs/golang/lib/services"
 	"github.com/kurtosistech/chainlink-testing/testsuite/services_impl/geth"
+	"github.com/kurtosistech/chainlink-testing/testsuite/services_impl/service_context"
 	"github.com/palantir/stacktrace"
 	"github.com/sirupsen/logrus"
+	"regexp"
 	"strings"
+	"sync"
 )
 
 const (
@@ -20,15 +23,53 @@ const (
 	oracleContractSplitter      = "Deploying 'Oracle'\n"
 	myContractSplitter      = "Deploying 'MyContract'\n"
 	setOracleFulfillmentPermissionsPath = "ethers_js_scripts/setOracleFulfillmentPermissions.js"
+	deployContractPath = "truffle_scripts/deploy-contract.js"
+	simpleConsumerContractName = "SimpleConsumer"
+	eventEmitterContractName = "EventEmitter"
+	consumerContractName = "MyContract"
+	requestDataPath = "truffle_scripts/request-data.js"
+	requestTxHashSplitter = "Request transaction: "
+	// Truffle reports requests the EVM rejects, e.g. from a consumer without enough $LINK, with errors mentioning this
+	revertErrorFragment = "revert"
+
+	commandLogEntrySeparator = "\n\n"
 )
 
+// The request-data script passes the request transaction hash to truffle's callback, which prints it
+var txHashRegex = regexp.MustCompile("0x[0-9a-fA-F]{64}")
+
+// The consumer contract is the last one the migrations deploy, so there's no next contract to split its info off at
+var lastContractAddressRegex = regexp.MustCompile(regexp.QuoteMeta(contractAddressSplitter) + `\s*(0x[0-9a-fA-F]{40})`)
+
 type ChainlinkContractDeployerService struct {
-	serviceCtx *services.ServiceContext
+	serviceCtx service_context.ServiceContext
 	isContractDeployed bool
+	// Output of every command run on the container, kept so truffle output can be inspected after a failure
+	commandLog *commandLog
 }
 
-func NewChainlinkContractDeployerService(serviceCtx *services.ServiceContext) *ChainlinkContractDeployerService {
-	return &ChainlinkContractDeployerService{serviceCtx: serviceCtx}
+type commandLog struct {
+	mutex *sync.Mutex
+	entries []string
+}
+
+func NewChainlinkContractDeployerService(serviceCtx service_context.ServiceContext) *ChainlinkContractDeployerService {
+	return &ChainlinkContractDeployerService{
+		serviceCtx: serviceCtx,
+		commandLog: &commandLog{
+			mutex: &sync.Mutex{},
+			entries: []string{},
+		},
+	}
+}
+
+/*
+	Gets the commands run on the deployer container so far, each followed by its exit code and output.
+ */
+func (deployer ChainlinkContractDeployerService) GetCommandLog() string {
+	deployer.commandLog.mutex.Lock()
+	defer deployer.commandLog.mutex.Unlock()
+	return strings.Join(deployer.commandLog.entries, commandLogEntrySeparator)
 }
 
 func (deployer *ChainlinkContractDeployerService) overwriteMigrationIPAddress(nodeIpAddress string) error {
@@ -40,7 +81,7 @@ func (deployer *ChainlinkContractDeployerService) overwriteMigrationIPAddress(no
 			nodeIpAddress,
 			migrationConfigurationFileName),
 	}
-	errorCode, _, err := deployer.serviceCtx.ExecCommand(overwriteMigrationIPAddressCommand)
+	errorCode, _, err := deployer.execCommand(overwriteMigrationIPAddressCommand)
 	if err != nil {
 		return stacktrace.Propagate(err, "Failed to execute command on contract deployer service.")
 	} else if errorCode != 0 {
@@ -58,7 +99,7 @@ func (deployer *ChainlinkContractDeployerService) overwriteMigrationPort(port st
 			geth.FirstFundedAddress,
 			migrationConfigurationFileName,),
 	}
-	errorCode, _, err := deployer.serviceCtx.ExecCommand(overwriteMigrationPortCommand)
+	errorCode, _, err := deployer.execCommand(overwriteMigrationPortCommand)
 	if err != nil {
 		return stacktrace.Propagate(err, "Failed to execute command on contract deployer service.")
 	} else if errorCode != 0 {
@@ -67,14 +108,18 @@ func (deployer *ChainlinkContractDeployerService) overwriteMigrationPort(port st
 	return nil
 }
 
-func (deployer *ChainlinkContractDeployerService) DeployContract(gethServiceIpAddress string, gethServicePort string) (linkAddress string, oracleAddress string, err error) {
+/*
+	Runs the truffle box's migrations, which deploy the $LINK token, Oracle and consumer contracts, returning their
+	addresses.
+ */
+func (deployer *ChainlinkContractDeployerService) DeployContract(gethServiceIpAddress string, gethServicePort string) (linkAddress string, oracleAddress string, consumerAddress string, err error) {
 	err = deployer.overwriteMigrationIPAddress(gethServiceIpAddress)
 	if err != nil {
-		return "", "", stacktrace.Propagate(err, "Failed to deploy $LINK contract.")
+		return "", "", "", stacktrace.Propagate(err, "Failed to deploy $LINK contract.")
 	}
 	err = deployer.overwriteMigrationPort(gethServicePort)
 	if err != nil {
-		return "", "", stacktrace.Propagate(err, "Failed to deploy $LINK contract.")
+		return "", "", "", stacktrace.Propagate(err, "Failed to deploy $LINK contract.")
 	}
 
 	migrateCommand := []string{
@@ -82,33 +127,48 @@ func (deployer *ChainlinkContractDeployerService) DeployContract(gethServiceIpAd
 		"-c",
 		fmt.Sprintf("yarn migrate:dev",),
 	}
-	errorCode, logOutput, err := deployer.serviceCtx.ExecCommand(migrateCommand)
+	errorCode, logOutput, err := deployer.execCommand(migrateCommand)
 	if err != nil {
-		return "", "", stacktrace.Propagate(err, "Failed to execute yarn migration command on contract deployer service.")
+		return "", "", "", stacktrace.Propagate(err, "Failed to execute yarn migration command on contract deployer service.")
 	} else if errorCode != 0 {
-		return "", "", stacktrace.NewError("Got a non-zero exit code executing yarn migration for contract deployment: %v", errorCode)
+		return "", "", "", stacktrace.NewError("Got a non-zero exit code executing yarn migration for contract deployment: %v", errorCode)
 	}
 	logOutputStr := string(*logOutput)
 	logrus.Debugf("Log output from contract deploy: %+v", logOutputStr)
 	linkAddress, err = parseContractAddressFromTruffleMigrate(logOutputStr, linkTokenContractSplitter, oracleContractSplitter)
 	if err != nil {
-		return "", "", stacktrace.Propagate(err, "Failed to parse contract linkAddress.")
+		return "", "", "", stacktrace.Propagate(err, "Failed to parse contract linkAddress.")
 	}
 	oracleAddress, err = parseContractAddressFromTruffleMigrate(logOutputStr, oracleContractSplitter, myContractSplitter)
+	if err != nil {
+		return "", "", "", stacktrace.Propagate(err, "Failed to parse the Oracle contract address.")
+	}
+	consumerAddress, err = parseLastContractAddressFromTruffleMigrate(logOutputStr, myContractSplitter)
+	if err != nil {
+		return "", "", "", stacktrace.Propagate(err, "Failed to parse the consumer contract address.")
+	}
 	deployer.isContractDeployed = true
-	return linkAddress, oracleAddress,nil
+	return linkAddress, oracleAddress, consumerAddress, nil
 }
 
 func (deployer ChainlinkContractDeployerService) FundLinkWalletContract() error {
+	return deployer.FundLinkWalletContractWithAmount(DefaultLinkPaymentJuels)
+}
+
+/*
+	Transfers the given amount of $LINK (in juels, i.e. 10^-18 $LINK) to the consumer contract, so it can pay for requests.
+ */
+func (deployer ChainlinkContractDeployerService) FundLinkWalletContractWithAmount(linkAmountJuels string) error {
 	fundLinkWalletCommand := []string{
 		"/bin/sh",
 		"-c",
-		fmt.Sprintf("npx truffle exec scripts/fund-contract.js --network %v",
-			devNetworkId,),
+		fmt.Sprintf("export TRUFFLE_CL_BOX_PAYMENT=%v && " +
+			"npx truffle exec scripts/fund-contract.js --network %v",
+			linkAmountJuels, devNetworkId,),
 	}
 	// We don't check the error code here because the fund-contract script from Chainlink
 	// erroneously reports failures, see: https://github.com/smartcontractkit/box/issues/63
-	_, logOutput, err := deployer.serviceCtx.ExecCommand(fundLinkWalletCommand)
+	_, logOutput, err := deployer.execCommand(fundLinkWalletCommand)
 	if err != nil {
 		return stacktrace.Propagate(err, "Failed to execute $LINK funding command on contract deployer service.")
 	}
@@ -129,7 +189,7 @@ func (deployer ChainlinkContractDeployerService) SetFulfillmentPermissions(gethS
 			"node %v", gethServiceIpAddress, gethServicePort, geth.PrivateKeyPassword,
 				oracleContractAddress, oracleEthereumAccount, setOracleFulfillmentPermissionsPath),
 	}
-	statusCode, logOutput, err := deployer.serviceCtx.ExecCommand(setPermissionCommand)
+	statusCode, logOutput, err := deployer.execCommand(setPermissionCommand)
 	if err != nil {
 		return stacktrace.Propagate(err, "Failed to execute set permission script.")
 	}
@@ -139,7 +199,88 @@ func (deployer ChainlinkContractDeployerService) SetFulfillmentPermissions(gethS
 	return nil
 }
 
-func (deployer ChainlinkContractDeployerService) RunRequestDataScript(oracleContractAddress string, jobId string, priceFeedUrl string) error {
+/*
+	Deploys a SimpleConsumer contract, which jobs without an on-chain requester write their results to, returning its
+	address. Must be called after DeployContract, which points truffle at the network.
+ */
+func (deployer ChainlinkContractDeployerService) DeploySimpleConsumerContract() (string, error) {
+	address, err := deployer.deployContractByName(simpleConsumerContractName)
+	if err != nil {
+		return "", stacktrace.Propagate(err, "Failed to deploy the SimpleConsumer contract.")
+	}
+	return address, nil
+}
+
+/*
+	Deploys an EventEmitter contract, which emits events with the data it's called with, returning its address. Must be
+	called after DeployContract, which points truffle at the network.
+ */
+func (deployer ChainlinkContractDeployerService) DeployEventEmitterContract() (string, error) {
+	address, err := deployer.deployContractByName(eventEmitterContractName)
+	if err != nil {
+		return "", stacktrace.Propagate(err, "Failed to deploy the EventEmitter contract.")
+	}
+	return address, nil
+}
+
+/*
+	Deploys another consumer contract like the one the migrations deploy, paying for requests with the $LINK token at
+	the given address, returning its address. It starts without any $LINK.
+ */
+func (deployer ChainlinkContractDeployerService) DeployConsumerContract(linkContractAddress string) (string, error) {
+	address, err := deployer.deployContractByName(consumerContractName, linkContractAddress)
+	if err != nil {
+		return "", stacktrace.Propagate(err, "Failed to deploy a consumer contract.")
+	}
+	return address, nil
+}
+
+/*
+	Requests data from the Oracle contract through the consumer contract at the given address (or the one the
+	migrations deployed, if empty), paying the given $LINK in juels. Returns the hash of the request transaction, or
+	isReverted if the consumer's request was rejected on-chain, e.g. because it can't afford the payment, in which case
+	there's no transaction.
+ */
+func (deployer ChainlinkContractDeployerService) RequestDataWithPayment(consumerContractAddress string, oracleContractAddress string,
+		jobId string, priceFeedUrl string, paymentJuels string) (txHash string, isReverted bool, err error) {
+	requestDataCommand := []string{
+		"/bin/sh",
+		"-c",
+		fmt.Sprintf("export CONSUMER_ADDRESS=%v && " +
+			"export ORACLE_ADDRESS=%v && " +
+			"export JOB_ID=%v && " +
+			"export PAYMENT=%v && " +
+			"export URL=%v && " +
+			"npx truffle exec %v --network %v",
+			consumerContractAddress, oracleContractAddress, jobId, paymentJuels, priceFeedUrl, requestDataPath, devNetworkId),
+	}
+	exitCode, logOutput, err := deployer.execCommand(requestDataCommand)
+	if err != nil {
+		return "", false, stacktrace.Propagate(err, "Failed to execute request data command on contract deployer service.")
+	}
+	logOutputStr := string(*logOutput)
+	logrus.Debugf("Log output from requesting data: %+v", logOutputStr)
+	if exitCode != 0 {
+		if strings.Contains(strings.ToLower(logOutputStr), revertErrorFragment) {
+			return "", true, nil
+		}
+		return "", false, stacktrace.NewError("Got a non-zero exit code requesting data: %v\n%v", exitCode, logOutputStr)
+	}
+	splitOnTxHash := strings.Split(logOutputStr, requestTxHashSplitter)
+	if len(splitOnTxHash) != 2 {
+		return "", false, stacktrace.NewError("Couldn't find the request transaction hash in the request data script output: %v", logOutputStr)
+	}
+	txHash = txHashRegex.FindString(splitOnTxHash[1])
+	if txHash == "" {
+		return "", false, stacktrace.NewError("Couldn't parse the request transaction hash from the request data script output: %v", logOutputStr)
+	}
+	return txHash, false, nil
+}
+
+/*
+	Requests data from the Oracle contract through the consumer contract, returning the hash of the request transaction.
+ */
+func (deployer ChainlinkContractDeployerService) RunRequestDataScript(oracleContractAddress string, jobId string, priceFeedUrl string) (string, error) {
 	requestDataCommand := []string{
 		"/bin/sh",
 		"-c",
@@ -151,13 +292,17 @@ func (deployer ChainlinkContractDeployerService) RunRequestDataScript(oracleCont
 	}
 	// We don't check the error code here because the fund-contract script from Chainlink
 	// erroneously reports failures, see: https://github.com/smartcontractkit/box/issues/63
-	_, logOutput, err := deployer.serviceCtx.ExecCommand(requestDataCommand)
+	_, logOutput, err := deployer.execCommand(requestDataCommand)
 	if err != nil {
-		return stacktrace.Propagate(err, "Failed to execute request data command on contract deployer service.")
+		return "", stacktrace.Propagate(err, "Failed to execute request data command on contract deployer service.")
 	}
 	logOutputStr := string(*logOutput)
 	logrus.Debugf("Log output from requesting data: %+v", logOutputStr)
-	return nil
+	txHashes := txHashRegex.FindAllString(logOutputStr, -1)
+	if len(txHashes) == 0 {
+		return "", stacktrace.NewError("Couldn't find the request transaction hash in the request data script output: %v", logOutputStr)
+	}
+	return txHashes[len(txHashes) - 1], nil
 }
 
 // ===========================================================================================
@@ -172,6 +317,56 @@ func (deployer ChainlinkContractDeployerService) IsAvailable() bool {
 //                              Helper functions
 // ===========================================================================================
 
+func (deployer ChainlinkContractDeployerService) execCommand(command []string) (int32, *[]byte, error) {
+	exitCode, logOutput, err := deployer.serviceCtx.ExecCommand(command)
+	entry := fmt.Sprintf("$ %v\n", strings.Join(command, " "))
+	if err != nil {
+		entry += fmt.Sprintf("Failed to execute: %v", err)
+	} else {
+		entry += fmt.Sprintf("Exit code: %v\n%v", exitCode, string(*logOutput))
+	}
+	deployer.commandLog.mutex.Lock()
+	deployer.commandLog.entries = append(deployer.commandLog.entries, entry)
+	deployer.commandLog.mutex.Unlock()
+	return exitCode, logOutput, err
+}
+
+/*
+	Deploys one of the contracts compiled into the image that takes no constructor arguments, returning its address.
+ */
+func (deployer ChainlinkContractDeployerService) deployContractByName(contractName string, constructorArgs ...string) (string, error) {
+	if !deployer.isContractDeployed {
+		return "", stacktrace.NewError("Tried to deploy contract %v before truffle was pointed at the network by deploying the $LINK contract.", contractName)
+	}
+	constructorArgsJson, err := json.Marshal(constructorArgs)
+	if err != nil {
+		return "", stacktrace.Propagate(err, "Failed to serialize the constructor arguments of contract %v.", contractName)
+	}
+	deployCommand := []string{
+		"/bin/sh",
+		"-c",
+		fmt.Sprintf("export CONTRACT_NAME=%v && " +
+			"export CONTRACT_ARGS='%v' && " +
+			"npx truffle exec %v --network %v", contractName, string(constructorArgsJson), deployContractPath, devNetworkId),
+	}
+	exitCode, logOutput, err := deployer.execCommand(deployCommand)
+	if err != nil {
+		return "", stacktrace.Propagate(err, "Failed to execute %v deploy command on contract deployer service.", contractName)
+	}
+	logOutputStr := string(*logOutput)
+	logrus.Debugf("Log output from deploying %v: %+v", contractName, logOutputStr)
+	if exitCode != 0 {
+		return "", stacktrace.NewError("Got a non-zero exit code deploying the %v contract: %v", contractName, exitCode)
+	}
+	// The deploy script prints "<contract name> address: <address>"
+	deployedAddressRegex := regexp.MustCompile(regexp.QuoteMeta(contractName) + " address: (0x[0-9a-fA-F]{40})")
+	matches := deployedAddressRegex.FindStringSubmatch(logOutputStr)
+	if matches == nil {
+		return "", stacktrace.NewError("Couldn't find the %v address in the deploy script output: %v", contractName, logOutputStr)
+	}
+	return matches[1], nil
+}
+
 func parseContractAddressFromTruffleMigrate(logOutputStr string, contractSplitter string, nextContractSplitter string) (string, error) {
 	splitOnContract := strings.Split(logOutputStr, contractSplitter)
 	splitCount := len(splitOnContract)
@@ -204,4 +399,20 @@ func parseContractAddressFromTruffleMigrate(logOutputStr string, contractSplitte
 	}
 	address := splitOnAddressContent[0]
 	return strings.TrimSpace(address), nil
+}
+
+func parseLastContractAddressFromTruffleMigrate(logOutputStr string, contractSplitter string) (string, error) {
+	splitOnContract := strings.Split(logOutputStr, contractSplitter)
+	splitCount := len(splitOnContract)
+	if splitCount != 2 {
+		return "", stacktrace.NewError("Expected truffle migrate command output to split into two on %+v, instead split into %v",
+			contractSplitter,
+			splitCount)
+	}
+	matches := lastContractAddressRegex.FindStringSubmatch(splitOnContract[1])
+	if matches == nil {
+		return "", stacktrace.NewError("Couldn't find the contract address after %+v in the truffle migrate command output",
+			contractSplitter)
+	}
+	return matches[1], nil
 }
\ No newline at end of file
@@ -9,7 +9,8 @@ import (
 
 const (
 	sleepSeconds = 7200
-	defaultLinkFunding = "1000000000000000000000"
+	// $LINK (in juels) that the consumer contract pays per request, and that each funding transfers to it
+	DefaultLinkPaymentJuels = "1000000000000000000000"
 )
 
 type ChainlinkContractDeployerInitializer struct {
@@ -52,7 +53,7 @@ func (initializer ChainlinkContractDeployerInitializer) GetTestVolumeMountpoint(
 
 func (initializer ChainlinkContractDeployerInitializer) GetEnvironmentVariableOverrides() (map[string]string, error) {
 	return map[string]string{
-		"TRUFFLE_CL_BOX_PAYMENT": defaultLinkFunding,
+		"TRUFFLE_CL_BOX_PAYMENT": DefaultLinkPaymentJuels,
 	}, nil
 }
 
@@ -0,0 +1,226 @@
+package chainlink_contract_deployer
+
+import (
+	"github.com/kurtosistech/chainlink-testing/testsuite/services_impl/testutil"
+	"strings"
+	"testing"
+)
+
+const (
+	testLinkTokenAddress = "0x6E7aE6e0fC1E2b4e5F5b1b6a3F6d2A1f0C9B8a71"
+	testOracleAddress = "0x2cF1b5D2c2E8c0F3a3b5e6D4b1C0A9f8E7d6C5b4"
+	testConsumerAddress = "0x9aB8c7D6e5F4a3B2c1D0e9F8a7B6c5D4e3F2a1B0"
+
+	testDeployerServiceId = "test-contract-deployer"
+	testDeployerIpAddress = "172.23.0.5"
+)
+
+func TestParseContractAddressFromTruffleMigrate(t *testing.T) {
+	testCases := []struct {
+		name string
+		logOutput string
+		contractSplitter string
+		nextContractSplitter string
+		expectedAddress string
+		isErrorExpected bool
+	}{
+		{
+			name:                 "first contract",
+			logOutput:            getTruffleMigrateOutput(testLinkTokenAddress, testOracleAddress, testConsumerAddress),
+			contractSplitter:     linkTokenContractSplitter,
+			nextContractSplitter: oracleContractSplitter,
+			expectedAddress:      testLinkTokenAddress,
+		},
+		{
+			name:                 "middle contract",
+			logOutput:            getTruffleMigrateOutput(testLinkTokenAddress, testOracleAddress, testConsumerAddress),
+			contractSplitter:     oracleContractSplitter,
+			nextContractSplitter: myContractSplitter,
+			expectedAddress:      testOracleAddress,
+		},
+		{
+			name:                 "contract not deployed",
+			logOutput:            getTruffleDeploymentOutput("Oracle", testOracleAddress),
+			contractSplitter:     linkTokenContractSplitter,
+			nextContractSplitter: oracleContractSplitter,
+			isErrorExpected:      true,
+		},
+		{
+			name: "contract deployed twice",
+			logOutput: getTruffleDeploymentOutput("LinkToken", testLinkTokenAddress) +
+				getTruffleDeploymentOutput("LinkToken", testLinkTokenAddress) +
+				getTruffleDeploymentOutput("Oracle", testOracleAddress),
+			contractSplitter:     linkTokenContractSplitter,
+			nextContractSplitter: oracleContractSplitter,
+			isErrorExpected:      true,
+		},
+		{
+			name:                 "next contract not deployed",
+			logOutput:            getTruffleDeploymentOutput("LinkToken", testLinkTokenAddress),
+			contractSplitter:     linkTokenContractSplitter,
+			nextContractSplitter: oracleContractSplitter,
+			isErrorExpected:      true,
+		},
+		{
+			name: "deployment without an address",
+			logOutput: "   Deploying 'LinkToken'\n   ---------------------\n   Error: insufficient funds for gas * price + value\n\n" +
+				getTruffleDeploymentOutput("Oracle", testOracleAddress),
+			contractSplitter:     linkTokenContractSplitter,
+			nextContractSplitter: oracleContractSplitter,
+			isErrorExpected:      true,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			address, err := parseContractAddressFromTruffleMigrate(testCase.logOutput, testCase.contractSplitter, testCase.nextContractSplitter)
+			if testCase.isErrorExpected {
+				if err == nil {
+					t.Fatalf("Expected an error, but got address '%v'", address)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected address %v, but got an error: %v", testCase.expectedAddress, err)
+			}
+			if address != testCase.expectedAddress {
+				t.Errorf("Expected address %v, but got %v", testCase.expectedAddress, address)
+			}
+		})
+	}
+}
+
+func TestParseLastContractAddressFromTruffleMigrate(t *testing.T) {
+	testCases := []struct {
+		name string
+		logOutput string
+		expectedAddress string
+		isErrorExpected bool
+	}{
+		{
+			name:            "last contract",
+			logOutput:       getTruffleMigrateOutput(testLinkTokenAddress, testOracleAddress, testConsumerAddress),
+			expectedAddress: testConsumerAddress,
+		},
+		{
+			name:            "contract not deployed",
+			logOutput:       getTruffleDeploymentOutput("LinkToken", testLinkTokenAddress),
+			isErrorExpected: true,
+		},
+		{
+			name:            "deployment without an address",
+			logOutput:       "   Deploying 'MyContract'\n   ----------------------\n   Error: out of gas\n",
+			isErrorExpected: true,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			address, err := parseLastContractAddressFromTruffleMigrate(testCase.logOutput, myContractSplitter)
+			if testCase.isErrorExpected {
+				if err == nil {
+					t.Fatalf("Expected an error, but got address '%v'", address)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected address %v, but got an error: %v", testCase.expectedAddress, err)
+			}
+			if address != testCase.expectedAddress {
+				t.Errorf("Expected address %v, but got %v", testCase.expectedAddress, address)
+			}
+		})
+	}
+}
+
+func TestDeployContract(t *testing.T) {
+	testCases := []struct {
+		name string
+		migrateOutput string
+		migrateExitCode int32
+		isErrorExpected bool
+	}{
+		{
+			name:          "all contracts deployed",
+			migrateOutput: getTruffleMigrateOutput(testLinkTokenAddress, testOracleAddress, testConsumerAddress),
+		},
+		{
+			name: "oracle deployment failed",
+			migrateOutput: getTruffleDeploymentOutput("LinkToken", testLinkTokenAddress) +
+				"   Deploying 'Oracle'\n   ------------------\n   Error: out of gas\n\n" +
+				getTruffleDeploymentOutput("MyContract", testConsumerAddress),
+			isErrorExpected: true,
+		},
+		{
+			name:            "migrations failed",
+			migrateOutput:   "error Command failed with exit code 1.",
+			migrateExitCode: 1,
+			isErrorExpected: true,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			serviceCtx := testutil.NewFakeServiceContext(testDeployerServiceId, testDeployerIpAddress)
+			serviceCtx.SetCommandHandler(func(command []string) (int32, string, error) {
+				if strings.Contains(command[len(command) - 1], "yarn migrate:dev") {
+					return testCase.migrateExitCode, testCase.migrateOutput, nil
+				}
+				return 0, "", nil
+			})
+			deployer := NewChainlinkContractDeployerService(serviceCtx)
+
+			linkAddress, oracleAddress, consumerAddress, err := deployer.DeployContract("172.23.0.2", "8545")
+			if testCase.isErrorExpected {
+				if err == nil {
+					t.Fatalf("Expected an error, but got addresses %v, %v and %v", linkAddress, oracleAddress, consumerAddress)
+				}
+				if deployer.isContractDeployed {
+					t.Errorf("Expected the deployer not to be marked as having deployed the contracts")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected the contracts to be deployed, but got an error: %v", err)
+			}
+			if linkAddress != testLinkTokenAddress || oracleAddress != testOracleAddress || consumerAddress != testConsumerAddress {
+				t.Errorf("Expected addresses %v, %v and %v, but got %v, %v and %v",
+					testLinkTokenAddress, testOracleAddress, testConsumerAddress, linkAddress, oracleAddress, consumerAddress)
+			}
+			// Truffle has to be pointed at the geth node before the migrations run
+			executedCommands := serviceCtx.GetExecutedCommands()
+			if len(executedCommands) != 3 {
+				t.Fatalf("Expected 3 commands to be run on the deployer, but got %v: %v", len(executedCommands), executedCommands)
+			}
+			if !strings.Contains(executedCommands[0][2], "172.23.0.2") || !strings.Contains(executedCommands[1][2], "port: 8545") {
+				t.Errorf("Expected the truffle config to be pointed at the geth node first, but the commands were %v", executedCommands)
+			}
+		})
+	}
+}
+
+// ==========================================================================================
+//								Helper methods
+// ==========================================================================================
+
+/*
+	Output of the truffle box's migrations, which deploy the $LINK token, Oracle and consumer contracts in that order.
+ */
+func getTruffleMigrateOutput(linkTokenAddress string, oracleAddress string, consumerAddress string) string {
+	return "Compiling your contracts...\n===========================\n> Everything is up to date, there is nothing to compile.\n\n" +
+		"Starting migrations...\n======================\n> Network name:    'cldev'\n> Network id:      1234\n\n" +
+		"2_deploy_contracts.js\n=====================\n\n" +
+		getTruffleDeploymentOutput("LinkToken", linkTokenAddress) +
+		getTruffleDeploymentOutput("Oracle", oracleAddress) +
+		getTruffleDeploymentOutput("MyContract", consumerAddress) +
+		"   > Saving migration to chain.\n   > Saving artifacts\n   -------------------------------------\n" +
+		"   > Total cost:          0.0789 ETH\n\nDone in 12.34s.\n"
+}
+
+func getTruffleDeploymentOutput(contractName string, address string) string {
+	return "   Deploying '" + contractName + "'\n" +
+		"   " + strings.Repeat("-", len(contractName) + 12) + "\n" +
+		"   > transaction hash:    0x4b1c8e5a0d2f7b3e9c6a1d8f5e2b7c4a9d6f3e0b8c5a2d7f4e1b9c6a3d0f8e5b\n" +
+		"   > Blocks: 0            Seconds: 4\n" +
+		"   > contract address:    " + address + "\n" +
+		"   > block number:        3\n" +
+		"   > account:             0x8eA1441a74ffbE9504a8Cb3F7e4b7118d8CCFc56\n" +
+		"   > gas used:            1234567\n\n"
+}
@@ -0,0 +1,12 @@
+pragma solidity >=0.4.24 <0.8.0;
+
+/**
+ * Emits events with whatever data it's given, so tests can drive EthLog initiated jobs.
+ */
+contract EventEmitter {
+  event Emitted(uint256 indexed id, bytes32 data);
+
+  function emitEvent(uint256 _id, bytes32 _data) public {
+    emit Emitted(_id, _data);
+  }
+}
@@ -0,0 +1,20 @@
+pragma solidity >=0.4.24 <0.8.0;
+
+/**
+ * Holds the last value written to it, so tests can check what jobs without an on-chain requester (cron and web
+ * initiated ones) wrote from their EthTx task.
+ */
+contract SimpleConsumer {
+  uint256 public value;
+  uint256 public numWrites;
+  address public lastWriter;
+
+  event ValueWritten(uint256 value, address writer);
+
+  function setValue(uint256 _value) public {
+    value = _value;
+    numWrites += 1;
+    lastWriter = msg.sender;
+    emit ValueWritten(_value, msg.sender);
+  }
+}
@@ -0,0 +1,17 @@
+const CONTRACT_NAME = process.env.CONTRACT_NAME
+// JSON array of constructor arguments, if the contract takes any
+const CONTRACT_ARGS = JSON.parse(process.env.CONTRACT_ARGS || '[]')
+
+/*
+  Deploys the contract named by CONTRACT_NAME and prints its address, which the testsuite parses from the output.
+*/
+module.exports = async callback => {
+  try {
+    const Contract = artifacts.require(CONTRACT_NAME)
+    const contract = await Contract.new(...CONTRACT_ARGS)
+    console.log(CONTRACT_NAME + ' address: ' + contract.address)
+    callback()
+  } catch (err) {
+    callback(err)
+  }
+}
@@ -0,0 +1,26 @@
+const MyContract = artifacts.require('MyContract')
+
+// Consumer contract to request through; the one deployed by the box's migrations if unset
+const CONSUMER_ADDRESS = process.env.CONSUMER_ADDRESS
+const ORACLE_ADDRESS = process.env.ORACLE_ADDRESS
+const JOB_ID = process.env.JOB_ID
+// $LINK, in juels, the consumer pays the Oracle for the request
+const PAYMENT = process.env.PAYMENT
+const URL = process.env.URL
+const JSON_PATH = 'USD'
+const TIMES = '100'
+
+/*
+  Like the box's request-data script, but with the consumer and payment configurable, and exiting non-zero only when
+  the request fails. Prints the request transaction hash, which the testsuite parses from the output.
+*/
+module.exports = async callback => {
+  try {
+    const consumer = CONSUMER_ADDRESS ? await MyContract.at(CONSUMER_ADDRESS) : await MyContract.deployed()
+    const tx = await consumer.createRequestTo(ORACLE_ADDRESS, web3.utils.toHex(JOB_ID), PAYMENT, URL, JSON_PATH, TIMES)
+    console.log('Request transaction: ' + tx.tx)
+    callback()
+  } catch (err) {
+    callback(err)
+  }
+}
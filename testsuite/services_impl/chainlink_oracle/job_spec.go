@@ -0,0 +1,162 @@
+package chainlink_oracle
+
+import (
+	"fmt"
+)
+
+const (
+	// Initiators, which decide when the node starts a run of a job
+	RunLogInitiatorType = "RunLog"
+	CronInitiatorType = "cron"
+	WebInitiatorType = "web"
+	EthLogInitiatorType = "ethlog"
+
+	httpGetTaskType = "HttpGetWithUnrestrictedNetworkAccess"
+	jsonParseTaskType = "JsonParse"
+	multiplyTaskType = "Multiply"
+	ethInt256TaskType = "EthInt256"
+	ethUint256TaskType = "EthUint256"
+	ethTxTaskType = "EthTx"
+	noOpTaskType = "NoOp"
+
+	// The price feed server answers with the price under this key
+	priceFeedJsonPath = "USD"
+	// The price is multiplied by this before being written on-chain as an integer, keeping two decimal places
+	priceFeedMultiplier = 100
+	// Signature of the SimpleConsumer contract function that jobs without a requester write their result to
+	SimpleConsumerSetValueSignature = "setValue(uint256)"
+)
+
+/*
+	A JSON job spec, as posted to the node's specs endpoint.
+ */
+type JobSpec struct {
+	Initiators []InitiatorSpec `json:"initiators"`
+	Tasks []TaskSpec `json:"tasks"`
+}
+
+type InitiatorSpec struct {
+	Type string `json:"type"`
+	Params map[string]interface{} `json:"params,omitempty"`
+}
+
+type TaskSpec struct {
+	Type string `json:"type"`
+	Params map[string]interface{} `json:"params,omitempty"`
+}
+
+/*
+	The price feed job started by requests to the Oracle contract at the given address; the request supplies the URL,
+	JSON path and multiplier, and the result is sent back to the Oracle contract to fulfill the request.
+ */
+func NewRunLogJobSpec(oracleContractAddress string) JobSpec {
+	return JobSpec{
+		Initiators: []InitiatorSpec{
+			{
+				Type: RunLogInitiatorType,
+				Params: map[string]interface{}{"address": oracleContractAddress},
+			},
+		},
+		Tasks: []TaskSpec{
+			{Type: httpGetTaskType},
+			{Type: jsonParseTaskType},
+			{Type: multiplyTaskType},
+			{Type: ethInt256TaskType},
+			{Type: ethTxTaskType},
+		},
+	}
+}
+
+/*
+	A job that fetches the price from the given URL on the given cron schedule (with seconds, e.g.
+	"CRON_TZ=UTC 0/15 * * * * *") and writes it to the SimpleConsumer contract at the given address.
+ */
+func NewCronJobSpec(schedule string, priceFeedUrl string, consumerContractAddress string) JobSpec {
+	return JobSpec{
+		Initiators: []InitiatorSpec{
+			{
+				Type: CronInitiatorType,
+				Params: map[string]interface{}{"schedule": schedule},
+			},
+		},
+		Tasks: getWritePriceTasks(priceFeedUrl, consumerContractAddress),
+	}
+}
+
+/*
+	A job that's only run when triggered over the operator API, fetching the price from the given URL and writing it to
+	the SimpleConsumer contract at the given address.
+ */
+func NewWebJobSpec(priceFeedUrl string, consumerContractAddress string) JobSpec {
+	return JobSpec{
+		Initiators: []InitiatorSpec{
+			{Type: WebInitiatorType},
+		},
+		Tasks: getWritePriceTasks(priceFeedUrl, consumerContractAddress),
+	}
+}
+
+/*
+	A job that runs once for every log the contract at the given address emits, doing nothing but record the log.
+ */
+func NewEthLogJobSpec(contractAddress string) JobSpec {
+	return JobSpec{
+		Initiators: []InitiatorSpec{
+			{
+				Type: EthLogInitiatorType,
+				Params: map[string]interface{}{"address": contractAddress},
+			},
+		},
+		Tasks: []TaskSpec{
+			{Type: noOpTaskType},
+		},
+	}
+}
+
+/*
+	Whether the job has an initiator of the given type, e.g. WebInitiatorType for jobs that can be run over the API.
+ */
+func (jobSpec JobSpec) HasInitiator(initiatorType string) bool {
+	for _, initiator := range jobSpec.Initiators {
+		if initiator.Type == initiatorType {
+			return true
+		}
+	}
+	return false
+}
+
+// ===========================================================================================
+//                                  Helper methods
+// ===========================================================================================
+
+func getWritePriceTasks(priceFeedUrl string, consumerContractAddress string) []TaskSpec {
+	return []TaskSpec{
+		{
+			Type: httpGetTaskType,
+			Params: map[string]interface{}{"get": priceFeedUrl},
+		},
+		{
+			Type: jsonParseTaskType,
+			Params: map[string]interface{}{"path": []string{priceFeedJsonPath}},
+		},
+		{
+			Type: multiplyTaskType,
+			Params: map[string]interface{}{"times": priceFeedMultiplier},
+		},
+		{Type: ethUint256TaskType},
+		{
+			Type: ethTxTaskType,
+			Params: map[string]interface{}{
+				"address": consumerContractAddress,
+				"functionSelector": SimpleConsumerSetValueSignature,
+			},
+		},
+	}
+}
+
+/*
+	Gets the price the SimpleConsumer contract should hold after a job built by this file writes the given price.
+ */
+func GetPriceFeedOnChainValue(price float64) string {
+	return fmt.Sprintf("%.0f", price * priceFeedMultiplier)
+}
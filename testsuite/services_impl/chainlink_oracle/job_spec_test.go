@@ -0,0 +1,90 @@
+package chainlink_oracle
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+const (
+	testContractAddress = "0x1234567890123456789012345678901234567890"
+	testPriceFeedUrl = "http://price-feed-server:8080/price"
+	testCronSchedule = "CRON_TZ=UTC 0/15 * * * * *"
+)
+
+func TestJobSpecs(t *testing.T) {
+	writePriceTasksJson := `[` +
+		`{"type":"HttpGetWithUnrestrictedNetworkAccess","params":{"get":"` + testPriceFeedUrl + `"}},` +
+		`{"type":"JsonParse","params":{"path":["USD"]}},` +
+		`{"type":"Multiply","params":{"times":100}},` +
+		`{"type":"EthUint256"},` +
+		`{"type":"EthTx","params":{"address":"` + testContractAddress + `","functionSelector":"setValue(uint256)"}}` +
+		`]`
+	testCases := []struct {
+		name string
+		jobSpec JobSpec
+		expectedJson string
+		isWebRunnable bool
+	}{
+		{
+			name:    "run log",
+			jobSpec: NewRunLogJobSpec(testContractAddress),
+			expectedJson: `{"initiators":[{"type":"RunLog","params":{"address":"` + testContractAddress + `"}}],` +
+				`"tasks":[{"type":"HttpGetWithUnrestrictedNetworkAccess"},{"type":"JsonParse"},{"type":"Multiply"},` +
+				`{"type":"EthInt256"},{"type":"EthTx"}]}`,
+			isWebRunnable: false,
+		},
+		{
+			name:    "cron",
+			jobSpec: NewCronJobSpec(testCronSchedule, testPriceFeedUrl, testContractAddress),
+			expectedJson: `{"initiators":[{"type":"cron","params":{"schedule":"` + testCronSchedule + `"}}],` +
+				`"tasks":` + writePriceTasksJson + `}`,
+			isWebRunnable: false,
+		},
+		{
+			name:          "web",
+			jobSpec:       NewWebJobSpec(testPriceFeedUrl, testContractAddress),
+			expectedJson:  `{"initiators":[{"type":"web"}],"tasks":` + writePriceTasksJson + `}`,
+			isWebRunnable: true,
+		},
+		{
+			name:    "eth log",
+			jobSpec: NewEthLogJobSpec(testContractAddress),
+			expectedJson: `{"initiators":[{"type":"ethlog","params":{"address":"` + testContractAddress + `"}}],` +
+				`"tasks":[{"type":"NoOp"}]}`,
+			isWebRunnable: false,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			jobSpecBytes, err := json.Marshal(testCase.jobSpec)
+			if err != nil {
+				t.Fatalf("Serializing the job spec failed: %v", err)
+			}
+			if string(jobSpecBytes) != testCase.expectedJson {
+				t.Errorf("Expected job spec JSON\n%v\nbut got\n%v", testCase.expectedJson, string(jobSpecBytes))
+			}
+			if isWebRunnable := testCase.jobSpec.HasInitiator(WebInitiatorType); isWebRunnable != testCase.isWebRunnable {
+				t.Errorf("Expected HasInitiator(%v) to be %v, but was %v", WebInitiatorType, testCase.isWebRunnable, isWebRunnable)
+			}
+		})
+	}
+}
+
+func TestGetPriceFeedOnChainValue(t *testing.T) {
+	testCases := []struct {
+		price float64
+		expected string
+	}{
+		{price: 0, expected: "0"},
+		{price: 1, expected: "100"},
+		{price: 1234.56, expected: "123456"},
+		// Digits beyond the two decimal places that are kept get rounded off
+		{price: 0.124, expected: "12"},
+		{price: 0.126, expected: "13"},
+	}
+	for _, testCase := range testCases {
+		if actual := GetPriceFeedOnChainValue(testCase.price); actual != testCase.expected {
+			t.Errorf("Expected on-chain value of price %v to be %v, but was %v", testCase.price, testCase.expected, actual)
+		}
+	}
+}
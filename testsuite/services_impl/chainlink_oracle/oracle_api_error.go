@@ -0,0 +1,35 @@
+package chainlink_oracle
+
+import (
+	"fmt"
+	"github.com/palantir/stacktrace"
+	"strings"
+)
+
+/*
+	An operator API response with a non-2xx status, carrying the details from the JSON:API errors array the node sends
+	back, or the raw body if it didn't send one.
+ */
+type OracleApiError struct {
+	Method string
+	Endpoint string
+	StatusCode int
+	Details []string
+}
+
+func (apiError *OracleApiError) Error() string {
+	return fmt.Sprintf("Oracle answered %v %v with status %v: %v",
+		apiError.Method,
+		apiError.Endpoint,
+		apiError.StatusCode,
+		strings.Join(apiError.Details, "; "))
+}
+
+/*
+	Finds the OracleApiError at the root of an error returned by ChainlinkOracleService, if there is one, e.g. to tell
+	a rejected request apart from an unreachable node.
+ */
+func GetOracleApiError(err error) (*OracleApiError, bool) {
+	apiError, ok := stacktrace.RootCause(err).(*OracleApiError)
+	return apiError, ok
+}
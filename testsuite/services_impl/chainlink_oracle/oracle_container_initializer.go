@@ -11,8 +11,9 @@ import (
 )
 
 const (
-	oracleEmail = "user@example.com"
-	oraclePassword = "qWeRtY123!@#qWeRtY123!@#"
+	// Operator credentials the node is started with, which the operator API is logged into with
+	OracleEmail = "user@example.com"
+	OraclePassword = "qWeRtY123!@#qWeRtY123!@#"
 	oracleWalletPassword = "qWeRtY123!@#qWeRtY123!@#"
 
 	passwordFileKey = "password-file"
@@ -26,25 +27,35 @@ const (
 	minOutgoingConfirmations = 12
 	minIncomingConfirmations = 0
 
+	// Environment variables that tests commonly override
+	MinIncomingConfirmationsEnvVar = "MIN_INCOMING_CONFIRMATIONS"
+	MinOutgoingConfirmationsEnvVar = "MIN_OUTGOING_CONFIRMATIONS"
+	// Smallest payment, in juels, the node accepts for a request
+	MinimumContractPaymentEnvVar = "MINIMUM_CONTRACT_PAYMENT"
+
 	operatorUiPort = 6688
+	oracleRootDirpath = "/chainlink"
 )
 
 type ChainlinkOracleInitializer struct {
 	dockerImage         string
 	linkContractAddress string
 	oracleContractAddress string
-	gethClient	*geth.GethService
-	postgresService	*postgres.PostgresService
+	gethClient	geth.GethNode
+	databaseCredentials	postgres.DatabaseCredentials
+	// Replace the defaults for the same variables
+	environmentOverrides map[string]string
 }
 
 func NewChainlinkOracleContainerInitializer(dockerImage string, linkContractAddress string, oracleContractAddress string,
-	gethClient *geth.GethService, postgresService *postgres.PostgresService) *ChainlinkOracleInitializer {
+	gethClient geth.GethNode, databaseCredentials postgres.DatabaseCredentials, environmentOverrides map[string]string) *ChainlinkOracleInitializer {
 	return &ChainlinkOracleInitializer{
 		dockerImage:         dockerImage,
 		linkContractAddress: linkContractAddress,
 		oracleContractAddress: oracleContractAddress,
 		gethClient: gethClient,
-		postgresService: postgresService,
+		databaseCredentials: databaseCredentials,
+		environmentOverrides: environmentOverrides,
 	}
 }
 
@@ -59,7 +70,7 @@ func (initializer ChainlinkOracleInitializer) GetUsedPorts() map[string]bool {
 }
 
 func (initializer ChainlinkOracleInitializer) GetService(ctx *services.ServiceContext) services.Service {
-	return NewChainlinkOracleService(ctx);
+	return NewChainlinkOracleService(ctx, operatorUiPort);
 }
 
 func (initializer ChainlinkOracleInitializer) GetFilesToGenerate() map[string]bool {
@@ -71,12 +82,12 @@ func (initializer ChainlinkOracleInitializer) GetFilesToGenerate() map[string]bo
 }
 
 func (initializer ChainlinkOracleInitializer) GetEnvironmentVariableOverrides() (map[string]string, error) {
-	return map[string]string {
-		"ROOT": "/chainlink",
+	environment := map[string]string {
+		"ROOT": oracleRootDirpath,
 		"LOG_LEVEL": "debug",
 		"ETH_CHAIN_ID": fmt.Sprintf("%v", geth.PrivateNetworkId),
-		"MIN_OUTGOING_CONFIRMATIONS": strconv.Itoa(minOutgoingConfirmations),
-		"MIN_INCOMING_CONFIRMATIONS": strconv.Itoa(minIncomingConfirmations),
+		MinOutgoingConfirmationsEnvVar: strconv.Itoa(minOutgoingConfirmations),
+		MinIncomingConfirmationsEnvVar: strconv.Itoa(minIncomingConfirmations),
 		"ETH_GAS_PRICE_DEFAULT": strconv.Itoa(gasPriceDefault),
 		"ETH_GAS_BUMP_THRESHOLD": strconv.Itoa(gasPriceBumpThreshold),
 		"ETH_GAS_BUMP_WEI": ethGasBumpWei,
@@ -88,15 +99,17 @@ func (initializer ChainlinkOracleInitializer) GetEnvironmentVariableOverrides()
 		"GAS_UPDATER_BLOCK_DELAY": strconv.Itoa(gasUpdaterDelay),
 		"ALLOW_ORIGINS":"*",
 		"ETH_URL": fmt.Sprintf("ws://%v:%v", initializer.gethClient.GetIPAddress(), initializer.gethClient.GetWsPort()),
-		"DATABASE_URL": fmt.Sprintf("postgresql://%v:%v@%v:%v/%v?sslmode=disable",
-			initializer.postgresService.GetSuperUsername(), initializer.postgresService.GetSuperUserPassword(),
-			initializer.postgresService.GetIPAddress(), initializer.postgresService.GetPort(), initializer.postgresService.GetDatabaseName()),
-	}, nil
+		"DATABASE_URL": initializer.databaseCredentials.GetConnectionUrl(),
+	}
+	for name, value := range initializer.environmentOverrides {
+		environment[name] = value
+	}
+	return environment, nil
 }
 
 func (initializer ChainlinkOracleInitializer) InitializeGeneratedFiles(mountedFiles map[string]*os.File) error {
 	passwordFileString := getOraclePasswordFile(oracleWalletPassword)
-	apiFileString := getOracleApiFile(oracleEmail, oraclePassword)
+	apiFileString := getOracleApiFile(OracleEmail, OraclePassword)
 
 	passwordFileFp := mountedFiles[passwordFileKey]
 	_, err := passwordFileFp.WriteString(passwordFileString)
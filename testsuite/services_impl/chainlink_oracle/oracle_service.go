@@ -4,16 +4,22 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
-	"github.com/kurtosis-tech/kurtosis-libs/golang/lib/services"
+	"github.com/kurtosistech/chainlink-testing/testsuite/services_impl/geth"
+	"github.com/kurtosistech/chainlink-testing/testsuite/services_impl/service_context"
 	"github.com/palantir/stacktrace"
 	"github.com/sirupsen/logrus"
 	"golang.org/x/net/publicsuffix"
 	"io"
 	"io/ioutil"
+	"math/big"
 	"net"
 	"net/http"
 	"net/http/cookiejar"
+	"net/url"
+	"path"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 )
 
@@ -21,13 +27,76 @@ const (
 	isAvailableDialTimeout = time.Second
 
 	sessionsEndpoint = "sessions"
+	apiTokenEndpoint = "v2/user/token"
+	// Cheap endpoint that needs auth, used to check whether we're logged in
+	configEndpoint = "v2/config"
 	specsEndpoint = "v2/specs"
 	ethAccountsEndpoint = "v2/keys/eth"
+	// Keys are imported by posting a keystore to this path under the keys endpoint, and exported by posting to it
+	// followed by the key's address
+	ethKeysImportPathSegment = "import"
+	ethKeysExportPathSegment = "export"
+	// Password the keystore being imported is encrypted with
+	oldPasswordQueryParam = "oldpassword"
+	// Password to encrypt the exported keystore with
+	newPasswordQueryParam = "newpassword"
+	// Deleting a key without this only archives it, keeping its address reserved
+	hardDeleteQueryParam = "hard"
 	runsEndpoint = "v2/runs"
+	// Runs of a web initiated job are started by posting to this path under the job's spec
+	specRunsPathSegment = "runs"
+	metricsEndpoint = "metrics"
+	JobSpecsEndpoint = specsEndpoint
+	EthKeysEndpoint = ethAccountsEndpoint
+	RunsEndpoint = runsEndpoint
+
+	// Headers the node reads API token credentials from, as an alternative to a session cookie
+	apiKeyHeader = "X-API-KEY"
+	apiSecretHeader = "X-API-SECRET"
+
+	httpClientTimeout = 60 * time.Second
+
+	// The node writes its logs to this file under its root directory
+	oracleLogFilepath = oracleRootDirpath + "/log.jsonl"
+
+	// Prometheus metrics exported by the Chainlink node
+	RunStatusUpdateMetric = "run_status_update"
+	RunStatusLabel = "status"
+	HeadTrackerCurrentHeadMetric = "head_tracker_current_head"
+	TxManagerNumTxRevertedMetric = "tx_manager_num_tx_reverted"
+	TxManagerNumGasBumpsMetric = "tx_manager_num_gas_bumps"
+
+	// Query parameters of the paginated runs endpoint
+	pageQueryParam = "page"
+	pageSizeQueryParam = "size"
+	jobSpecIdQueryParam = "jobSpecId"
+	// Runs asked for per page
+	runsPageSize = 100
+	// Most pages of runs read in one scan, so a node that pages wrongly can't keep a scan going forever
+	maxRunsPages = 1000
+
+	waitForRunTimeBetweenPolls = 1 * time.Second
+
+	// Run statuses the Chainlink node reports
+	RunStatusInProgress = "in_progress"
+	RunStatusPendingIncomingConfirmations = "pending_incoming_confirmations"
+	RunStatusPendingOutgoingConfirmations = "pending_outgoing_confirmations"
+	RunStatusCompleted = "completed"
+	RunStatusErrored = "errored"
 )
 
 type RunsResponse struct {
 	Data []Run `json:"data"`
+	Meta RunsMeta `json:"meta"`
+}
+
+type RunsMeta struct {
+	// Total number of runs matching the query, across every page
+	Count int `json:"count"`
+}
+
+type RunResponse struct {
+	Data Run `json:"data"`
 }
 
 type Run struct {
@@ -42,16 +111,52 @@ type RunAttributes struct {
 	TaskRuns []TaskRun `json:"taskRuns"`
 	Initiator Initiator `json:"initiator"`
 	Payment string `json:"payment"`
+	RunRequest RunRequest `json:"runRequest"`
+	// Input the run started with; for EthLog initiated runs, this is the log that started it
+	Overrides json.RawMessage `json:"overrides"`
+	CreatedAt time.Time `json:"createdAt"`
+	FinishedAt *time.Time `json:"finishedAt"`
 }
 
 type TaskRun struct {
 	Id string `json:"id"`
 	Status string `json:"status"`
+	Result TaskRunResult `json:"result"`
+}
+
+type TaskRunResult struct {
+	Data TaskRunResultData `json:"data"`
+	ErrorMessage *string `json:"error"`
+}
+
+type TaskRunResultData struct {
+	// Output of the task; for an EthTx task, this is the hash of the transaction it submitted
+	Result interface{} `json:"result"`
+}
+
+// The on-chain request that triggered a run, for RunLog-initiated jobs
+type RunRequest struct {
+	RequestId string `json:"requestId"`
+	TxHash string `json:"txHash"`
+	BlockHash string `json:"blockHash"`
+	Requester string `json:"requester"`
+	Payment string `json:"payment"`
+}
+
+// The log an EthLog initiated run was started by, as the node stores it in the run's overrides
+type LogEvent struct {
+	Address string `json:"address"`
+	Topics []string `json:"topics"`
+	Data string `json:"data"`
+	BlockNumber string `json:"blockNumber"`
+	TransactionHash string `json:"transactionHash"`
 }
 
 type Initiator struct {
 	Id int `json:"id"`
 	JobSpecId string `json:"jobSpecId"`
+	// One of the initiator types, e.g. RunLogInitiatorType
+	Type string `json:"type"`
 }
 
 
@@ -64,6 +169,10 @@ type OracleEthereumKey struct {
 	Attributes EthereumKeyAttributes `json:"attributes"`
 }
 
+type OracleEthereumKeyResponse struct {
+	Data OracleEthereumKey `json:"data"`
+}
+
 type EthereumKeyAttributes struct {
 	Address string `json:"address"`
 	EthBalance string `json:"ethBalance"`
@@ -78,123 +187,498 @@ type OracleJobInitiatedData struct {
 	Id string `json:"id"`
 }
 
+/*
+	Credentials of an API token, which authenticate requests without a session.
+ */
+type ApiCredentials struct {
+	AccessKey string `json:"accessKey"`
+	Secret string `json:"secret"`
+}
+
+type ApiTokenResponse struct {
+	Data ApiTokenData `json:"data"`
+}
+
+type ApiTokenData struct {
+	Attributes ApiCredentials `json:"attributes"`
+}
+
+type sessionRequest struct {
+	Email string `json:"email"`
+	Password string `json:"password"`
+}
+
+type apiTokenRequest struct {
+	Password string `json:"password"`
+}
+
+type jsonApiErrorsResponse struct {
+	Errors []struct {
+		Detail string `json:"detail"`
+	} `json:"errors"`
+}
+
+/*
+	A Chainlink node the network can talk to. The network holds its Oracles by this rather than by
+	ChainlinkOracleService, so its logic can be exercised against stand-in nodes without live containers.
+ */
+type ChainlinkOracle interface {
+	IsAvailable() bool
+
+	GetOperatorPort() int
+
+	GetIPAddress() string
+
+	GetMetricsUrl() string
+
+	GetRawApiResponse(endpoint string) ([]byte, error)
+
+	CopyLogsToTestVolume(relativeDirpath string) error
+
+	GetRuns() ([]Run, error)
+
+	GetRunsForJob(jobId string) ([]Run, error)
+
+	GetRun(runId string) (Run, error)
+
+	WaitForRun(jobId string, predicate func(run Run) bool, maxNumPolls int) (Run, error)
+
+	GetEthAccounts() ([]OracleEthereumKey, error)
+
+	CreateEthKey() (OracleEthereumKey, error)
+
+	ImportEthKey(keystoreJson []byte, password string) (OracleEthereumKey, error)
+
+	ExportEthKey(address string, password string) ([]byte, error)
+
+	DeleteEthKey(address string) error
+
+	SetJobSpec(oracleContractAddress string) (jobId string, err error)
+
+	CreateJob(jobSpec JobSpec) (jobId string, err error)
+
+	ArchiveJob(jobId string) error
+
+	TriggerJobRun(jobId string) (Run, error)
+
+	StartSession() (string, error)
+
+	IsLoggedIn() (bool, error)
+
+	CreateApiToken() (ApiCredentials, error)
+
+	SetApiCredentials(apiCredentials ApiCredentials)
+}
+
+var _ ChainlinkOracle = (*ChainlinkOracleService)(nil)
+
 type ChainlinkOracleService struct {
-	serviceCtx *services.ServiceContext
+	serviceCtx service_context.ServiceContext
+	operatorPort int
+
+	// Mutex protecting the auth state below, since the service gets called from several goroutines
+	mutex *sync.Mutex
 	clientWithSession *http.Client
-	sessionCookieJar *cookiejar.Jar
+	// When set, requests authenticate with these rather than a session
+	apiCredentials *ApiCredentials
 }
 
-func NewChainlinkOracleService(serviceCtx *services.ServiceContext) *ChainlinkOracleService {
-	return &ChainlinkOracleService{serviceCtx: serviceCtx}
+func NewChainlinkOracleService(serviceCtx service_context.ServiceContext, operatorPort int) *ChainlinkOracleService {
+	return &ChainlinkOracleService{
+		serviceCtx:   serviceCtx,
+		operatorPort: operatorPort,
+		mutex:        &sync.Mutex{},
+	}
 }
 
 func (chainlinkOracleService *ChainlinkOracleService) GetOperatorPort() int {
-	return operatorUiPort
+	return chainlinkOracleService.operatorPort
 }
 
 func (chainlinkOracleService *ChainlinkOracleService) GetIPAddress() string {
 	return chainlinkOracleService.serviceCtx.GetIPAddress()
 }
 
-func (chainlinkOracleService *ChainlinkOracleService) GetRuns() ([]Run, error) {
-	if chainlinkOracleService.clientWithSession == nil {
-		_, err := chainlinkOracleService.StartSession()
-		if err != nil {
-			return nil, stacktrace.Propagate(err, "Failed to start session on Oracle.")
-		}
+/*
+	URL of the node's Prometheus endpoint, which doesn't require a session.
+ */
+func (chainlinkOracleService *ChainlinkOracleService) GetMetricsUrl() string {
+	return fmt.Sprintf("http://%v:%v/%v",
+		chainlinkOracleService.GetIPAddress(), chainlinkOracleService.GetOperatorPort(), metricsEndpoint)
+}
+
+/*
+	Gets the raw body returned by a GET to an operator API endpoint, e.g. for saving as a test artifact.
+ */
+func (chainlinkOracleService *ChainlinkOracleService) GetRawApiResponse(endpoint string) ([]byte, error) {
+	response, err := chainlinkOracleService.sendAuthenticatedRequest(http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "Failed to get %v from Oracle.", endpoint)
+	}
+	bodyBytes, err := readApiResponse(response)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "Failed to read Oracle response from %v.", endpoint)
+	}
+	return bodyBytes, nil
+}
+
+/*
+	Copies the node's log file into the given directory, relative to the root of the test volume.
+ */
+func (chainlinkOracleService *ChainlinkOracleService) CopyLogsToTestVolume(relativeDirpath string) error {
+	destDirpath := path.Join(geth.TestVolumeMountpoint, relativeDirpath)
+	copyLogsCommand := []string{
+		"/bin/sh",
+		"-c",
+		fmt.Sprintf("mkdir -p %v && cp %v %v/", destDirpath, oracleLogFilepath, destDirpath),
+	}
+	exitCode, logOutput, err := chainlinkOracleService.serviceCtx.ExecCommand(copyLogsCommand)
+	if err != nil {
+		return stacktrace.Propagate(err, "Failed to execute command to copy Oracle logs.")
+	}
+	if exitCode != 0 {
+		return stacktrace.NewError("Copying Oracle logs exited with code %v: %v", exitCode, string(*logOutput))
+	}
+	return nil
+}
+
+/*
+	Gets the log that started an EthLog initiated run.
+ */
+func (run Run) GetLogEvent() (LogEvent, error) {
+	var logEvent LogEvent
+	if len(run.Attributes.Overrides) == 0 {
+		return LogEvent{}, stacktrace.NewError("Run %v has no overrides to read a log from.", run.Attributes.Id)
 	}
-	urlStr := fmt.Sprintf("http://%v:%v/%v",
-		chainlinkOracleService.GetIPAddress(), chainlinkOracleService.GetOperatorPort(), runsEndpoint)
-	response, err := chainlinkOracleService.clientWithSession.Get(urlStr)
+	if err := json.Unmarshal(run.Attributes.Overrides, &logEvent); err != nil {
+		return LogEvent{}, stacktrace.Propagate(err, "Failed to parse the overrides of run %v as a log.", run.Attributes.Id)
+	}
+	return logEvent, nil
+}
+
+/*
+	Gets every run the node has, going through every page of the runs endpoint.
+ */
+func (chainlinkOracleService *ChainlinkOracleService) GetRuns() ([]Run, error) {
+	runs, err := chainlinkOracleService.getAllRunPages(url.Values{})
 	if err != nil {
 		return nil, stacktrace.Propagate(err, "Failed to get runs information from Oracle.")
 	}
-	runsResponse := new(RunsResponse)
-	err = parseAndLogResponse(response, runsResponse)
+	return runs, nil
+}
+
+/*
+	Gets every run of the job with the given ID, newest first.
+ */
+func (chainlinkOracleService *ChainlinkOracleService) GetRunsForJob(jobId string) ([]Run, error) {
+	query := url.Values{}
+	query.Set(jobSpecIdQueryParam, jobId)
+	runs, err := chainlinkOracleService.getAllRunPages(query)
 	if err != nil {
-		return nil, stacktrace.Propagate(err, "Failed to parse Oracle response into a struct.")
+		return nil, stacktrace.Propagate(err, "Failed to get the runs of job %v from Oracle.", jobId)
 	}
-	return runsResponse.Data, nil
+	return runs, nil
 }
 
-func (chainlinkOracleService *ChainlinkOracleService) GetEthAccounts() ([]OracleEthereumKey, error) {
-	if chainlinkOracleService.clientWithSession == nil {
-		_, err := chainlinkOracleService.StartSession()
+func (chainlinkOracleService *ChainlinkOracleService) GetRun(runId string) (Run, error) {
+	response, err := chainlinkOracleService.sendAuthenticatedRequest(http.MethodGet, path.Join(runsEndpoint, runId), nil)
+	if err != nil {
+		return Run{}, stacktrace.Propagate(err, "Failed to get run %v from Oracle.", runId)
+	}
+	runResponse := new(RunResponse)
+	if err := parseApiResponse(response, runResponse); err != nil {
+		return Run{}, stacktrace.Propagate(err, "Failed to parse Oracle response into a struct.")
+	}
+	return runResponse.Data, nil
+}
+
+/*
+	Polls the runs of the given job until one the predicate matches has finished, returning it if it completed. A run
+	that errored gives an error with the messages of its failed tasks. Errors getting runs are retried, since the API
+	can be briefly unavailable, e.g. while the node reconnects to its database.
+ */
+func (chainlinkOracleService *ChainlinkOracleService) WaitForRun(jobId string, predicate func(run Run) bool, maxNumPolls int) (Run, error) {
+	var lastPollErr error
+	for numPolls := 0; numPolls < maxNumPolls; numPolls++ {
+		if numPolls > 0 {
+			time.Sleep(waitForRunTimeBetweenPolls)
+		}
+		runs, err := chainlinkOracleService.GetRunsForJob(jobId)
 		if err != nil {
-			return nil, stacktrace.Propagate(err, "Failed to start session on Oracle.")
+			logrus.Debugf("Couldn't get the runs of job %v while waiting for a run: %v", jobId, err)
+			lastPollErr = err
+			continue
 		}
+		for _, run := range runs {
+			if !predicate(run) {
+				continue
+			}
+			switch run.Attributes.Status {
+			case RunStatusCompleted:
+				return run, nil
+			case RunStatusErrored:
+				return Run{}, stacktrace.NewError("Run %v of job %v errored: %v", run.Attributes.Id, jobId, strings.Join(getTaskRunErrors(run), "; "))
+			}
+		}
+	}
+	if lastPollErr != nil {
+		return Run{}, stacktrace.Propagate(lastPollErr, "No matching run of job %v finished after %v polls; the last poll failed.", jobId, maxNumPolls)
+	}
+	return Run{}, stacktrace.NewError("No matching run of job %v finished after %v polls.", jobId, maxNumPolls)
+}
+
+/*
+	Parses the $LINK balance the node reported for the key, in juels.
+ */
+func (key OracleEthereumKey) GetLinkBalance() (*big.Int, error) {
+	linkBalance, ok := new(big.Int).SetString(key.Attributes.LinkBalance, 10)
+	if !ok {
+		return nil, stacktrace.NewError("Couldn't parse $LINK balance '%v' of key %v", key.Attributes.LinkBalance, key.Attributes.Address)
 	}
-	urlStr := fmt.Sprintf("http://%v:%v/%v",
-		chainlinkOracleService.GetIPAddress(), chainlinkOracleService.GetOperatorPort(), ethAccountsEndpoint)
-	response, err := chainlinkOracleService.clientWithSession.Get(urlStr)
+	return linkBalance, nil
+}
+
+func (chainlinkOracleService *ChainlinkOracleService) GetEthAccounts() ([]OracleEthereumKey, error) {
+	response, err := chainlinkOracleService.sendAuthenticatedRequest(http.MethodGet, ethAccountsEndpoint, nil)
 	if err != nil {
 		return nil, stacktrace.Propagate(err, "Failed to get ethereum account info from Oracle.")
 	}
 	ethereumKeysResponse := new(OracleEthereumKeysResponse)
-
-	err = parseAndLogResponse(response, ethereumKeysResponse)
+	err = parseApiResponse(response, ethereumKeysResponse)
 	if err != nil {
 		return nil, stacktrace.Propagate(err, "Failed to parse Oracle response into a struct.")
 	}
 	return ethereumKeysResponse.Data, nil
 }
 
-func (chainlinkOracleService *ChainlinkOracleService) SetJobSpec(oracleContractAddress string) (jobId string, err error) {
-	if chainlinkOracleService.clientWithSession == nil {
-		_, err := chainlinkOracleService.StartSession()
-		if err != nil {
-			return "", stacktrace.Propagate(err, "Failed to start session on Oracle.")
-		}
+/*
+	Has the node generate a new ethereum key, which it starts sending transactions from alongside its other keys.
+ */
+func (chainlinkOracleService *ChainlinkOracleService) CreateEthKey() (OracleEthereumKey, error) {
+	response, err := chainlinkOracleService.sendAuthenticatedRequest(http.MethodPost, ethAccountsEndpoint, nil)
+	if err != nil {
+		return OracleEthereumKey{}, stacktrace.Propagate(err, "Failed to create an ethereum key on the Oracle.")
+	}
+	ethereumKeyResponse := new(OracleEthereumKeyResponse)
+	if err := parseApiResponse(response, ethereumKeyResponse); err != nil {
+		return OracleEthereumKey{}, stacktrace.Propagate(err, "The Oracle didn't create an ethereum key.")
 	}
-	jobSpecJsonStr := generateJobSpec(oracleContractAddress)
-	jsonByteArray := []byte(jobSpecJsonStr)
-	urlStr := fmt.Sprintf("http://%v:%v/%v",
-		chainlinkOracleService.GetIPAddress(), chainlinkOracleService.GetOperatorPort(), specsEndpoint)
+	return ethereumKeyResponse.Data, nil
+}
 
-	response, err := chainlinkOracleService.clientWithSession.Post(urlStr, "application/json", bytes.NewBuffer(jsonByteArray))
+/*
+	Imports the key in the given JSON keystore, encrypted with the given password, e.g. one of the prefunded accounts
+	from the geth data dir.
+ */
+func (chainlinkOracleService *ChainlinkOracleService) ImportEthKey(keystoreJson []byte, password string) (OracleEthereumKey, error) {
+	query := url.Values{}
+	query.Set(oldPasswordQueryParam, password)
+	endpoint := path.Join(ethAccountsEndpoint, ethKeysImportPathSegment) + "?" + query.Encode()
+	response, err := chainlinkOracleService.sendAuthenticatedRequest(http.MethodPost, endpoint, keystoreJson)
+	if err != nil {
+		return OracleEthereumKey{}, stacktrace.Propagate(err, "Failed to import an ethereum key into the Oracle.")
+	}
+	ethereumKeyResponse := new(OracleEthereumKeyResponse)
+	if err := parseApiResponse(response, ethereumKeyResponse); err != nil {
+		return OracleEthereumKey{}, stacktrace.Propagate(err, "The Oracle didn't import the ethereum key.")
+	}
+	return ethereumKeyResponse.Data, nil
+}
+
+/*
+	Exports the key with the given address as a JSON keystore encrypted with the given password, which ImportEthKey
+	accepts.
+ */
+func (chainlinkOracleService *ChainlinkOracleService) ExportEthKey(address string, password string) ([]byte, error) {
+	query := url.Values{}
+	query.Set(newPasswordQueryParam, password)
+	endpoint := path.Join(ethAccountsEndpoint, ethKeysExportPathSegment, address) + "?" + query.Encode()
+	response, err := chainlinkOracleService.sendAuthenticatedRequest(http.MethodPost, endpoint, nil)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "Failed to export ethereum key %v from the Oracle.", address)
+	}
+	keystoreJson, err := readApiResponse(response)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "The Oracle didn't export ethereum key %v.", address)
+	}
+	return keystoreJson, nil
+}
+
+/*
+	Permanently deletes the key with the given address, so the node stops sending transactions from it. It can be
+	imported again afterwards.
+ */
+func (chainlinkOracleService *ChainlinkOracleService) DeleteEthKey(address string) error {
+	query := url.Values{}
+	query.Set(hardDeleteQueryParam, "true")
+	endpoint := path.Join(ethAccountsEndpoint, address) + "?" + query.Encode()
+	response, err := chainlinkOracleService.sendAuthenticatedRequest(http.MethodDelete, endpoint, nil)
+	if err != nil {
+		return stacktrace.Propagate(err, "Failed to delete ethereum key %v from the Oracle.", address)
+	}
+	if _, err := readApiResponse(response); err != nil {
+		return stacktrace.Propagate(err, "The Oracle didn't delete ethereum key %v.", address)
+	}
+	return nil
+}
+
+/*
+	Creates the price feed job started by requests to the Oracle contract at the given address, returning its ID.
+ */
+func (chainlinkOracleService *ChainlinkOracleService) SetJobSpec(oracleContractAddress string) (jobId string, err error) {
+	jobId, err = chainlinkOracleService.CreateJob(NewRunLogJobSpec(oracleContractAddress))
 	if err != nil {
 		return "", stacktrace.Propagate(err, "Encountered an error trying to set job spec on the Oracle.")
 	}
-	jobInitiatedResponse := new(OracleJobInitiatedResponse)
-	defer response.Body.Close()
+	return jobId, nil
+}
 
-	err = parseAndLogResponse(response, jobInitiatedResponse)
+/*
+	Creates a job from the given spec, returning its ID.
+ */
+func (chainlinkOracleService *ChainlinkOracleService) CreateJob(jobSpec JobSpec) (jobId string, err error) {
+	jsonByteArray, err := json.Marshal(jobSpec)
 	if err != nil {
+		return "", stacktrace.Propagate(err, "An error occurred serializing the job spec.")
+	}
+	response, err := chainlinkOracleService.sendAuthenticatedRequest(http.MethodPost, specsEndpoint, jsonByteArray)
+	if err != nil {
+		return "", stacktrace.Propagate(err, "Encountered an error trying to create a job on the Oracle.")
+	}
+	jobInitiatedResponse := new(OracleJobInitiatedResponse)
+	err = parseApiResponse(response, jobInitiatedResponse)
+	if err != nil {
+		if apiError, ok := GetOracleApiError(err); ok {
+			return "", stacktrace.Propagate(err, "The Oracle rejected the job spec: %v", strings.Join(apiError.Details, "; "))
+		}
 		return "", stacktrace.Propagate(err, "Failed to parse Oracle response into a struct.")
 	}
+	if jobInitiatedResponse.Data.Id == "" {
+		return "", stacktrace.NewError("The Oracle accepted the job spec, but didn't say what ID the job got.")
+	}
 	return jobInitiatedResponse.Data.Id, nil
 }
 
+/*
+	Archives the job, so the node starts no new runs of it; its existing runs are kept.
+ */
+func (chainlinkOracleService *ChainlinkOracleService) ArchiveJob(jobId string) error {
+	response, err := chainlinkOracleService.sendAuthenticatedRequest(http.MethodDelete, path.Join(specsEndpoint, jobId), nil)
+	if err != nil {
+		return stacktrace.Propagate(err, "Failed to archive job %v on the Oracle.", jobId)
+	}
+	if _, err := readApiResponse(response); err != nil {
+		return stacktrace.Propagate(err, "The Oracle didn't archive job %v.", jobId)
+	}
+	return nil
+}
+
+/*
+	Starts a run of a job with a web initiator, returning the run as the node reported it when it started.
+ */
+func (chainlinkOracleService *ChainlinkOracleService) TriggerJobRun(jobId string) (Run, error) {
+	endpoint := path.Join(specsEndpoint, jobId, specRunsPathSegment)
+	response, err := chainlinkOracleService.sendAuthenticatedRequest(http.MethodPost, endpoint, []byte("{}"))
+	if err != nil {
+		return Run{}, stacktrace.Propagate(err, "Failed to trigger a run of job %v on the Oracle.", jobId)
+	}
+	runResponse := new(RunResponse)
+	if err := parseApiResponse(response, runResponse); err != nil {
+		return Run{}, stacktrace.Propagate(err, "The Oracle didn't start a run of job %v.", jobId)
+	}
+	return runResponse.Data, nil
+}
+
+/*
+	Logs in with the operator's email and password, after which requests authenticate with the session cookie. Only
+	needs calling directly to check the credentials, since requests log in on their own when they have no session.
+ */
 func (chainlinkOracleService *ChainlinkOracleService) StartSession() (string, error) {
-	authJsonStr := fmt.Sprintf("{\"email\":\"%v\", \"password\":\"%v\"}",
-		oracleEmail, oraclePassword)
-	authByteArray := []byte(authJsonStr)
-	urlStr := fmt.Sprintf("http://%v:%v/%v",
-		chainlinkOracleService.GetIPAddress(), chainlinkOracleService.GetOperatorPort(), sessionsEndpoint)
+	authByteArray, err := json.Marshal(sessionRequest{Email: OracleEmail, Password: OraclePassword})
+	if err != nil {
+		return "", stacktrace.Propagate(err, "An error occurred serializing the Oracle's login credentials.")
+	}
+	urlStr := chainlinkOracleService.getEndpointUrl(sessionsEndpoint)
 	// Create new cookiejar for holding cookies
 	jar, _ := cookiejar.New(&cookiejar.Options{PublicSuffixList: publicsuffix.List})
 
 	// Create new http client with predefined options
 	client := &http.Client{
 		Jar:     jar,
-		Timeout: time.Second * 60,
+		Timeout: httpClientTimeout,
 	}
 	authResp, err := client.Post(urlStr, "application/json", bytes.NewBuffer(authByteArray))
 	if err != nil {
 		return "", stacktrace.Propagate(err, "Encountered an error trying to authenticate with the oracle service..")
 	}
+	if _, err := readApiResponse(authResp); err != nil {
+		return "", stacktrace.Propagate(err, "Oracle rejected the login.")
+	}
 	logrus.Debugf("After starting sessions, cookies look like: %+v", jar)
+	chainlinkOracleService.mutex.Lock()
 	chainlinkOracleService.clientWithSession = client
+	chainlinkOracleService.mutex.Unlock()
 	return authResp.Status, nil
 }
 
+/*
+	Checks whether requests are currently authenticated, either by a session or by API token credentials, without
+	logging in. Only a 401 means not logged in; any other answer but success is an error, as it says nothing either way.
+ */
+func (chainlinkOracleService *ChainlinkOracleService) IsLoggedIn() (bool, error) {
+	client, apiCredentials := chainlinkOracleService.getAuthState()
+	if client == nil {
+		return false, nil
+	}
+	response, err := chainlinkOracleService.sendRequest(client, apiCredentials, http.MethodGet, configEndpoint, nil)
+	if err != nil {
+		return false, stacktrace.Propagate(err, "Failed to check the Oracle's login status.")
+	}
+	if response.StatusCode == http.StatusUnauthorized {
+		response.Body.Close()
+		return false, nil
+	}
+	if _, err := readApiResponse(response); err != nil {
+		return false, stacktrace.Propagate(err, "The Oracle didn't answer whether requests are logged in.")
+	}
+	return true, nil
+}
+
+/*
+	Creates an API token for the operator, whose credentials can be passed to SetApiCredentials.
+ */
+func (chainlinkOracleService *ChainlinkOracleService) CreateApiToken() (ApiCredentials, error) {
+	requestBytes, err := json.Marshal(apiTokenRequest{Password: OraclePassword})
+	if err != nil {
+		return ApiCredentials{}, stacktrace.Propagate(err, "An error occurred serializing the API token request.")
+	}
+	response, err := chainlinkOracleService.sendAuthenticatedRequest(http.MethodPost, apiTokenEndpoint, requestBytes)
+	if err != nil {
+		return ApiCredentials{}, stacktrace.Propagate(err, "Failed to create an API token on the Oracle.")
+	}
+	tokenResponse := new(ApiTokenResponse)
+	if err := parseApiResponse(response, tokenResponse); err != nil {
+		return ApiCredentials{}, stacktrace.Propagate(err, "Failed to parse Oracle response into a struct.")
+	}
+	return tokenResponse.Data.Attributes, nil
+}
+
+/*
+	Makes requests authenticate with the given API token credentials rather than a session.
+ */
+func (chainlinkOracleService *ChainlinkOracleService) SetApiCredentials(apiCredentials ApiCredentials) {
+	chainlinkOracleService.mutex.Lock()
+	defer chainlinkOracleService.mutex.Unlock()
+	chainlinkOracleService.apiCredentials = &apiCredentials
+}
+
 // ===========================================================================================
 //                              Service interface methods
 // ===========================================================================================
 
 func (chainlinkOracleService *ChainlinkOracleService) IsAvailable() bool {
 	conn, err := net.DialTimeout("tcp",
-		net.JoinHostPort(chainlinkOracleService.GetIPAddress(), strconv.Itoa(operatorUiPort)), isAvailableDialTimeout)
+		net.JoinHostPort(chainlinkOracleService.GetIPAddress(), strconv.Itoa(chainlinkOracleService.GetOperatorPort())), isAvailableDialTimeout)
 	if err != nil {
 		return false
 	}
@@ -205,52 +689,183 @@ func (chainlinkOracleService *ChainlinkOracleService) IsAvailable() bool {
 	return true
 }
 
-func generateJobSpec(oracleContractAddress string) string {
-	return fmt.Sprintf(`{
-		  "initiators": [
-			{
-			  "type": "RunLog",
-			  "params": { "address": "%v" }
+/*
+	Sends a request to the operator API, logging in first if there's no session yet, and logging in again and retrying
+	once if the node says the session has expired. The response should be handled with readApiResponse or
+	parseApiResponse, which check its status and close its body.
+ */
+func (chainlinkOracleService *ChainlinkOracleService) sendAuthenticatedRequest(method string, endpoint string, body []byte) (*http.Response, error) {
+	client, apiCredentials := chainlinkOracleService.getAuthState()
+	if client == nil {
+		if _, err := chainlinkOracleService.StartSession(); err != nil {
+			return nil, stacktrace.Propagate(err, "Failed to start session on Oracle.")
+		}
+		client, apiCredentials = chainlinkOracleService.getAuthState()
+	}
+	response, err := chainlinkOracleService.sendRequest(client, apiCredentials, method, endpoint, body)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "Failed to send %v request to Oracle endpoint %v.", method, endpoint)
+	}
+	// API token credentials don't expire, so only sessions are worth retrying
+	if response.StatusCode == http.StatusUnauthorized && apiCredentials == nil {
+		response.Body.Close()
+		logrus.Debugf("Oracle session was rejected by %v %v; logging in again.", method, endpoint)
+		if _, err := chainlinkOracleService.StartSession(); err != nil {
+			return nil, stacktrace.Propagate(err, "Failed to restart session on Oracle.")
+		}
+		client, apiCredentials = chainlinkOracleService.getAuthState()
+		response, err = chainlinkOracleService.sendRequest(client, apiCredentials, method, endpoint, body)
+		if err != nil {
+			return nil, stacktrace.Propagate(err, "Failed to send %v request to Oracle endpoint %v.", method, endpoint)
+		}
+	}
+	return response, nil
+}
+
+func (chainlinkOracleService *ChainlinkOracleService) sendRequest(client *http.Client, apiCredentials *ApiCredentials, method string, endpoint string, body []byte) (*http.Response, error) {
+	var bodyReader io.Reader
+	if body != nil {
+		bodyReader = bytes.NewReader(body)
+	}
+	request, err := http.NewRequest(method, chainlinkOracleService.getEndpointUrl(endpoint), bodyReader)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "Failed to create %v request to Oracle endpoint %v.", method, endpoint)
+	}
+	if body != nil {
+		request.Header.Set("Content-Type", "application/json")
+	}
+	if apiCredentials != nil {
+		request.Header.Set(apiKeyHeader, apiCredentials.AccessKey)
+		request.Header.Set(apiSecretHeader, apiCredentials.Secret)
+	}
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "An error occurred sending the request.")
+	}
+	return response, nil
+}
+
+/*
+	The client to send requests with and the API token credentials to add to them, if any. The client is nil when
+	there's neither a session nor API token credentials.
+ */
+func (chainlinkOracleService *ChainlinkOracleService) getAuthState() (*http.Client, *ApiCredentials) {
+	chainlinkOracleService.mutex.Lock()
+	defer chainlinkOracleService.mutex.Unlock()
+	if chainlinkOracleService.apiCredentials != nil {
+		apiCredentials := *chainlinkOracleService.apiCredentials
+		return &http.Client{Timeout: httpClientTimeout}, &apiCredentials
+	}
+	return chainlinkOracleService.clientWithSession, nil
+}
+
+func (chainlinkOracleService *ChainlinkOracleService) getEndpointUrl(endpoint string) string {
+	return fmt.Sprintf("http://%v:%v/%v", chainlinkOracleService.GetIPAddress(), chainlinkOracleService.GetOperatorPort(), endpoint)
+}
+
+/*
+	Gets the runs matching the query from every page of the runs endpoint, stopping at a short or empty page, at a page
+	with no runs that weren't already seen, or once the node's count is reached if it sends one. Pages are newest
+	first, so a run started mid-scan pushes older runs onto the next page; those show up twice and are deduplicated by
+	ID. WaitForRun polls again anyway, so a run that's missed in one scan gets picked up in the next.
+ */
+func (chainlinkOracleService *ChainlinkOracleService) getAllRunPages(query url.Values) ([]Run, error) {
+	runs := []Run{}
+	seenRunIds := map[string]bool{}
+	query.Set(pageSizeQueryParam, strconv.Itoa(runsPageSize))
+	for page := 1; page <= maxRunsPages; page++ {
+		query.Set(pageQueryParam, strconv.Itoa(page))
+		response, err := chainlinkOracleService.sendAuthenticatedRequest(http.MethodGet, runsEndpoint + "?" + query.Encode(), nil)
+		if err != nil {
+			return nil, stacktrace.Propagate(err, "Failed to get page %v of runs.", page)
+		}
+		runsResponse := new(RunsResponse)
+		if err := parseApiResponse(response, runsResponse); err != nil {
+			return nil, stacktrace.Propagate(err, "Failed to parse page %v of runs into a struct.", page)
+		}
+		numNewRuns := 0
+		for _, run := range runsResponse.Data {
+			if seenRunIds[run.Attributes.Id] {
+				continue
 			}
-		  ],
-		  "tasks": [
-				{
-				  "type": "HttpGetWithUnrestrictedNetworkAccess"
-				},
-				{
-				  "type": "JsonParse"
-				},
-				{
-				  "type": "Multiply"
-				},
-				{
-				  "type": "EthInt256"
-				},
-				{
-				  "type": "EthTx"
-				}
-		  ]
-		}`, oracleContractAddress)
+			seenRunIds[run.Attributes.Id] = true
+			runs = append(runs, run)
+			numNewRuns++
+		}
+		// A node that leaves out the count sends 0, which mustn't cut the scan short
+		isCountReached := runsResponse.Meta.Count > 0 && len(runs) >= runsResponse.Meta.Count
+		// A page of runs already seen means the node is serving the same runs again, so later pages won't add any
+		if len(runsResponse.Data) < runsPageSize || isCountReached || numNewRuns == 0 {
+			return runs, nil
+		}
+	}
+	return nil, stacktrace.NewError("Runs still hadn't run out after %v pages of %v.", maxRunsPages, runsPageSize)
 }
 
 /*
-	Parses an HTTP response into the target struct, while also logging it as a string to help develop and debug.
+	The error messages of the tasks that failed in a run.
  */
-func parseAndLogResponse(resp *http.Response, targetStruct interface{}) error{
-	var teeBuf bytes.Buffer
-	tee := io.TeeReader(resp.Body, &teeBuf)
-	bodyBytes, err := ioutil.ReadAll(tee)
-	if err != nil {
-		return stacktrace.Propagate(err, "Error parsing Oracle response into bytes.")
+func getTaskRunErrors(run Run) []string {
+	taskRunErrors := []string{}
+	for taskIdx, taskRun := range run.Attributes.TaskRuns {
+		if taskRun.Result.ErrorMessage != nil {
+			taskRunErrors = append(taskRunErrors, fmt.Sprintf("task %v (%v) failed with '%v'", taskIdx, taskRun.Id, *taskRun.Result.ErrorMessage))
+		}
+	}
+	if len(taskRunErrors) == 0 {
+		taskRunErrors = append(taskRunErrors, "no task reported an error")
 	}
-	bodyString := string(bodyBytes)
-	logrus.Debugf("Response from Oracle: %v", bodyString)
+	return taskRunErrors
+}
 
+/*
+	Reads and closes the body of an operator API response, logging it to help develop and debug. Non-2xx responses
+	give an OracleApiError.
+ */
+func readApiResponse(response *http.Response) ([]byte, error) {
+	defer response.Body.Close()
+	bodyBytes, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "Error parsing Oracle response into bytes.")
+	}
+	logrus.Debugf("Response from Oracle: %v", string(bodyBytes))
+	if response.StatusCode < http.StatusOK || response.StatusCode >= http.StatusMultipleChoices {
+		return nil, newOracleApiError(response, bodyBytes)
+	}
+	return bodyBytes, nil
+}
 
-	err = json.NewDecoder(&teeBuf).Decode(targetStruct)
+/*
+	Reads an operator API response with readApiResponse and deserializes its body into the target struct.
+ */
+func parseApiResponse(response *http.Response, targetStruct interface{}) error {
+	bodyBytes, err := readApiResponse(response)
 	if err != nil {
+		return stacktrace.Propagate(err, "The Oracle didn't answer with success.")
+	}
+	if err := json.Unmarshal(bodyBytes, targetStruct); err != nil {
 		return stacktrace.Propagate(err, "Error parsing Oracle response into a struct.")
 	}
 	logrus.Debugf("Response from Chainlink Oracle: %+v", targetStruct)
 	return nil
-}
\ No newline at end of file
+}
+
+func newOracleApiError(response *http.Response, bodyBytes []byte) *OracleApiError {
+	apiError := &OracleApiError{
+		StatusCode: response.StatusCode,
+		Details:    []string{},
+	}
+	if response.Request != nil {
+		apiError.Method = response.Request.Method
+		apiError.Endpoint = strings.TrimPrefix(response.Request.URL.Path, "/")
+	}
+	errorsResponse := new(jsonApiErrorsResponse)
+	if err := json.Unmarshal(bodyBytes, errorsResponse); err != nil || len(errorsResponse.Errors) == 0 {
+		apiError.Details = append(apiError.Details, strings.TrimSpace(string(bodyBytes)))
+		return apiError
+	}
+	for _, errorObject := range errorsResponse.Errors {
+		apiError.Details = append(apiError.Details, errorObject.Detail)
+	}
+	return apiError
+}
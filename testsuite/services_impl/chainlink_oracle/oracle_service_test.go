@@ -0,0 +1,363 @@
+package chainlink_oracle_test
+
+import (
+	"github.com/kurtosistech/chainlink-testing/testsuite/services_impl/chainlink_oracle"
+	"github.com/kurtosistech/chainlink-testing/testsuite/services_impl/chainlink_oracle/oracletest"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+const (
+	testOracleContractAddress = "0x1234567890123456789012345678901234567890"
+	testConsumerContractAddress = "0x0987654321098765432109876543210987654321"
+	testPriceFeedUrl = "http://price-feed-server:8080/"
+	testRequestTxHash = "0x1111111111111111111111111111111111111111111111111111111111111111"
+	testFulfillmentTxHash = "0x2222222222222222222222222222222222222222222222222222222222222222"
+
+	// More than fit on one page of the runs endpoint, so paging gets exercised
+	numPagedRuns = 230
+)
+
+func TestWaitForRun(t *testing.T) {
+	testCases := []struct {
+		name string
+		transitions []oracletest.RunTransition
+		maxNumPolls int
+		isErrorExpected bool
+		// Expected in the error when there is one
+		expectedErrorFragment string
+	}{
+		{
+			name:        "completes",
+			transitions: oracletest.CompleteRunAfterPolls(2, testFulfillmentTxHash),
+			maxNumPolls: 5,
+		},
+		{
+			name: "errors",
+			transitions: []oracletest.RunTransition{
+				{AfterNumPolls: 1, Status: chainlink_oracle.RunStatusErrored, ErrorMessage: "out of gas"},
+			},
+			maxNumPolls:           5,
+			isErrorExpected:       true,
+			expectedErrorFragment: "out of gas",
+		},
+		{
+			name: "still pending after the last poll",
+			transitions: []oracletest.RunTransition{
+				{AfterNumPolls: 1, Status: chainlink_oracle.RunStatusPendingIncomingConfirmations},
+				{AfterNumPolls: 10, Status: chainlink_oracle.RunStatusCompleted},
+			},
+			maxNumPolls:     2,
+			isErrorExpected: true,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			mock := oracletest.NewMockChainlinkServer()
+			defer mock.Close()
+			oracleService := mock.NewOracleService()
+			jobId := createJob(t, oracleService, chainlink_oracle.NewRunLogJobSpec(testOracleContractAddress))
+			// A finished run of another job for the same request, which mustn't be mistaken for this job's
+			otherJobId := createJob(t, oracleService, chainlink_oracle.NewRunLogJobSpec(testOracleContractAddress))
+			runRequest := chainlink_oracle.RunRequest{TxHash: testRequestTxHash}
+			if _, err := mock.AddRun(otherJobId, runRequest, chainlink_oracle.RunStatusCompleted, nil); err != nil {
+				t.Fatalf("Adding a run of the other job failed: %v", err)
+			}
+			runId, err := mock.AddRun(jobId, runRequest, chainlink_oracle.RunStatusInProgress, testCase.transitions)
+			if err != nil {
+				t.Fatalf("Adding the run failed: %v", err)
+			}
+
+			isRequestRun := func(run chainlink_oracle.Run) bool {
+				return run.Attributes.RunRequest.TxHash == testRequestTxHash
+			}
+			run, err := oracleService.WaitForRun(jobId, isRequestRun, testCase.maxNumPolls)
+			if testCase.isErrorExpected {
+				if err == nil {
+					t.Fatalf("Expected waiting for the run to fail, but got run %+v", run)
+				}
+				if !strings.Contains(err.Error(), testCase.expectedErrorFragment) {
+					t.Errorf("Expected the error to mention '%v', but it was: %v", testCase.expectedErrorFragment, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected the run to complete, but got an error: %v", err)
+			}
+			if run.Attributes.Id != runId || run.Attributes.Status != chainlink_oracle.RunStatusCompleted {
+				t.Errorf("Expected completed run %v, but got run %v with status %v", runId, run.Attributes.Id, run.Attributes.Status)
+			}
+			taskRuns := run.Attributes.TaskRuns
+			if len(taskRuns) == 0 || taskRuns[len(taskRuns) - 1].Result.Data.Result != testFulfillmentTxHash {
+				t.Errorf("Expected the final task run to have sent fulfillment transaction %v, but the task runs were %+v",
+					testFulfillmentTxHash, taskRuns)
+			}
+		})
+	}
+}
+
+func TestGetRunsForJobPages(t *testing.T) {
+	mock := oracletest.NewMockChainlinkServer()
+	defer mock.Close()
+	oracleService := mock.NewOracleService()
+	jobIds := []string{
+		createJob(t, oracleService, chainlink_oracle.NewRunLogJobSpec(testOracleContractAddress)),
+		createJob(t, oracleService, chainlink_oracle.NewRunLogJobSpec(testOracleContractAddress)),
+	}
+	numRunsByJobId := map[string]int{
+		jobIds[0]: numPagedRuns,
+		jobIds[1]: 3,
+	}
+	for jobId, numRuns := range numRunsByJobId {
+		for runIdx := 0; runIdx < numRuns; runIdx++ {
+			if _, err := mock.AddRun(jobId, chainlink_oracle.RunRequest{}, chainlink_oracle.RunStatusInProgress, nil); err != nil {
+				t.Fatalf("Adding run %v of job %v failed: %v", runIdx, jobId, err)
+			}
+		}
+	}
+
+	for jobId, expectedNumRuns := range numRunsByJobId {
+		runs, err := oracleService.GetRunsForJob(jobId)
+		if err != nil {
+			t.Fatalf("Getting the runs of job %v failed: %v", jobId, err)
+		}
+		runIds := map[string]bool{}
+		for _, run := range runs {
+			if run.Attributes.JobId != jobId {
+				t.Errorf("Got run %v of job %v among the runs of job %v", run.Attributes.Id, run.Attributes.JobId, jobId)
+			}
+			runIds[run.Attributes.Id] = true
+		}
+		if len(runs) != expectedNumRuns || len(runIds) != expectedNumRuns {
+			t.Errorf("Expected %v distinct runs of job %v, but got %v runs with %v distinct IDs", expectedNumRuns, jobId, len(runs), len(runIds))
+		}
+	}
+	allRuns, err := oracleService.GetRuns()
+	if err != nil {
+		t.Fatalf("Getting every run failed: %v", err)
+	}
+	if expectedNumRuns := numPagedRuns + 3; len(allRuns) != expectedNumRuns {
+		t.Errorf("Expected %v runs in all, but got %v", expectedNumRuns, len(allRuns))
+	}
+}
+
+func TestGetRunsStopsWhenPagesRepeat(t *testing.T) {
+	mock := oracletest.NewMockChainlinkServer()
+	defer mock.Close()
+	oracleService := mock.NewOracleService()
+	jobId := createJob(t, oracleService, chainlink_oracle.NewRunLogJobSpec(testOracleContractAddress))
+	for runIdx := 0; runIdx < numPagedRuns; runIdx++ {
+		if _, err := mock.AddRun(jobId, chainlink_oracle.RunRequest{}, chainlink_oracle.RunStatusInProgress, nil); err != nil {
+			t.Fatalf("Adding run %v failed: %v", runIdx, err)
+		}
+	}
+	// Every page is then the same full page, and the count is never reached
+	mock.SetIgnoringRunsPage(true)
+
+	runs, err := oracleService.GetRuns()
+	if err != nil {
+		t.Fatalf("Getting the runs failed: %v", err)
+	}
+	if len(runs) == 0 || len(runs) >= numPagedRuns {
+		t.Fatalf("Expected only the runs of the one page served, but got %v of %v runs", len(runs), numPagedRuns)
+	}
+}
+
+func TestAuthentication(t *testing.T) {
+	testCases := []struct {
+		name string
+		useApiToken bool
+	}{
+		{name: "session", useApiToken: false},
+		{name: "API token", useApiToken: true},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			mock := oracletest.NewMockChainlinkServer()
+			defer mock.Close()
+			oracleService := mock.NewOracleService()
+			if isLoggedIn, err := oracleService.IsLoggedIn(); err != nil || isLoggedIn {
+				t.Fatalf("Expected a new Oracle service not to be logged in, but got %v and error %v", isLoggedIn, err)
+			}
+			if testCase.useApiToken {
+				apiCredentials, err := oracleService.CreateApiToken()
+				if err != nil {
+					t.Fatalf("Creating an API token failed: %v", err)
+				}
+				oracleService = mock.NewOracleService()
+				oracleService.SetApiCredentials(apiCredentials)
+			}
+			createJob(t, oracleService, chainlink_oracle.NewRunLogJobSpec(testOracleContractAddress))
+
+			// Sessions are forgotten when the node restarts, which requests should recover from by logging in again
+			mock.ExpireSessions()
+			if isLoggedIn, err := oracleService.IsLoggedIn(); err != nil || isLoggedIn != testCase.useApiToken {
+				t.Errorf("Expected being logged in after the sessions expired to be %v, but got %v and error %v",
+					testCase.useApiToken, isLoggedIn, err)
+			}
+			createJob(t, oracleService, chainlink_oracle.NewRunLogJobSpec(testOracleContractAddress))
+			if numJobs := len(mock.GetJobIds()); numJobs != 2 {
+				t.Errorf("Expected the mock to have 2 jobs, but it had %v", numJobs)
+			}
+		})
+	}
+}
+
+func TestIsLoggedInErrorsWhenTheOracleDoesntSay(t *testing.T) {
+	testCases := []struct {
+		name string
+		breakOracle func(mock *oracletest.MockChainlinkServer)
+	}{
+		{
+			name: "server error",
+			breakOracle: func(mock *oracletest.MockChainlinkServer) {
+				mock.SetConfigFailing(true)
+			},
+		},
+		{
+			name: "unreachable",
+			breakOracle: func(mock *oracletest.MockChainlinkServer) {
+				mock.Close()
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			mock := oracletest.NewMockChainlinkServer()
+			defer mock.Close()
+			oracleService := mock.NewOracleService()
+			if _, err := oracleService.StartSession(); err != nil {
+				t.Fatalf("Starting a session failed: %v", err)
+			}
+			if isLoggedIn, err := oracleService.IsLoggedIn(); err != nil || !isLoggedIn {
+				t.Fatalf("Expected to be logged in after starting a session, but got %v and error %v", isLoggedIn, err)
+			}
+			testCase.breakOracle(mock)
+			if isLoggedIn, err := oracleService.IsLoggedIn(); err == nil {
+				t.Fatalf("Expected an error checking the login status, but got %v", isLoggedIn)
+			}
+		})
+	}
+}
+
+func TestEthKeyRoundTrip(t *testing.T) {
+	mock := oracletest.NewMockChainlinkServer()
+	defer mock.Close()
+	oracleService := mock.NewOracleService()
+	ethKey, err := oracleService.CreateEthKey()
+	if err != nil {
+		t.Fatalf("Creating an ethereum key failed: %v", err)
+	}
+	address := ethKey.Attributes.Address
+	keystoreJson, err := oracleService.ExportEthKey(address, "export-password")
+	if err != nil {
+		t.Fatalf("Exporting key %v failed: %v", address, err)
+	}
+	if err := oracleService.DeleteEthKey(address); err != nil {
+		t.Fatalf("Deleting key %v failed: %v", address, err)
+	}
+
+	_, err = oracleService.ImportEthKey(keystoreJson, "wrong-password")
+	if apiError, ok := chainlink_oracle.GetOracleApiError(err); !ok || apiError.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected importing with the wrong password to be rejected with status %v, but got error %v", http.StatusBadRequest, err)
+	}
+	importedKey, err := oracleService.ImportEthKey(keystoreJson, "export-password")
+	if err != nil {
+		t.Fatalf("Importing key %v failed: %v", address, err)
+	}
+	if !strings.EqualFold(importedKey.Attributes.Address, address) {
+		t.Errorf("Expected the imported key to have address %v, but it had %v", address, importedKey.Attributes.Address)
+	}
+	ethKeys, err := oracleService.GetEthAccounts()
+	if err != nil {
+		t.Fatalf("Getting the ethereum keys failed: %v", err)
+	}
+	if len(ethKeys) != 1 || !strings.EqualFold(ethKeys[0].Attributes.Address, address) {
+		t.Errorf("Expected only key %v after the round trip, but got %+v", address, ethKeys)
+	}
+}
+
+func TestCreateAndTriggerJobs(t *testing.T) {
+	testCases := []struct {
+		name string
+		jobSpec chainlink_oracle.JobSpec
+		// Status the node rejects creating the job with, or 0 if it accepts it
+		expectedCreateStatusCode int
+		// Status the node rejects running the job over the API with, or 0 if it runs it
+		expectedTriggerStatusCode int
+	}{
+		{
+			name:    "web job",
+			jobSpec: chainlink_oracle.NewWebJobSpec(testPriceFeedUrl, testConsumerContractAddress),
+		},
+		{
+			name:                      "run log job",
+			jobSpec:                   chainlink_oracle.NewRunLogJobSpec(testOracleContractAddress),
+			expectedTriggerStatusCode: http.StatusForbidden,
+		},
+		{
+			name: "job without tasks",
+			jobSpec: chainlink_oracle.JobSpec{
+				Initiators: []chainlink_oracle.InitiatorSpec{{Type: chainlink_oracle.WebInitiatorType}},
+				Tasks:      []chainlink_oracle.TaskSpec{},
+			},
+			expectedCreateStatusCode: http.StatusUnprocessableEntity,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			mock := oracletest.NewMockChainlinkServer()
+			defer mock.Close()
+			oracleService := mock.NewOracleService()
+
+			jobId, err := oracleService.CreateJob(testCase.jobSpec)
+			if testCase.expectedCreateStatusCode != 0 {
+				assertOracleApiError(t, err, testCase.expectedCreateStatusCode)
+				return
+			}
+			if err != nil {
+				t.Fatalf("Creating the job failed: %v", err)
+			}
+			run, err := oracleService.TriggerJobRun(jobId)
+			if testCase.expectedTriggerStatusCode != 0 {
+				assertOracleApiError(t, err, testCase.expectedTriggerStatusCode)
+				return
+			}
+			if err != nil {
+				t.Fatalf("Triggering a run of the job failed: %v", err)
+			}
+			// Runs started over the API complete the first time they're polled
+			polledRun, err := oracleService.GetRun(run.Attributes.Id)
+			if err != nil {
+				t.Fatalf("Getting run %v failed: %v", run.Attributes.Id, err)
+			}
+			if polledRun.Attributes.JobId != jobId || polledRun.Attributes.Status != chainlink_oracle.RunStatusCompleted {
+				t.Errorf("Expected a completed run of job %v, but got a run of job %v with status %v",
+					jobId, polledRun.Attributes.JobId, polledRun.Attributes.Status)
+			}
+		})
+	}
+}
+
+// ==========================================================================================
+//								Helper methods
+// ==========================================================================================
+
+func createJob(t *testing.T, oracleService *chainlink_oracle.ChainlinkOracleService, jobSpec chainlink_oracle.JobSpec) string {
+	jobId, err := oracleService.CreateJob(jobSpec)
+	if err != nil {
+		t.Fatalf("Creating a job failed: %v", err)
+	}
+	return jobId
+}
+
+func assertOracleApiError(t *testing.T, err error, expectedStatusCode int) {
+	apiError, ok := chainlink_oracle.GetOracleApiError(err)
+	if !ok {
+		t.Fatalf("Expected the Oracle to answer with status %v, but got error %v", expectedStatusCode, err)
+	}
+	if apiError.StatusCode != expectedStatusCode || len(apiError.Details) == 0 {
+		t.Errorf("Expected the Oracle to answer with status %v and the reason why, but got %+v", expectedStatusCode, apiError)
+	}
+}
@@ -0,0 +1,864 @@
+package oracletest
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/kurtosistech/chainlink-testing/testsuite/services_impl/chainlink_oracle"
+	"github.com/kurtosistech/chainlink-testing/testsuite/services_impl/testutil"
+	"github.com/palantir/stacktrace"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	mockServiceId = "mock-chainlink-oracle"
+
+	// Routes of the node's operator API, spelled out here rather than shared with the Oracle service so the mock
+	// catches the service calling the wrong one
+	sessionsEndpoint = "sessions"
+	apiTokenEndpoint = "v2/user/token"
+	configEndpoint = "v2/config"
+	specsEndpoint = "v2/specs"
+	specRunsPathSegment = "runs"
+	ethAccountsEndpoint = "v2/keys/eth"
+	ethKeysImportPathSegment = "import"
+	ethKeysExportPathSegment = "export"
+	oldPasswordQueryParam = "oldpassword"
+	newPasswordQueryParam = "newpassword"
+	runsEndpoint = "v2/runs"
+	pageQueryParam = "page"
+	pageSizeQueryParam = "size"
+	jobSpecIdQueryParam = "jobSpecId"
+	apiKeyHeader = "X-API-KEY"
+	apiSecretHeader = "X-API-SECRET"
+
+	// Value the config endpoint reports, matching what the Oracle container is started with
+	mockMinIncomingConfirmations = 0
+
+	// Name of the cookie the Chainlink node keeps the operator's session in
+	sessionCookieName = "clsession"
+
+	jsonApiContentType = "application/vnd.api+json"
+
+	specsResourceType = "specs"
+	ethKeysResourceType = "eTHKeys"
+	runsResourceType = "runs"
+	// Page size the node uses when a runs request doesn't give one
+	mockDefaultRunsPageSize = 25
+	sessionResourceType = "session"
+	apiTokenResourceType = "auth_tokens"
+	configResourceType = "configWhitelists"
+
+	taskStatusUnstarted = "unstarted"
+)
+
+/*
+	A change in a scripted run's state, applied once the runs endpoint has been polled the given number of times since
+	the run's previous transition (or since it was added, for the first one).
+ */
+type RunTransition struct {
+	AfterNumPolls int
+
+	Status string
+
+	// When non-empty, set as the result of the run's final task, like the EthTx task does when it sends the
+	// fulfillment transaction
+	FulfillmentTxHash string
+
+	// When non-empty, set as the error of the run's final task
+	ErrorMessage string
+}
+
+/*
+	A fake Chainlink node serving the operator API endpoints the testsuite uses (sessions, job specs, ethereum keys and
+	runs) with JSON:API payloads like the real node's, so chainlink_oracle.ChainlinkOracleService can be developed without the Chainlink
+	image. Runs are added by the test and move through scripted state transitions as they're polled.
+ */
+type MockChainlinkServer struct {
+	server *httptest.Server
+
+	// Mutex protecting all the state below
+	mutex *sync.Mutex
+	sessionTokens map[string]bool
+	// API token access key -> secret
+	apiTokens map[string]string
+	// Job ID -> the spec posted for it, in the order they were posted
+	jobSpecs map[string]json.RawMessage
+	jobIds []string
+	ethKeys []chainlink_oracle.EthereumKeyAttributes
+	// Lowercase address -> password the key was last exported with, which importing it again must give
+	exportedKeyPasswords map[string]string
+	// Newest first, like the real node lists them
+	runs []*mockRun
+	// Transitions given to runs started through the web initiator endpoint
+	triggeredRunTransitions []RunTransition
+	// Whether the runs endpoint serves the first page whatever page is asked for, like a node that ignores the param
+	isIgnoringRunsPage bool
+	// Whether the config endpoint answers with a server error, like a node whose database is down
+	isConfigFailing bool
+	nextId int
+}
+
+type mockRun struct {
+	run chainlink_oracle.Run
+	pendingTransitions []RunTransition
+	numPollsSinceTransition int
+}
+
+// The parts of a JSON keystore the mock reads and writes; the real node also encrypts the key into a crypto section
+type mockKeystore struct {
+	Address string `json:"address"`
+	Version int `json:"version"`
+}
+
+type mockSessionRequest struct {
+	Email string `json:"email"`
+	Password string `json:"password"`
+}
+
+type mockApiTokenRequest struct {
+	Password string `json:"password"`
+}
+
+type jsonApiResource struct {
+	Type string `json:"type"`
+	Id string `json:"id"`
+	Attributes interface{} `json:"attributes"`
+}
+
+type jsonApiDocument struct {
+	Data interface{} `json:"data"`
+	Meta map[string]interface{} `json:"meta,omitempty"`
+}
+
+type jsonApiError struct {
+	Detail string `json:"detail"`
+}
+
+type jsonApiErrorDocument struct {
+	Errors []jsonApiError `json:"errors"`
+}
+
+/*
+	Starts the server on a local port, which keeps serving until Close is called.
+ */
+func NewMockChainlinkServer() *MockChainlinkServer {
+	mock := &MockChainlinkServer{
+		mutex:         &sync.Mutex{},
+		sessionTokens: map[string]bool{},
+		apiTokens:     map[string]string{},
+		jobSpecs:      map[string]json.RawMessage{},
+		jobIds:        []string{},
+		ethKeys:       []chainlink_oracle.EthereumKeyAttributes{},
+		exportedKeyPasswords: map[string]string{},
+		runs:          []*mockRun{},
+		triggeredRunTransitions: []RunTransition{
+			{AfterNumPolls: 1, Status: chainlink_oracle.RunStatusCompleted},
+		},
+		nextId:        1,
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/" + sessionsEndpoint, mock.handleSessions)
+	mux.HandleFunc("/" + specsEndpoint, mock.requireSession(mock.handleSpecs))
+	mux.HandleFunc("/" + specsEndpoint + "/", mock.requireSession(mock.handleSpec))
+	mux.HandleFunc("/" + ethAccountsEndpoint, mock.requireSession(mock.handleEthKeys))
+	mux.HandleFunc("/" + ethAccountsEndpoint + "/", mock.requireSession(mock.handleEthKey))
+	mux.HandleFunc("/" + runsEndpoint, mock.requireSession(mock.handleRuns))
+	mux.HandleFunc("/" + runsEndpoint + "/", mock.requireSession(mock.handleRun))
+	mux.HandleFunc("/" + apiTokenEndpoint, mock.requireSession(mock.handleApiToken))
+	mux.HandleFunc("/" + configEndpoint, mock.requireSession(mock.handleConfig))
+	mock.server = httptest.NewServer(mux)
+	return mock
+}
+
+func (mock *MockChainlinkServer) Close() {
+	mock.server.Close()
+}
+
+func (mock *MockChainlinkServer) GetIPAddress() string {
+	host, _, _ := net.SplitHostPort(mock.server.Listener.Addr().String())
+	return host
+}
+
+func (mock *MockChainlinkServer) GetPort() int {
+	_, portStr, _ := net.SplitHostPort(mock.server.Listener.Addr().String())
+	port, _ := strconv.Atoi(portStr)
+	return port
+}
+
+/*
+	An Oracle service talking to this server, as the testsuite's Oracle service talks to a real node.
+ */
+func (mock *MockChainlinkServer) NewOracleService() *chainlink_oracle.ChainlinkOracleService {
+	serviceCtx := testutil.NewFakeServiceContext(mockServiceId, mock.GetIPAddress())
+	return chainlink_oracle.NewChainlinkOracleService(serviceCtx, mock.GetPort())
+}
+
+func (mock *MockChainlinkServer) AddEthKey(address string, ethBalance string, linkBalance string) {
+	mock.mutex.Lock()
+	defer mock.mutex.Unlock()
+	mock.ethKeys = append(mock.ethKeys, chainlink_oracle.EthereumKeyAttributes{
+		Address:     address,
+		EthBalance:  ethBalance,
+		LinkBalance: linkBalance,
+	})
+}
+
+func (mock *MockChainlinkServer) SetEthBalance(address string, ethBalance string) error {
+	mock.mutex.Lock()
+	defer mock.mutex.Unlock()
+	for idx := range mock.ethKeys {
+		if strings.EqualFold(mock.ethKeys[idx].Address, address) {
+			mock.ethKeys[idx].EthBalance = ethBalance
+			return nil
+		}
+	}
+	return stacktrace.NewError("The mock Chainlink node has no ethereum key with address '%v'", address)
+}
+
+/*
+	IDs of the jobs created through the specs endpoint, in the order they were created.
+ */
+func (mock *MockChainlinkServer) GetJobIds() []string {
+	mock.mutex.Lock()
+	defer mock.mutex.Unlock()
+	return append([]string{}, mock.jobIds...)
+}
+
+func (mock *MockChainlinkServer) GetJobSpec(jobId string) (json.RawMessage, bool) {
+	mock.mutex.Lock()
+	defer mock.mutex.Unlock()
+	jobSpec, found := mock.jobSpecs[jobId]
+	return jobSpec, found
+}
+
+/*
+	Adds a run of the given job, as the node would on seeing an on-chain request, returning the run's ID. The run starts
+	in the given status and then goes through the transitions in order as the runs endpoint gets polled.
+ */
+func (mock *MockChainlinkServer) AddRun(jobId string, runRequest chainlink_oracle.RunRequest, initialStatus string, transitions []RunTransition) (string, error) {
+	mock.mutex.Lock()
+	defer mock.mutex.Unlock()
+	run, err := mock.addRun(jobId, runRequest, initialStatus, transitions)
+	if err != nil {
+		return "", stacktrace.Propagate(err, "An error occurred adding a run of job '%v'", jobId)
+	}
+	return run.run.Attributes.Id, nil
+}
+
+/*
+	Sets the transitions that runs started through a job's web initiator go through, which by default complete on the
+	first poll after they start.
+ */
+func (mock *MockChainlinkServer) SetTriggeredRunTransitions(transitions []RunTransition) {
+	mock.mutex.Lock()
+	defer mock.mutex.Unlock()
+	mock.triggeredRunTransitions = append([]RunTransition{}, transitions...)
+}
+
+/*
+	Makes the runs endpoint serve the first page whichever page is asked for, or page properly again.
+ */
+func (mock *MockChainlinkServer) SetIgnoringRunsPage(isIgnoringRunsPage bool) {
+	mock.mutex.Lock()
+	defer mock.mutex.Unlock()
+	mock.isIgnoringRunsPage = isIgnoringRunsPage
+}
+
+/*
+	Makes the config endpoint answer with a server error, or answer properly again.
+ */
+func (mock *MockChainlinkServer) SetConfigFailing(isConfigFailing bool) {
+	mock.mutex.Lock()
+	defer mock.mutex.Unlock()
+	mock.isConfigFailing = isConfigFailing
+}
+
+func (mock *MockChainlinkServer) GetRun(runId string) (chainlink_oracle.Run, bool) {
+	mock.mutex.Lock()
+	defer mock.mutex.Unlock()
+	for _, run := range mock.runs {
+		if run.run.Attributes.Id == runId {
+			return copyRun(run.run), true
+		}
+	}
+	return chainlink_oracle.Run{}, false
+}
+
+/*
+	Forgets every session, so clients have to log in again, as when the node restarts. API tokens stay valid.
+ */
+func (mock *MockChainlinkServer) ExpireSessions() {
+	mock.mutex.Lock()
+	defer mock.mutex.Unlock()
+	mock.sessionTokens = map[string]bool{}
+}
+
+/*
+	Transitions for a run that waits for the given number of polls and then completes, having sent the given fulfillment
+	transaction.
+ */
+func CompleteRunAfterPolls(numPolls int, fulfillmentTxHash string) []RunTransition {
+	return []RunTransition{
+		{
+			AfterNumPolls:     numPolls,
+			Status:            chainlink_oracle.RunStatusCompleted,
+			FulfillmentTxHash: fulfillmentTxHash,
+		},
+	}
+}
+
+// ===========================================================================================
+//                                  Request handlers
+// ===========================================================================================
+
+func (mock *MockChainlinkServer) handleSessions(writer http.ResponseWriter, request *http.Request) {
+	if request.Method != http.MethodPost {
+		writeJsonApiError(writer, http.StatusMethodNotAllowed, fmt.Sprintf("Method %v isn't supported", request.Method))
+		return
+	}
+	var credentials mockSessionRequest
+	if err := json.NewDecoder(request.Body).Decode(&credentials); err != nil {
+		writeJsonApiError(writer, http.StatusBadRequest, fmt.Sprintf("Couldn't parse session request: %v", err))
+		return
+	}
+	if credentials.Email != chainlink_oracle.OracleEmail || credentials.Password != chainlink_oracle.OraclePassword {
+		writeJsonApiError(writer, http.StatusUnauthorized, "Invalid email or password")
+		return
+	}
+
+	mock.mutex.Lock()
+	sessionToken := mock.getNextId()
+	mock.sessionTokens[sessionToken] = true
+	mock.mutex.Unlock()
+	http.SetCookie(writer, &http.Cookie{
+		Name:     sessionCookieName,
+		Value:    sessionToken,
+		Path:     "/",
+		HttpOnly: true,
+	})
+	writeJsonApiDocument(writer, http.StatusOK, jsonApiDocument{
+		Data: jsonApiResource{
+			Type:       sessionResourceType,
+			Id:         sessionResourceType,
+			Attributes: map[string]bool{"authenticated": true},
+		},
+	})
+}
+
+func (mock *MockChainlinkServer) handleApiToken(writer http.ResponseWriter, request *http.Request) {
+	if request.Method != http.MethodPost {
+		writeJsonApiError(writer, http.StatusMethodNotAllowed, fmt.Sprintf("Method %v isn't supported", request.Method))
+		return
+	}
+	var tokenRequest mockApiTokenRequest
+	if err := json.NewDecoder(request.Body).Decode(&tokenRequest); err != nil {
+		writeJsonApiError(writer, http.StatusBadRequest, fmt.Sprintf("Couldn't parse API token request: %v", err))
+		return
+	}
+	if tokenRequest.Password != chainlink_oracle.OraclePassword {
+		writeJsonApiError(writer, http.StatusUnauthorized, "Incorrect password")
+		return
+	}
+	mock.mutex.Lock()
+	apiCredentials := chainlink_oracle.ApiCredentials{
+		AccessKey: mock.getNextId(),
+		Secret:    mock.getNextId(),
+	}
+	mock.apiTokens[apiCredentials.AccessKey] = apiCredentials.Secret
+	mock.mutex.Unlock()
+	writeJsonApiDocument(writer, http.StatusCreated, jsonApiDocument{
+		Data: jsonApiResource{
+			Type:       apiTokenResourceType,
+			Id:         apiCredentials.AccessKey,
+			Attributes: apiCredentials,
+		},
+	})
+}
+
+func (mock *MockChainlinkServer) handleConfig(writer http.ResponseWriter, request *http.Request) {
+	mock.mutex.Lock()
+	isConfigFailing := mock.isConfigFailing
+	mock.mutex.Unlock()
+	if isConfigFailing {
+		writeJsonApiError(writer, http.StatusInternalServerError, "the config couldn't be loaded")
+		return
+	}
+	writeJsonApiDocument(writer, http.StatusOK, jsonApiDocument{
+		Data: jsonApiResource{
+			Type:       configResourceType,
+			Id:         "",
+			Attributes: map[string]string{"minIncomingConfirmations": strconv.Itoa(mockMinIncomingConfirmations)},
+		},
+	})
+}
+
+func (mock *MockChainlinkServer) handleSpecs(writer http.ResponseWriter, request *http.Request) {
+	switch request.Method {
+	case http.MethodPost:
+		var jobSpec chainlink_oracle.JobSpec
+		var rawJobSpec json.RawMessage
+		if err := json.NewDecoder(request.Body).Decode(&rawJobSpec); err != nil {
+			writeJsonApiError(writer, http.StatusUnprocessableEntity, fmt.Sprintf("Couldn't parse job spec: %v", err))
+			return
+		}
+		if err := json.Unmarshal(rawJobSpec, &jobSpec); err != nil || len(jobSpec.Tasks) == 0 {
+			writeJsonApiError(writer, http.StatusUnprocessableEntity, "Job spec must have at least one task")
+			return
+		}
+		mock.mutex.Lock()
+		jobId := mock.getNextId()
+		mock.jobSpecs[jobId] = rawJobSpec
+		mock.jobIds = append(mock.jobIds, jobId)
+		mock.mutex.Unlock()
+		writeJsonApiDocument(writer, http.StatusOK, jsonApiDocument{
+			Data: getSpecResource(jobId, rawJobSpec),
+		})
+	case http.MethodGet:
+		mock.mutex.Lock()
+		specResources := []jsonApiResource{}
+		for _, jobId := range mock.jobIds {
+			specResources = append(specResources, getSpecResource(jobId, mock.jobSpecs[jobId]))
+		}
+		mock.mutex.Unlock()
+		writeJsonApiDocument(writer, http.StatusOK, jsonApiDocument{
+			Data: specResources,
+			Meta: map[string]interface{}{"count": len(specResources)},
+		})
+	default:
+		writeJsonApiError(writer, http.StatusMethodNotAllowed, fmt.Sprintf("Method %v isn't supported", request.Method))
+	}
+}
+
+/*
+	Archives a job when its spec is deleted, and starts a run of a job with a web initiator when its runs path is
+	posted to, as the real node does.
+ */
+func (mock *MockChainlinkServer) handleSpec(writer http.ResponseWriter, request *http.Request) {
+	pathSegments := strings.Split(strings.TrimPrefix(request.URL.Path, "/" + specsEndpoint + "/"), "/")
+	switch {
+	case len(pathSegments) == 1 && request.Method == http.MethodDelete:
+		mock.archiveJob(writer, pathSegments[0])
+	case len(pathSegments) == 2 && pathSegments[1] == specRunsPathSegment && request.Method == http.MethodPost:
+		mock.triggerJobRun(writer, pathSegments[0])
+	default:
+		writeJsonApiError(writer, http.StatusNotFound, fmt.Sprintf("No route for %v %v", request.Method, request.URL.Path))
+	}
+}
+
+/*
+	Forgets the job, so it gets no new runs, while keeping the runs it already has.
+ */
+func (mock *MockChainlinkServer) archiveJob(writer http.ResponseWriter, jobId string) {
+	mock.mutex.Lock()
+	defer mock.mutex.Unlock()
+	if _, found := mock.jobSpecs[jobId]; !found {
+		writeJsonApiError(writer, http.StatusNotFound, fmt.Sprintf("Job %v not found", jobId))
+		return
+	}
+	delete(mock.jobSpecs, jobId)
+	remainingJobIds := []string{}
+	for _, otherJobId := range mock.jobIds {
+		if otherJobId != jobId {
+			remainingJobIds = append(remainingJobIds, otherJobId)
+		}
+	}
+	mock.jobIds = remainingJobIds
+	writer.WriteHeader(http.StatusNoContent)
+}
+
+func (mock *MockChainlinkServer) triggerJobRun(writer http.ResponseWriter, jobId string) {
+	mock.mutex.Lock()
+	defer mock.mutex.Unlock()
+	jobSpecBytes, found := mock.jobSpecs[jobId]
+	if !found {
+		writeJsonApiError(writer, http.StatusNotFound, fmt.Sprintf("Job %v not found", jobId))
+		return
+	}
+	var jobSpec chainlink_oracle.JobSpec
+	if err := json.Unmarshal(jobSpecBytes, &jobSpec); err != nil || !jobSpec.HasInitiator(chainlink_oracle.WebInitiatorType) {
+		writeJsonApiError(writer, http.StatusForbidden, "Job not available on web API, recreate with web initiator")
+		return
+	}
+	run, err := mock.addRun(jobId, chainlink_oracle.RunRequest{}, chainlink_oracle.RunStatusInProgress, mock.triggeredRunTransitions)
+	if err != nil {
+		writeJsonApiError(writer, http.StatusInternalServerError, fmt.Sprintf("Couldn't start a run of job %v", jobId))
+		return
+	}
+	writeJsonApiDocument(writer, http.StatusOK, jsonApiDocument{Data: getRunResource(run)})
+}
+
+/*
+	Lists the keys, and generates a new one when posted to.
+ */
+func (mock *MockChainlinkServer) handleEthKeys(writer http.ResponseWriter, request *http.Request) {
+	mock.mutex.Lock()
+	defer mock.mutex.Unlock()
+	switch request.Method {
+	case http.MethodGet:
+		keyResources := []jsonApiResource{}
+		for _, ethKey := range mock.ethKeys {
+			keyResources = append(keyResources, getEthKeyResource(ethKey))
+		}
+		writeJsonApiDocument(writer, http.StatusOK, jsonApiDocument{Data: keyResources})
+	case http.MethodPost:
+		// Padded from the mock's 16 byte IDs to the 20 bytes of an address
+		ethKey := chainlink_oracle.EthereumKeyAttributes{
+			Address:     "0x00000000" + mock.getNextId(),
+			EthBalance:  "0",
+			LinkBalance: "0",
+		}
+		mock.ethKeys = append(mock.ethKeys, ethKey)
+		writeJsonApiDocument(writer, http.StatusCreated, jsonApiDocument{Data: getEthKeyResource(ethKey)})
+	default:
+		writeJsonApiError(writer, http.StatusMethodNotAllowed, fmt.Sprintf("Method %v isn't supported", request.Method))
+	}
+}
+
+/*
+	Imports a keystore posted to the import path, exports the key whose address follows the export path, and deletes
+	the key whose address the path ends with, as the real node does.
+ */
+func (mock *MockChainlinkServer) handleEthKey(writer http.ResponseWriter, request *http.Request) {
+	pathSegments := strings.Split(strings.TrimPrefix(request.URL.Path, "/" + ethAccountsEndpoint + "/"), "/")
+	switch {
+	case len(pathSegments) == 1 && pathSegments[0] == ethKeysImportPathSegment && request.Method == http.MethodPost:
+		mock.importEthKey(writer, request)
+	case len(pathSegments) == 2 && pathSegments[0] == ethKeysExportPathSegment && request.Method == http.MethodPost:
+		mock.exportEthKey(writer, pathSegments[1], request.URL.Query().Get(newPasswordQueryParam))
+	case len(pathSegments) == 1 && request.Method == http.MethodDelete:
+		mock.deleteEthKey(writer, pathSegments[0])
+	default:
+		writeJsonApiError(writer, http.StatusNotFound, fmt.Sprintf("No route for %v %v", request.Method, request.URL.Path))
+	}
+}
+
+func (mock *MockChainlinkServer) importEthKey(writer http.ResponseWriter, request *http.Request) {
+	keystore := new(mockKeystore)
+	if err := json.NewDecoder(request.Body).Decode(keystore); err != nil || keystore.Address == "" {
+		writeJsonApiError(writer, http.StatusBadRequest, "Couldn't read a keystore from the request body")
+		return
+	}
+	address := "0x" + strings.ToLower(strings.TrimPrefix(keystore.Address, "0x"))
+	mock.mutex.Lock()
+	defer mock.mutex.Unlock()
+	if exportPassword, found := mock.exportedKeyPasswords[address]; found && exportPassword != request.URL.Query().Get(oldPasswordQueryParam) {
+		writeJsonApiError(writer, http.StatusBadRequest, "could not decrypt key with given password")
+		return
+	}
+	if mock.getEthKeyIndex(address) >= 0 {
+		writeJsonApiError(writer, http.StatusConflict, fmt.Sprintf("account already exists: %v", address))
+		return
+	}
+	ethKey := chainlink_oracle.EthereumKeyAttributes{
+		Address:     address,
+		EthBalance:  "0",
+		LinkBalance: "0",
+	}
+	mock.ethKeys = append(mock.ethKeys, ethKey)
+	writeJsonApiDocument(writer, http.StatusOK, jsonApiDocument{Data: getEthKeyResource(ethKey)})
+}
+
+func (mock *MockChainlinkServer) exportEthKey(writer http.ResponseWriter, address string, password string) {
+	mock.mutex.Lock()
+	defer mock.mutex.Unlock()
+	ethKeyIdx := mock.getEthKeyIndex(address)
+	if ethKeyIdx < 0 {
+		writeJsonApiError(writer, http.StatusNotFound, fmt.Sprintf("Key %v not found", address))
+		return
+	}
+	mock.exportedKeyPasswords[strings.ToLower(address)] = password
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(http.StatusOK)
+	json.NewEncoder(writer).Encode(mockKeystore{
+		Address: strings.TrimPrefix(strings.ToLower(mock.ethKeys[ethKeyIdx].Address), "0x"),
+		Version: 3,
+	})
+}
+
+func (mock *MockChainlinkServer) deleteEthKey(writer http.ResponseWriter, address string) {
+	mock.mutex.Lock()
+	defer mock.mutex.Unlock()
+	ethKeyIdx := mock.getEthKeyIndex(address)
+	if ethKeyIdx < 0 {
+		writeJsonApiError(writer, http.StatusNotFound, fmt.Sprintf("Key %v not found", address))
+		return
+	}
+	ethKey := mock.ethKeys[ethKeyIdx]
+	mock.ethKeys = append(mock.ethKeys[:ethKeyIdx], mock.ethKeys[ethKeyIdx + 1:]...)
+	writeJsonApiDocument(writer, http.StatusOK, jsonApiDocument{Data: getEthKeyResource(ethKey)})
+}
+
+/*
+	Serves the runs newest first, filtered by job spec ID and paged like the real node. Only the runs on the returned
+	page count as polled, so a run's scripted transitions advance only when a client actually sees it. The page is
+	made of copies taken under the mutex, so encoding it can't race with other requests moving the runs along.
+ */
+func (mock *MockChainlinkServer) handleRuns(writer http.ResponseWriter, request *http.Request) {
+	if request.Method != http.MethodGet {
+		writeJsonApiError(writer, http.StatusMethodNotAllowed, fmt.Sprintf("Method %v isn't supported", request.Method))
+		return
+	}
+	query := request.URL.Query()
+	page, isValid := getPositiveIntQueryParam(query, pageQueryParam, 1)
+	if !isValid {
+		writeJsonApiError(writer, http.StatusUnprocessableEntity, fmt.Sprintf("Query parameter %v must be a positive integer", pageQueryParam))
+		return
+	}
+	pageSize, isValid := getPositiveIntQueryParam(query, pageSizeQueryParam, mockDefaultRunsPageSize)
+	if !isValid {
+		writeJsonApiError(writer, http.StatusUnprocessableEntity, fmt.Sprintf("Query parameter %v must be a positive integer", pageSizeQueryParam))
+		return
+	}
+	jobId := query.Get(jobSpecIdQueryParam)
+
+	mock.mutex.Lock()
+	if mock.isIgnoringRunsPage {
+		page = 1
+	}
+	matchingRuns := []*mockRun{}
+	for _, run := range mock.runs {
+		if jobId == "" || run.run.Attributes.JobId == jobId {
+			matchingRuns = append(matchingRuns, run)
+		}
+	}
+	runResources := []jsonApiResource{}
+	for idx := (page - 1) * pageSize; idx < len(matchingRuns) && idx < page * pageSize; idx++ {
+		run := matchingRuns[idx]
+		run.numPollsSinceTransition++
+		run.applyDueTransitions()
+		runResources = append(runResources, getRunResource(run))
+	}
+	mock.mutex.Unlock()
+	writeJsonApiDocument(writer, http.StatusOK, jsonApiDocument{
+		Data: runResources,
+		Meta: map[string]interface{}{"count": len(matchingRuns)},
+	})
+}
+
+func (mock *MockChainlinkServer) handleRun(writer http.ResponseWriter, request *http.Request) {
+	if request.Method != http.MethodGet {
+		writeJsonApiError(writer, http.StatusMethodNotAllowed, fmt.Sprintf("Method %v isn't supported", request.Method))
+		return
+	}
+	runId := strings.TrimPrefix(request.URL.Path, "/" + runsEndpoint + "/")
+	mock.mutex.Lock()
+	defer mock.mutex.Unlock()
+	for _, run := range mock.runs {
+		if run.run.Attributes.Id == runId {
+			run.numPollsSinceTransition++
+			run.applyDueTransitions()
+			writeJsonApiDocument(writer, http.StatusOK, jsonApiDocument{Data: getRunResource(run)})
+			return
+		}
+	}
+	writeJsonApiError(writer, http.StatusNotFound, fmt.Sprintf("Run %v not found", runId))
+}
+
+// ===========================================================================================
+//                                  Helper methods
+// ===========================================================================================
+
+/*
+	Wraps a handler so it answers 401 unless the request carries a live session cookie or valid API token credentials,
+	like the real node.
+ */
+func (mock *MockChainlinkServer) requireSession(handler http.HandlerFunc) http.HandlerFunc {
+	return func(writer http.ResponseWriter, request *http.Request) {
+		sessionCookie, err := request.Cookie(sessionCookieName)
+		apiKey := request.Header.Get(apiKeyHeader)
+		mock.mutex.Lock()
+		isAuthenticated := err == nil && mock.sessionTokens[sessionCookie.Value]
+		if apiSecret, found := mock.apiTokens[apiKey]; found && apiKey != "" {
+			isAuthenticated = isAuthenticated || apiSecret == request.Header.Get(apiSecretHeader)
+		}
+		mock.mutex.Unlock()
+		if !isAuthenticated {
+			writeJsonApiError(writer, http.StatusUnauthorized, "Unauthorized")
+			return
+		}
+		handler(writer, request)
+	}
+}
+
+// Must be called with the mutex held; returns -1 if there's no key with the address
+func (mock *MockChainlinkServer) getEthKeyIndex(address string) int {
+	for idx, ethKey := range mock.ethKeys {
+		if strings.EqualFold(ethKey.Address, address) {
+			return idx
+		}
+	}
+	return -1
+}
+
+// Must be called with the mutex held
+func (mock *MockChainlinkServer) getNextId() string {
+	// The node's IDs are UUIDs without dashes
+	id := fmt.Sprintf("%032x", mock.nextId)
+	mock.nextId++
+	return id
+}
+
+/*
+	Adds a run of the given job, which starts in the given status and then goes through the given transitions. Must be
+	called with the mutex held.
+ */
+func (mock *MockChainlinkServer) addRun(jobId string, runRequest chainlink_oracle.RunRequest, initialStatus string, transitions []RunTransition) (*mockRun, error) {
+	jobSpecBytes, found := mock.jobSpecs[jobId]
+	if !found {
+		return nil, stacktrace.NewError("The mock Chainlink node has no job with ID '%v'", jobId)
+	}
+	var jobSpec chainlink_oracle.JobSpec
+	if err := json.Unmarshal(jobSpecBytes, &jobSpec); err != nil {
+		return nil, stacktrace.Propagate(err, "An error occurred deserializing the spec of job '%v'", jobId)
+	}
+	taskRuns := []chainlink_oracle.TaskRun{}
+	for range jobSpec.Tasks {
+		taskRuns = append(taskRuns, chainlink_oracle.TaskRun{
+			Id:     mock.getNextId(),
+			Status: taskStatusUnstarted,
+		})
+	}
+	initiator := chainlink_oracle.Initiator{JobSpecId: jobId}
+	if len(jobSpec.Initiators) > 0 {
+		initiator.Type = jobSpec.Initiators[0].Type
+	}
+
+	run := &mockRun{
+		run: chainlink_oracle.Run{
+			Type: runsResourceType,
+			Attributes: chainlink_oracle.RunAttributes{
+				Id:         mock.getNextId(),
+				JobId:      jobId,
+				Status:     initialStatus,
+				TaskRuns:   taskRuns,
+				Initiator:  initiator,
+				Payment:    runRequest.Payment,
+				RunRequest: runRequest,
+				CreatedAt:  time.Now(),
+			},
+		},
+		pendingTransitions: append([]RunTransition{}, transitions...),
+	}
+	// Transitions that don't wait for any polls apply right away
+	run.applyDueTransitions()
+	mock.runs = append([]*mockRun{run}, mock.runs...)
+	return run, nil
+}
+
+func (run *mockRun) applyDueTransitions() {
+	for len(run.pendingTransitions) > 0 && run.numPollsSinceTransition >= run.pendingTransitions[0].AfterNumPolls {
+		transition := run.pendingTransitions[0]
+		run.pendingTransitions = run.pendingTransitions[1:]
+		run.numPollsSinceTransition = 0
+
+		attributes := &run.run.Attributes
+		attributes.Status = transition.Status
+		taskStatus := transition.Status
+		if taskStatus != chainlink_oracle.RunStatusCompleted && taskStatus != chainlink_oracle.RunStatusErrored {
+			taskStatus = chainlink_oracle.RunStatusInProgress
+		}
+		for idx := range attributes.TaskRuns {
+			attributes.TaskRuns[idx].Status = taskStatus
+		}
+		if numTaskRuns := len(attributes.TaskRuns); numTaskRuns > 0 {
+			finalTaskRun := &attributes.TaskRuns[numTaskRuns - 1]
+			if transition.FulfillmentTxHash != "" {
+				finalTaskRun.Result.Data.Result = transition.FulfillmentTxHash
+			}
+			if transition.ErrorMessage != "" {
+				errorMessage := transition.ErrorMessage
+				finalTaskRun.Result.ErrorMessage = &errorMessage
+			}
+		}
+		if transition.Status == chainlink_oracle.RunStatusCompleted || transition.Status == chainlink_oracle.RunStatusErrored {
+			finishedAt := time.Now()
+			attributes.FinishedAt = &finishedAt
+		}
+	}
+}
+
+func getSpecResource(jobId string, rawJobSpec json.RawMessage) jsonApiResource {
+	var attributes map[string]interface{}
+	if err := json.Unmarshal(rawJobSpec, &attributes); err != nil {
+		attributes = map[string]interface{}{}
+	}
+	attributes["id"] = jobId
+	return jsonApiResource{
+		Type:       specsResourceType,
+		Id:         jobId,
+		Attributes: attributes,
+	}
+}
+
+func writeJsonApiDocument(writer http.ResponseWriter, statusCode int, document interface{}) {
+	writer.Header().Set("Content-Type", jsonApiContentType)
+	writer.WriteHeader(statusCode)
+	json.NewEncoder(writer).Encode(document)
+}
+
+func writeJsonApiError(writer http.ResponseWriter, statusCode int, detail string) {
+	writeJsonApiDocument(writer, statusCode, jsonApiErrorDocument{
+		Errors: []jsonApiError{{Detail: detail}},
+	})
+}
+
+func getEthKeyResource(ethKey chainlink_oracle.EthereumKeyAttributes) jsonApiResource {
+	return jsonApiResource{
+		Type:       ethKeysResourceType,
+		Id:         ethKey.Address,
+		Attributes: ethKey,
+	}
+}
+
+/*
+	Gets the resource for a copy of the run, which can be encoded once the mutex is released while other requests
+	keep applying transitions to the run itself. Must be called with the mutex held.
+ */
+func getRunResource(run *mockRun) jsonApiResource {
+	return jsonApiResource{
+		Type:       runsResourceType,
+		Id:         run.run.Attributes.Id,
+		Attributes: copyRun(run.run).Attributes,
+	}
+}
+
+/*
+	Copies the run along with its task runs and the values its pointers point to, which transitions modify in place.
+ */
+func copyRun(run chainlink_oracle.Run) chainlink_oracle.Run {
+	runCopy := run
+	runCopy.Attributes.TaskRuns = append([]chainlink_oracle.TaskRun{}, run.Attributes.TaskRuns...)
+	for idx := range runCopy.Attributes.TaskRuns {
+		if errorMessage := runCopy.Attributes.TaskRuns[idx].Result.ErrorMessage; errorMessage != nil {
+			errorMessageCopy := *errorMessage
+			runCopy.Attributes.TaskRuns[idx].Result.ErrorMessage = &errorMessageCopy
+		}
+	}
+	if finishedAt := run.Attributes.FinishedAt; finishedAt != nil {
+		finishedAtCopy := *finishedAt
+		runCopy.Attributes.FinishedAt = &finishedAtCopy
+	}
+	return runCopy
+}
+
+/*
+	Gets an optional positive integer query parameter, returning false if it was given but isn't one.
+ */
+func getPositiveIntQueryParam(query url.Values, name string, defaultValue int) (int, bool) {
+	valueStr := query.Get(name)
+	if valueStr == "" {
+		return defaultValue, true
+	}
+	value, err := strconv.Atoi(valueStr)
+	if err != nil || value < 1 {
+		return 0, false
+	}
+	return value, true
+}
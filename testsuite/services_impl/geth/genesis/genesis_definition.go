@@ -1,8 +1,38 @@
 package genesis
 
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Seconds between blocks sealed by the clique signers, matching "period" in the genesis config below
+const CliquePeriodSeconds = 1
+
+const (
+	// Clique extradata is 32 bytes of vanity, then the signer addresses, then a 65 byte seal which is empty at genesis
+	extradataVanityHex = "0000000000000000000000000000000000000000000000000000000000000000"
+	extradataSealHex   = "0000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000"
+)
+
+/*
+	Generates the genesis config with the given accounts as the clique signer set. Every node of a network must be
+	initialized with the same genesis, so every node needs the full list of signers, not just its own address.
+ */
+func GenerateGenesisJson(signerAddresses []string) string {
+	normalizedAddresses := []string{}
+	for _, address := range signerAddresses {
+		normalizedAddresses = append(normalizedAddresses, strings.TrimPrefix(strings.ToLower(address), "0x"))
+	}
+	// Clique keeps its signers sorted, so we list them in the same order it will write them into checkpoint blocks
+	sort.Strings(normalizedAddresses)
+	extradata := "0x" + extradataVanityHex + strings.Join(normalizedAddresses, "") + extradataSealHex
+	return fmt.Sprintf(genesisJsonTemplate, extradata)
+}
+
 // see the clique genesis json here: https://geth.ethereum.org/docs/interface/private-network
-// must have extradata contain the default account, first funded. this is the default signer set for clique genesis
-const GenesisJson =
+// extradata contains the signer set, which must include accounts whose keys are in the geth data dir keystore
+const genesisJsonTemplate =
 	`{
     "config": {
 		"chainId": 9,
@@ -20,10 +50,11 @@ const GenesisJson =
 	},
   	"difficulty": "1",
 	"gasLimit": "10000000",
-	"extradata": "0x00000000000000000000000000000000000000000000000000000000000000008ea1441a74ffbe9504a8cb3f7e4b7118d8ccfc560000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000",
+	"extradata": "%v",
   	"alloc": {
     	"8ea1441a74ffbe9504a8cb3f7e4b7118d8ccfc56": { "balance": "30000000000000000000000000000000000000000000000000000" },
     	"6f75c1925ef6d0c9a23fba6e4b889c52dd9d7f74": { "balance": "30000000000000000000000000000000000000000000000000000" },
-		"e68af577b1267c1e75d908668cb8ea4f72587d05": { "balance": "30000000000000000000000000000000000000000000000000000" }
+		"e68af577b1267c1e75d908668cb8ea4f72587d05": { "balance": "30000000000000000000000000000000000000000000000000000" },
+		"30a2ae74a64f2a311d9f72949ca2df4ca9038c71": { "balance": "30000000000000000000000000000000000000000000000000000" }
 	}
 }`
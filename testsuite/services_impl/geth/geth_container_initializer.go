@@ -6,49 +6,83 @@ import (
 	"github.com/kurtosistech/chainlink-testing/testsuite/services_impl/geth/genesis"
 	"github.com/palantir/stacktrace"
 	"os"
+	"strings"
 )
 
 const (
 	rpcPort       = 8545
 	wsPort 		  = 8546
 	discoveryPort = 30303
+	metricsPort   = 6060
 
 	httpExposedApisString  = "admin,eth,net,web3,miner,personal,txpool,debug"
 	wsExposedApisString    = "admin,eth,net,web3,miner,personal,txpool,debug"
 	keystoreFilename       = "keystore"
 	genesisJsonFilename    = "genesis.json"
 	passwordFilename       = "password.txt"
+	keyImportKeystoreFilename = "key-import-keystore.json"
 	gasPrice               = 1
 	gethDataMountedDirpath = "/geth-mounted-data"
 	gethTgzDataDir         = "geth-data-dir"
 	privateKeyFilePassword = "password"
-	targetGasLimit         = 10000000
+
+	// Block gas limit the miner targets; this caps how much transaction load a single block can absorb.
+	TargetGasLimit = 10000000
 
 	FirstFundedAddress  = "0x8eA1441a74ffbE9504a8Cb3F7e4b7118d8CcFc56"
+	// Other accounts prefunded in the genesis block whose keys live in the geth data dir keystore.
+	SecondFundedAddress = "0x6f75c1925ef6d0c9a23fba6e4b889c52dd9d7f74"
+	ThirdFundedAddress  = "0xe68af577b1267c1e75d908668cb8ea4f72587d05"
+	// Prefunded account reserved for importing into Oracles, so that nothing else in the network transacts from it.
+	// It isn't in the geth data dir, so its keystore is added to every node's keystore when the node starts.
+	KeyImportFundedAddress = "0x30a2ae74a64f2a311d9f72949ca2df4ca9038c71"
+	// Geth looks keystore files up by the address at the end of their name, so the timestamp doesn't matter
+	keyImportKeystoreFileBasename = "UTC--2021-03-17T00-00-00.000000000Z--30a2ae74a64f2a311d9f72949ca2df4ca9038c71"
+	// Encrypted with privateKeyFilePassword, like the keystores in the geth data dir
+	keyImportKeystoreJson = `{"address":"30a2ae74a64f2a311d9f72949ca2df4ca9038c71","crypto":{"cipher":"aes-128-ctr","ciphertext":"6fbe944b81ebb49d007cc7d73b7c3393fac056ef343d4c4458936f3ff160b261","cipherparams":{"iv":"c50c09f794eb6f5ef07418f6cb99e59a"},"kdf":"scrypt","kdfparams":{"dklen":32,"n":262144,"p":1,"r":8,"salt":"6aa4468b91caf627cfd107eb2191799ea8163b48c3a71350a39711761421de98"},"mac":"956b695cd47e12d0242dbb9e4b7c61e85b6da5ade7cbd51f76e0e9964240280e"},"id":"659fb349-575c-461f-a9cf-cdc4755b6a5c","version":3}`
 
 	// The geth node opens a socket for IPC communication in the genesis directory.
 	// This socket opening does not work on mounted filesystems, so runtime genesis directory needs to be off the mount.
 	// See: https://github.com/ethereum/go-ethereum/issues/16342
 	gethDataRuntimeDirpath = "/data"
+	gethLogFilepath = gethDataRuntimeDirpath + "/geth.log"
 
 	PrivateKeyPassword = "password"
 	PrivateNetworkId     = 9
 	TestVolumeMountpoint = "/test-volume"
+	// Kurtosis mounts the same volume here on the testsuite container, so files can be written from either side using
+	// the same path relative to the mountpoint
+	SuiteExecutionVolumeMountpoint = "/suite-execution"
 )
 
+// Prefunded accounts whose keys are in the geth data dir keystore, and so can act as clique signers
+var SignerCandidateAddresses = []string{
+	FirstFundedAddress,
+	SecondFundedAddress,
+	ThirdFundedAddress,
+}
+
+// Every prefunded account whose key is in the nodes' keystores
+var KeystoreAddresses = append(append([]string{}, SignerCandidateAddresses...), KeyImportFundedAddress)
+
 type GethContainerInitializer struct {
 	dockerImage string
 	dataDirArtifactId services.FilesArtifactID
-	gethBootstrapperService *GethService
-	isMiner bool
+	gethBootstrapperService GethNode
+	// Account this node seals blocks with; empty if the node isn't a signer
+	signerAddress string
+	// The whole clique signer set, which goes into the genesis every node is initialized with
+	genesisSignerAddresses []string
 }
 
-func NewGethContainerInitializer(dockerImage string, dataDirArtifactId services.FilesArtifactID, gethBootstrapperService *GethService, isMiner bool) *GethContainerInitializer {
+func NewGethContainerInitializer(dockerImage string, dataDirArtifactId services.FilesArtifactID, gethBootstrapperService GethNode,
+	signerAddress string, genesisSignerAddresses []string) *GethContainerInitializer {
 	return &GethContainerInitializer{
 		dockerImage: dockerImage,
 		dataDirArtifactId: dataDirArtifactId,
 		gethBootstrapperService: gethBootstrapperService,
-		isMiner: isMiner,
+		signerAddress: signerAddress,
+		genesisSignerAddresses: genesisSignerAddresses,
 	}
 }
 
@@ -62,22 +96,24 @@ func (initializer GethContainerInitializer) GetUsedPorts() map[string]bool {
 		fmt.Sprintf("%v/tcp", wsPort):       true,
 		fmt.Sprintf("%v/udp", discoveryPort): true,
 		fmt.Sprintf("%v/tcp", discoveryPort): true,
+		fmt.Sprintf("%v/tcp", metricsPort): true,
 	}
 }
 
 func (initializer GethContainerInitializer) GetService(ctx *services.ServiceContext) services.Service {
-	return NewGethService(ctx, rpcPort);
+	return NewGethService(ctx, rpcPort, wsPort, metricsPort);
 }
 
 func (initializer GethContainerInitializer) GetFilesToGenerate() map[string]bool {
 	return map[string]bool{
 		genesisJsonFilename: true,
 		passwordFilename: true,
+		keyImportKeystoreFilename: true,
 	}
 }
 
 func (initializer GethContainerInitializer) InitializeGeneratedFiles(mountedFiles map[string]*os.File) error {
-	genesisJson := genesis.GenesisJson
+	genesisJson := genesis.GenerateGenesisJson(initializer.genesisSignerAddresses)
 	genesisFp := mountedFiles[genesisJsonFilename]
 	_, err := genesisFp.WriteString(genesisJson)
 	if err != nil {
@@ -87,6 +123,10 @@ func (initializer GethContainerInitializer) InitializeGeneratedFiles(mountedFile
 	if err != nil {
 		return stacktrace.Propagate(err, "Failed to write password file.")
 	}
+	_, err = mountedFiles[keyImportKeystoreFilename].WriteString(keyImportKeystoreJson)
+	if err != nil {
+		return stacktrace.Propagate(err, "Failed to write the keystore of the key import account.")
+	}
 	return nil
 }
 
@@ -105,8 +145,16 @@ func (initializer GethContainerInitializer) GetEnvironmentVariableOverrides() (m
 }
 
 func (initializer GethContainerInitializer) GetStartCommandOverrides(mountedFileFilepaths map[string]string, ipPlaceholder string) (entrypointArgs []string, cmdArgs []string, resultErr error) {
+	// Without pipefail the container's exit status would be tee's rather than geth's, once geth's output is piped
+	// through tee below; the geth image's shell is BusyBox ash, which supports it
+	entrypointCommand := "set -o pipefail && "
 	// This is a bootstrapper
-	entrypointCommand := fmt.Sprintf("mkdir -p %v && cp -r %v/%v/* %v/ && ", gethDataRuntimeDirpath, gethDataMountedDirpath, gethTgzDataDir, gethDataRuntimeDirpath)
+	entrypointCommand += fmt.Sprintf("mkdir -p %v && cp -r %v/%v/* %v/ && ", gethDataRuntimeDirpath, gethDataMountedDirpath, gethTgzDataDir, gethDataRuntimeDirpath)
+	entrypointCommand += fmt.Sprintf("cp %v %v/%v/%v && ",
+		mountedFileFilepaths[keyImportKeystoreFilename],
+		gethDataRuntimeDirpath,
+		keystoreFilename,
+		keyImportKeystoreFileBasename)
 	entrypointCommand += fmt.Sprintf("geth init --datadir %v %v && ", gethDataRuntimeDirpath, mountedFileFilepaths[genesisJsonFilename])
 	entrypointCommand += fmt.Sprintf("geth --nodiscover --verbosity 4 --keystore %v --datadir %v --networkid %v ",
 		gethDataRuntimeDirpath + string(os.PathSeparator) + keystoreFilename,
@@ -116,21 +164,40 @@ func (initializer GethContainerInitializer) GetStartCommandOverrides(mountedFile
 		httpExposedApisString,
 		ipPlaceholder,
 		ipPlaceholder)
+	// Serves Prometheus metrics at metricsPrometheusPath, for the testsuite's metrics collector
+	entrypointCommand += fmt.Sprintf("--metrics --metrics.addr %v --metrics.port %v ", ipPlaceholder, metricsPort)
 	// Chainlink oracles require websocket communication
 	entrypointCommand += fmt.Sprintf("--ws --ws.addr %v --ws.port %v --ws.api %v --ws.origins=\"*\" ", ipPlaceholder, wsPort, wsExposedApisString)
-	if initializer.isMiner {
+	accountsToUnlock := []string{}
+	if initializer.gethBootstrapperService == nil {
+		// unlock the first account on the bootstrapper for use in spawning $LINK contract and distributing funds.
+		accountsToUnlock = append(accountsToUnlock, FirstFundedAddress)
+	}
+	if initializer.signerAddress != "" {
 		entrypointCommand += fmt.Sprintf("--mine --miner.threads=1 --miner.etherbase=%v --miner.gasprice=%v --miner.gaslimit=%v ",
-			FirstFundedAddress, gasPrice, targetGasLimit)
-		// unlock the first account for use in spawning $LINK contract and distributing funds.
-		entrypointCommand += fmt.Sprintf("--unlock %v --password %v  --allow-insecure-unlock ", FirstFundedAddress, mountedFileFilepaths[passwordFilename])
+			initializer.signerAddress, gasPrice, TargetGasLimit)
+		// Clique signers seal blocks with their etherbase account, so it has to be unlocked
+		isAlreadyUnlocked := len(accountsToUnlock) > 0 && strings.EqualFold(accountsToUnlock[0], initializer.signerAddress)
+		if !isAlreadyUnlocked {
+			accountsToUnlock = append(accountsToUnlock, initializer.signerAddress)
+		}
+	}
+	if len(accountsToUnlock) > 0 {
+		// Every keystore account has the same password, and geth reuses the last line of the password file for any
+		// accounts past the number of lines
+		entrypointCommand += fmt.Sprintf("--unlock %v --password %v ", strings.Join(accountsToUnlock, ","), mountedFileFilepaths[passwordFilename])
 	}
+	// Allows the testsuite to unlock the other prefunded accounts over RPC, e.g. for generating transaction load.
+	entrypointCommand += "--allow-insecure-unlock "
 	if initializer.gethBootstrapperService != nil {
 		bootnodeEnodeRecord, err := initializer.gethBootstrapperService.GetEnodeAddress()
 		if err != nil {
 			return nil, nil, stacktrace.Propagate(err, "Failed to get bootnode enode record.")
 		}
-		entrypointCommand += fmt.Sprintf("--bootnodes %v", bootnodeEnodeRecord)
+		entrypointCommand += fmt.Sprintf("--bootnodes %v ", bootnodeEnodeRecord)
 	}
+	// Keep a copy of geth's output in the container, so it can be collected if a test fails
+	entrypointCommand += fmt.Sprintf("2>&1 | tee %v", gethLogFilepath)
 
 	entrypointArgs = []string{
 		"/bin/sh",
@@ -2,43 +2,101 @@ package geth
 
 import (
 	"bytes"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
-	"github.com/kurtosis-tech/kurtosis-libs/golang/lib/services"
+	"github.com/kurtosistech/chainlink-testing/testsuite/services_impl/service_context"
 	"github.com/palantir/stacktrace"
 	"github.com/sirupsen/logrus"
 	"io"
 	"io/ioutil"
+	"math/big"
 	"net/http"
+	"path"
+	"strconv"
 	"strings"
 )
 
 const (
-	adminInfoRpcCall = `{"jsonrpc":"2.0","method": "admin_nodeInfo","params":[],"id":67}`
-	adminPeerRpcCall = `{"jsonrpc":"2.0", "method": "admin_peers","params":[],"id":67}`
 	enodePrefix = "enode://"
+	metricsPrometheusPath = "debug/metrics/prometheus"
+
+	// Geth gauge of the latest block number the node has imported
+	ChainHeadBlockMetric = "chain_head_block"
 	ipcPath = "ipc:/data/geth.ipc"
+
+	TransactionSucceededStatus = "0x1"
+	TransactionRevertedStatus = "0x0"
+
+	hexPrefix = "0x"
+	latestBlockTag = "latest"
+	abiWordSizeBytes = 32
+	jsonRpcVersion = "2.0"
+	jsonRpcRequestId = 1
+
+	// Passing a zero duration to personal_unlockAccount keeps the account unlocked until geth exits
+	unlockIndefinitelyDurationSeconds = 0
 )
 
-type GethService struct {
-	serviceCtx *services.ServiceContext
-	rpcPort   int
-}
+/*
+	A geth node the network can talk to. The network holds its nodes by this rather than by GethService, so its logic
+	can be exercised against stand-in nodes without live containers.
+ */
+type GethNode interface {
+	IsAvailable() bool
 
-type NodeInfoResponse struct {
-	Result NodeInfo `json:"result"`
-}
+	GetIPAddress() string
 
-type NodeInfo struct {
-	Enode string `json: "enode"`
+	GetRpcPort() int
+
+	GetWsPort() int
+
+	GetMetricsUrl() string
+
+	AddPeer(peerEnode string) (bool, error)
+
+	GetPeers() ([]Peer, error)
+
+	GetEnodeAddress() (string, error)
+
+	SendTransaction(from string, to string, amount string) error
+
+	UnlockAccount(address string, password string) error
+
+	GetKeystoreJson(address string) ([]byte, error)
+
+	GetBalance(address string) (*big.Int, error)
+
+	SendRpcTransaction(txArgs TransactionArgs) (string, error)
+
+	GetBlockNumber() (uint64, error)
+
+	GetBlockByNumber(blockNumber uint64) (*Block, error)
+
+	GetTransactionReceipt(txHash string) (*TransactionReceipt, error)
+
+	GetTransactionByHash(txHash string) (*Transaction, error)
+
+	CallContract(contractAddress string, callData string) (string, error)
+
+	CallContractFrom(from string, contractAddress string, callData string) (string, error)
+
+	GetRawRpcResult(method string, params []interface{}) (json.RawMessage, error)
+
+	CopyLogsToTestVolume(relativeDirpath string) error
 }
 
-type AddPeerResponse struct {
-	Result bool `json:"result"`
+var _ GethNode = (*GethService)(nil)
+
+type GethService struct {
+	serviceCtx  service_context.ServiceContext
+	rpcPort     int
+	wsPort      int
+	metricsPort int
 }
 
-type GetPeersResponse struct {
-	Result []Peer `json:"result"`
+type NodeInfo struct {
+	Enode string `json:"enode"`
 }
 
 type Peer struct {
@@ -52,8 +110,75 @@ type NetworkRecord struct {
 	RemoteAddress string `json:"remoteAddress"`
 }
 
-func NewGethService(serviceCtx *services.ServiceContext, port int) *GethService {
-	return &GethService{serviceCtx: serviceCtx, rpcPort: port}
+type TransactionArgs struct {
+	From string `json:"from"`
+	To string `json:"to,omitempty"`
+	// Gas limit, as a hex quantity
+	Gas string `json:"gas,omitempty"`
+	// Value in wei, as a hex quantity
+	Value string `json:"value,omitempty"`
+	// Input data, as a hex string
+	Data string `json:"data,omitempty"`
+}
+
+type Block struct {
+	Number string `json:"number"`
+	Timestamp string `json:"timestamp"`
+	GasUsed string `json:"gasUsed"`
+	GasLimit string `json:"gasLimit"`
+	Transactions []string `json:"transactions"`
+}
+
+type Transaction struct {
+	Hash string `json:"hash"`
+	From string `json:"from"`
+	To string `json:"to"`
+	GasPrice string `json:"gasPrice"`
+	BlockNumber string `json:"blockNumber"`
+}
+
+type TransactionReceipt struct {
+	TransactionHash string `json:"transactionHash"`
+	BlockNumber string `json:"blockNumber"`
+	From string `json:"from"`
+	To string `json:"to"`
+	GasUsed string `json:"gasUsed"`
+	// TransactionSucceededStatus if the transaction succeeded, TransactionRevertedStatus if it reverted
+	Status string `json:"status"`
+	Logs []Log `json:"logs"`
+}
+
+type Log struct {
+	Address string `json:"address"`
+	Topics []string `json:"topics"`
+	Data string `json:"data"`
+	BlockNumber string `json:"blockNumber"`
+}
+
+type jsonRpcRequest struct {
+	JsonRpc string `json:"jsonrpc"`
+	Method string `json:"method"`
+	Params []interface{} `json:"params"`
+	Id int `json:"id"`
+}
+
+type jsonRpcResponse struct {
+	Result json.RawMessage `json:"result"`
+	Error *JsonRpcError `json:"error"`
+}
+
+type JsonRpcError struct {
+	Code int `json:"code"`
+	Message string `json:"message"`
+}
+
+func NewGethService(serviceCtx service_context.ServiceContext, rpcPort int, wsPort int, metricsPort int) *GethService {
+	return &GethService{
+		serviceCtx:  serviceCtx,
+		rpcPort:     rpcPort,
+		wsPort:      wsPort,
+		metricsPort: metricsPort,
+	}
 }
 
 func (service GethService) GetIPAddress() string {
@@ -62,41 +187,42 @@ func (service GethService) GetIPAddress() string {
 }
 
 func (service GethService) GetRpcPort() int {
-	return rpcPort
+	return service.rpcPort
 }
 
 func (service GethService) GetWsPort() int {
-	return wsPort
+	return service.wsPort
+}
+
+func (service GethService) GetMetricsUrl() string {
+	return fmt.Sprintf("http://%v:%v/%v", service.GetIPAddress(), service.metricsPort, metricsPrometheusPath)
 }
 
 func (service GethService) AddPeer(peerEnode string) (bool, error) {
-	adminAddPeerRpcCall := fmt.Sprintf(`{"jsonrpc":"2.0", "method": "admin_addPeer", "params": ["%v"], "id":70}`, peerEnode)
-	logrus.Tracef("Admin add peer rpc call: %v", adminAddPeerRpcCall)
-	addPeerResponse := new(AddPeerResponse)
-	err := service.sendRpcCall(adminAddPeerRpcCall, addPeerResponse)
-	logrus.Tracef("AddPeer response: %+v", addPeerResponse)
+	var isAdded bool
+	err := service.callRpcMethod("admin_addPeer", []interface{}{peerEnode}, &isAdded)
 	if err != nil {
 		return false, stacktrace.Propagate(err, "Failed to send addPeer RPC call for enode %v", peerEnode)
 	}
-	return addPeerResponse.Result, nil
+	return isAdded, nil
 }
 
 func (service GethService) GetPeers() ([]Peer, error) {
-	getPeersResponse := new(GetPeersResponse)
-	err := service.sendRpcCall(adminPeerRpcCall, getPeersResponse)
+	var peers []Peer
+	err := service.callRpcMethod("admin_peers", []interface{}{}, &peers)
 	if err != nil {
 		return nil, stacktrace.Propagate(err, "Failed to send getPeers RPC call for service %v", service.serviceCtx.GetServiceID())
 	}
-	return getPeersResponse.Result, nil
+	return peers, nil
 }
 
 func (service GethService) GetEnodeAddress() (string, error) {
-	nodeInfoResponse := new(NodeInfoResponse)
-	err := service.sendRpcCall(adminInfoRpcCall, nodeInfoResponse)
+	var nodeInfo NodeInfo
+	err := service.callRpcMethod("admin_nodeInfo", []interface{}{}, &nodeInfo)
 	if err != nil {
 		return "", stacktrace.Propagate(err, "Failed to send admin node info RPC request to geth node %v", service.serviceCtx.GetServiceID())
 	}
-	return nodeInfoResponse.Result.Enode, nil
+	return nodeInfo.Enode, nil
 }
 
 func (service GethService) SendTransaction(from string, to string, amount string) (error) {
@@ -113,6 +239,180 @@ func (service GethService) SendTransaction(from string, to string, amount string
 	return nil
 }
 
+/*
+	Unlocks one of the accounts in the node's keystore so that transactions can be sent from it over RPC.
+ */
+func (service GethService) UnlockAccount(address string, password string) error {
+	var unlocked bool
+	err := service.callRpcMethod("personal_unlockAccount", []interface{}{address, password, unlockIndefinitelyDurationSeconds}, &unlocked)
+	if err != nil {
+		return stacktrace.Propagate(err, "Failed to unlock account %v on geth node %v", address, service.serviceCtx.GetServiceID())
+	}
+	if !unlocked {
+		return stacktrace.NewError("Geth node %v reported that account %v was not unlocked", service.serviceCtx.GetServiceID(), address)
+	}
+	return nil
+}
+
+/*
+	Gets the JSON keystore file of one of the accounts in the node's keystore, encrypted with PrivateKeyPassword like
+	every account in the geth data dir.
+ */
+func (service GethService) GetKeystoreJson(address string) ([]byte, error) {
+	// Geth names keystore files UTC--<creation time>--<address in lowercase hex, without a prefix>
+	addressHex := strings.ToLower(strings.TrimPrefix(address, hexPrefix))
+	getKeystoreCommand := []string{
+		"/bin/sh",
+		"-c",
+		fmt.Sprintf("cat %v/*--%v", gethDataRuntimeDirpath + "/" + keystoreFilename, addressHex),
+	}
+	exitCode, logOutput, err := service.serviceCtx.ExecCommand(getKeystoreCommand)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "Failed to execute command to read the keystore of %v on geth node %v", address, service.serviceCtx.GetServiceID())
+	}
+	if exitCode != 0 {
+		return nil, stacktrace.NewError("Reading the keystore of %v on geth node %v exited with code %v: %v",
+			address, service.serviceCtx.GetServiceID(), exitCode, string(*logOutput))
+	}
+	return *logOutput, nil
+}
+
+/*
+	Gets the balance of the given account in wei, as of the latest block.
+ */
+func (service GethService) GetBalance(address string) (*big.Int, error) {
+	var balanceHex string
+	err := service.callRpcMethod("eth_getBalance", []interface{}{address, latestBlockTag}, &balanceHex)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "Failed to get the balance of %v from geth node %v", address, service.serviceCtx.GetServiceID())
+	}
+	balance, err := ParseHexUint256(balanceHex)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "Geth node %v returned an invalid balance for %v", service.serviceCtx.GetServiceID(), address)
+	}
+	return balance, nil
+}
+
+/*
+	Submits a transaction from an unlocked account over RPC, returning the transaction hash.
+ */
+func (service GethService) SendRpcTransaction(txArgs TransactionArgs) (string, error) {
+	var txHash string
+	err := service.callRpcMethod("eth_sendTransaction", []interface{}{txArgs}, &txHash)
+	if err != nil {
+		return "", stacktrace.Propagate(err, "Failed to send transaction from %v to %v", txArgs.From, txArgs.To)
+	}
+	return txHash, nil
+}
+
+func (service GethService) GetBlockNumber() (uint64, error) {
+	var blockNumberHex string
+	err := service.callRpcMethod("eth_blockNumber", []interface{}{}, &blockNumberHex)
+	if err != nil {
+		return 0, stacktrace.Propagate(err, "Failed to get block number from geth node %v", service.serviceCtx.GetServiceID())
+	}
+	blockNumber, err := ParseHexQuantity(blockNumberHex)
+	if err != nil {
+		return 0, stacktrace.Propagate(err, "Failed to parse block number '%v'", blockNumberHex)
+	}
+	return blockNumber, nil
+}
+
+/*
+	Gets a block by number, listing only the hashes of its transactions.
+ */
+func (service GethService) GetBlockByNumber(blockNumber uint64) (*Block, error) {
+	block := new(Block)
+	err := service.callRpcMethod("eth_getBlockByNumber", []interface{}{FormatHexQuantity(blockNumber), false}, block)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "Failed to get block %v from geth node %v", blockNumber, service.serviceCtx.GetServiceID())
+	}
+	return block, nil
+}
+
+/*
+	Gets the receipt of a mined transaction, or nil if the transaction hasn't been mined yet.
+ */
+func (service GethService) GetTransactionReceipt(txHash string) (*TransactionReceipt, error) {
+	var receipt *TransactionReceipt
+	err := service.callRpcMethod("eth_getTransactionReceipt", []interface{}{txHash}, &receipt)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "Failed to get receipt for transaction %v", txHash)
+	}
+	return receipt, nil
+}
+
+func (service GethService) GetTransactionByHash(txHash string) (*Transaction, error) {
+	var transaction *Transaction
+	err := service.callRpcMethod("eth_getTransactionByHash", []interface{}{txHash}, &transaction)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "Failed to get transaction %v", txHash)
+	}
+	if transaction == nil {
+		return nil, stacktrace.NewError("Geth node %v doesn't know of transaction %v", service.serviceCtx.GetServiceID(), txHash)
+	}
+	return transaction, nil
+}
+
+/*
+	Runs the given call data (a function selector and its ABI-encoded arguments) against the contract at the given
+	address in the latest block without sending a transaction, returning the hex-encoded return data.
+ */
+func (service GethService) CallContract(contractAddress string, callData string) (string, error) {
+	return service.CallContractFrom("", contractAddress, callData)
+}
+
+/*
+	Like CallContract, but calling as the given account, for functions only some accounts may call, e.g. those
+	restricted to the contract's owner. An empty account calls as nobody in particular.
+ */
+func (service GethService) CallContractFrom(from string, contractAddress string, callData string) (string, error) {
+	callArgs := map[string]string{
+		"to": contractAddress,
+		"data": callData,
+	}
+	if from != "" {
+		callArgs["from"] = from
+	}
+	var returnData string
+	err := service.callRpcMethod("eth_call", []interface{}{callArgs, latestBlockTag}, &returnData)
+	if err != nil {
+		return "", stacktrace.Propagate(err, "Failed to call contract %v with data %v", contractAddress, callData)
+	}
+	return returnData, nil
+}
+
+/*
+	Calls an RPC method, returning its raw JSON result; useful for snapshotting node state.
+ */
+func (service GethService) GetRawRpcResult(method string, params []interface{}) (json.RawMessage, error) {
+	var result json.RawMessage
+	if err := service.callRpcMethod(method, params, &result); err != nil {
+		return nil, stacktrace.Propagate(err, "Failed to call RPC method %v on geth node %v", method, service.serviceCtx.GetServiceID())
+	}
+	return result, nil
+}
+
+/*
+	Copies the node's log into the given directory, relative to the root of the test volume.
+ */
+func (service GethService) CopyLogsToTestVolume(relativeDirpath string) error {
+	destDirpath := path.Join(TestVolumeMountpoint, relativeDirpath)
+	copyLogsCommand := []string{
+		"/bin/sh",
+		"-c",
+		fmt.Sprintf("mkdir -p %v && cp %v %v/", destDirpath, gethLogFilepath, destDirpath),
+	}
+	exitCode, logOutput, err := service.serviceCtx.ExecCommand(copyLogsCommand)
+	if err != nil {
+		return stacktrace.Propagate(err, "Failed to execute command to copy logs of geth node %v", service.serviceCtx.GetServiceID())
+	}
+	if exitCode != 0 {
+		return stacktrace.NewError("Copying logs of geth node %v exited with code %v: %v", service.serviceCtx.GetServiceID(), exitCode, string(*logOutput))
+	}
+	return nil
+}
+
 // ===========================================================================================
 //                              Service interface methods
 // ===========================================================================================
@@ -131,7 +431,7 @@ func (service GethService) IsAvailable() bool {
 // ==========================================================================================
 
 func (service GethService) sendRpcCall(rpcJsonString string, targetStruct interface{}) error {
-	url := fmt.Sprintf("http://%v:%v", service.serviceCtx.GetIPAddress(), rpcPort)
+	url := fmt.Sprintf("http://%v:%v", service.serviceCtx.GetIPAddress(), service.rpcPort)
 	var jsonByteArray = []byte(rpcJsonString)
 
 	resp, err := http.Post(url, "application/json", bytes.NewBuffer(jsonByteArray))
@@ -159,4 +459,117 @@ func (service GethService) sendRpcCall(rpcJsonString string, targetStruct interf
 	} else {
 		return stacktrace.NewError("Received non-200 status code rom admin RPC api: %v", resp.StatusCode)
 	}
+}
+
+/*
+	Calls a JSON-RPC method with the given params, decoding the result into targetStruct and surfacing RPC-level errors.
+ */
+func (service GethService) callRpcMethod(method string, params []interface{}, targetStruct interface{}) error {
+	request := jsonRpcRequest{
+		JsonRpc: jsonRpcVersion,
+		Method:  method,
+		Params:  params,
+		Id:      jsonRpcRequestId,
+	}
+	requestBytes, err := json.Marshal(request)
+	if err != nil {
+		return stacktrace.Propagate(err, "Failed to serialize RPC request for method %v", method)
+	}
+	response := new(jsonRpcResponse)
+	if err := service.sendRpcCall(string(requestBytes), response); err != nil {
+		return stacktrace.Propagate(err, "Failed to send RPC request for method %v", method)
+	}
+	if response.Error != nil {
+		return stacktrace.NewError("RPC method %v returned error code %v: %v", method, response.Error.Code, response.Error.Message)
+	}
+	if err := json.Unmarshal(response.Result, targetStruct); err != nil {
+		return stacktrace.Propagate(err, "Failed to parse result of RPC method %v into target struct", method)
+	}
+	return nil
+}
+
+func ParseHexQuantity(hexQuantity string) (uint64, error) {
+	quantity, err := strconv.ParseUint(strings.TrimPrefix(hexQuantity, hexPrefix), 16, 64)
+	if err != nil {
+		return 0, stacktrace.Propagate(err, "Failed to parse hex quantity '%v'", hexQuantity)
+	}
+	return quantity, nil
+}
+
+/*
+	Parses an ABI-encoded uint256, such as a contract function's return data, which can be too big for a uint64.
+ */
+func ParseHexUint256(hexData string) (*big.Int, error) {
+	value, ok := new(big.Int).SetString(strings.TrimPrefix(hexData, hexPrefix), 16)
+	if !ok {
+		return nil, stacktrace.NewError("Failed to parse hex uint256 '%v'", hexData)
+	}
+	return value, nil
+}
+
+/*
+	ABI-encodes a uint256 as a 32 byte word, as hex without a prefix, e.g. to append to a function selector.
+ */
+func EncodeUint256Word(value uint64) string {
+	return fmt.Sprintf("%064x", value)
+}
+
+/*
+	ABI-encodes a string of at most 32 bytes as a bytes32 word, right-padded with zeros, as hex without a prefix.
+ */
+func EncodeBytes32Word(data string) (string, error) {
+	if len(data) > abiWordSizeBytes {
+		return "", stacktrace.NewError("Can't fit %v bytes into a bytes32 word: '%v'", len(data), data)
+	}
+	return hex.EncodeToString([]byte(data)) + strings.Repeat("00", abiWordSizeBytes - len(data)), nil
+}
+
+/*
+	ABI-encodes a uint256 too big for a uint64, e.g. an amount of juels, as a 32 byte word, as hex without a prefix.
+ */
+func EncodeBigUint256Word(value *big.Int) string {
+	return fmt.Sprintf("%064x", value)
+}
+
+/*
+	ABI-encodes hex data of at most 32 bytes, such as a bytes4 function selector, as a fixed size bytes word,
+	right-padded with zeros, as hex without a prefix.
+ */
+func EncodeFixedBytesWord(hexData string) (string, error) {
+	dataHex := strings.TrimPrefix(hexData, hexPrefix)
+	if len(dataHex) > 2 * abiWordSizeBytes {
+		return "", stacktrace.NewError("Can't fit %v hex characters into a fixed size bytes word: '%v'", len(dataHex), hexData)
+	}
+	return dataHex + strings.Repeat("0", 2 * abiWordSizeBytes - len(dataHex)), nil
+}
+
+/*
+	ABI-encodes an address as a 32 byte word, left-padded with zeros, as hex without a prefix.
+ */
+func EncodeAddressWord(address string) (string, error) {
+	addressHex := strings.ToLower(strings.TrimPrefix(address, hexPrefix))
+	if len(addressHex) > 2 * abiWordSizeBytes {
+		return "", stacktrace.NewError("Can't fit %v hex characters into an address word: '%v'", len(addressHex), address)
+	}
+	return strings.Repeat("0", 2 * abiWordSizeBytes - len(addressHex)) + addressHex, nil
+}
+
+/*
+	Splits ABI-encoded data, such as a log's data, into its 32 byte words, each as hex without a prefix.
+ */
+func SplitAbiWords(hexData string) ([]string, error) {
+	data := strings.TrimPrefix(hexData, hexPrefix)
+	wordSizeHexChars := 2 * abiWordSizeBytes
+	if len(data) % wordSizeHexChars != 0 {
+		return nil, stacktrace.NewError("ABI-encoded data of %v hex characters isn't a whole number of words: '%v'", len(data), hexData)
+	}
+	words := []string{}
+	for wordStart := 0; wordStart < len(data); wordStart += wordSizeHexChars {
+		words = append(words, data[wordStart:wordStart + wordSizeHexChars])
+	}
+	return words, nil
+}
+
+func FormatHexQuantity(quantity uint64) string {
+	return hexPrefix + strconv.FormatUint(quantity, 16)
 }
\ No newline at end of file
@@ -0,0 +1,95 @@
+package geth
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEncodeFixedBytesWord(t *testing.T) {
+	testCases := []struct {
+		name string
+		hexData string
+		expectedWord string
+		isErrorExpected bool
+	}{
+		{
+			name:         "function selector",
+			hexData:      "0x4ab0d190",
+			expectedWord: "4ab0d190" + strings.Repeat("0", 56),
+		},
+		{
+			name:         "full word",
+			hexData:      "0x" + strings.Repeat("ab", 32),
+			expectedWord: strings.Repeat("ab", 32),
+		},
+		{
+			name:         "no prefix",
+			hexData:      "4ab0d190",
+			expectedWord: "4ab0d190" + strings.Repeat("0", 56),
+		},
+		{
+			name:            "longer than a word",
+			hexData:         "0x" + strings.Repeat("ab", 32) + "00",
+			isErrorExpected: true,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			word, err := EncodeFixedBytesWord(testCase.hexData)
+			if testCase.isErrorExpected {
+				if err == nil {
+					t.Fatalf("Expected encoding '%v' to fail, but got word '%v'", testCase.hexData, word)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Encoding '%v' failed: %v", testCase.hexData, err)
+			}
+			if word != testCase.expectedWord {
+				t.Fatalf("Expected word '%v', but got '%v'", testCase.expectedWord, word)
+			}
+		})
+	}
+}
+
+func TestEncodeAddressWord(t *testing.T) {
+	testCases := []struct {
+		name string
+		address string
+		expectedWord string
+		isErrorExpected bool
+	}{
+		{
+			name:         "checksummed address",
+			address:      "0x8A791620dd6260079BF849Dc5567aDC3F2FdC318",
+			expectedWord: strings.Repeat("0", 24) + "8a791620dd6260079bf849dc5567adc3f2fdc318",
+		},
+		{
+			name:         "full word",
+			address:      "0x" + strings.Repeat("ab", 32),
+			expectedWord: strings.Repeat("ab", 32),
+		},
+		{
+			name:            "longer than a word",
+			address:         "0x" + strings.Repeat("ab", 32) + "00",
+			isErrorExpected: true,
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			word, err := EncodeAddressWord(testCase.address)
+			if testCase.isErrorExpected {
+				if err == nil {
+					t.Fatalf("Expected encoding '%v' to fail, but got word '%v'", testCase.address, word)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Encoding '%v' failed: %v", testCase.address, err)
+			}
+			if word != testCase.expectedWord {
+				t.Fatalf("Expected word '%v', but got '%v'", testCase.expectedWord, word)
+			}
+		})
+	}
+}
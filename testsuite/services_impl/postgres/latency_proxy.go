@@ -0,0 +1,208 @@
+package postgres
+
+import (
+	"fmt"
+	"github.com/palantir/stacktrace"
+	"github.com/sirupsen/logrus"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	proxyNetwork = "tcp"
+	proxyBufferSize = 32 * 1024
+)
+
+/*
+	A TCP proxy running inside the testsuite container that sits between a client and Postgres, so tests can slow down
+	or sever the client's database traffic without touching Postgres itself. Clients connect to the address returned
+	by Start instead of the Postgres service's.
+ */
+type LatencyProxy struct {
+	targetAddress string
+
+	mutex       *sync.Mutex
+	latency     time.Duration
+	listener    net.Listener
+	connections map[net.Conn]bool
+}
+
+func NewLatencyProxy(targetHost string, targetPort int) *LatencyProxy {
+	return &LatencyProxy{
+		targetAddress: net.JoinHostPort(targetHost, strconv.Itoa(targetPort)),
+		mutex:         &sync.Mutex{},
+		connections:   map[net.Conn]bool{},
+	}
+}
+
+/*
+	Starts accepting connections on the testsuite container's address on the target's network, returning the host and
+	port clients should connect to.
+ */
+func (proxy *LatencyProxy) Start() (string, int, error) {
+	proxy.mutex.Lock()
+	defer proxy.mutex.Unlock()
+	if proxy.listener != nil {
+		return "", 0, stacktrace.NewError("Latency proxy to %v has already been started", proxy.targetAddress)
+	}
+
+	listenHost, err := getLocalIpAddressFor(proxy.targetAddress)
+	if err != nil {
+		return "", 0, stacktrace.Propagate(err, "An error occurred finding the testsuite's IP address on the network of %v", proxy.targetAddress)
+	}
+	listener, err := net.Listen(proxyNetwork, net.JoinHostPort(listenHost, "0"))
+	if err != nil {
+		return "", 0, stacktrace.Propagate(err, "An error occurred listening on %v", listenHost)
+	}
+	proxy.listener = listener
+	go proxy.acceptConnections(listener)
+
+	listenPort := listener.Addr().(*net.TCPAddr).Port
+	logrus.Debugf("Latency proxy listening on %v:%v, forwarding to %v", listenHost, listenPort, proxy.targetAddress)
+	return listenHost, listenPort, nil
+}
+
+/*
+	Sets the delay added to every chunk of data forwarded in either direction; zero forwards traffic untouched.
+ */
+func (proxy *LatencyProxy) SetLatency(latency time.Duration) {
+	proxy.mutex.Lock()
+	defer proxy.mutex.Unlock()
+	proxy.latency = latency
+}
+
+/*
+	Stops accepting connections and closes every open one.
+ */
+func (proxy *LatencyProxy) Close() error {
+	proxy.mutex.Lock()
+	defer proxy.mutex.Unlock()
+	if proxy.listener == nil {
+		return nil
+	}
+	err := proxy.listener.Close()
+	proxy.listener = nil
+	for conn := range proxy.connections {
+		conn.Close()
+	}
+	proxy.connections = map[net.Conn]bool{}
+	if err != nil {
+		return stacktrace.Propagate(err, "An error occurred closing the latency proxy's listener")
+	}
+	return nil
+}
+
+// ==========================================================================================
+//								Helper methods
+// ==========================================================================================
+
+func (proxy *LatencyProxy) acceptConnections(listener net.Listener) {
+	for {
+		clientConn, err := listener.Accept()
+		if err != nil {
+			// The listener was closed
+			return
+		}
+		go proxy.handleConnection(clientConn)
+	}
+}
+
+func (proxy *LatencyProxy) handleConnection(clientConn net.Conn) {
+	targetConn, err := net.Dial(proxyNetwork, proxy.targetAddress)
+	if err != nil {
+		logrus.Debugf("Latency proxy couldn't connect to %v: %v", proxy.targetAddress, err)
+		clientConn.Close()
+		return
+	}
+	isClientTracked := proxy.trackConnection(clientConn, true)
+	isTargetTracked := proxy.trackConnection(targetConn, true)
+	if !isClientTracked || !isTargetTracked {
+		// The proxy was closed while this connection was being set up
+		proxy.trackConnection(clientConn, false)
+		proxy.trackConnection(targetConn, false)
+		clientConn.Close()
+		targetConn.Close()
+		return
+	}
+	defer proxy.trackConnection(clientConn, false)
+	defer proxy.trackConnection(targetConn, false)
+
+	waitGroup := &sync.WaitGroup{}
+	waitGroup.Add(2)
+	go proxy.forward(clientConn, targetConn, waitGroup)
+	go proxy.forward(targetConn, clientConn, waitGroup)
+	waitGroup.Wait()
+}
+
+/*
+	Copies from source to dest until either side closes, then closes both so the other direction stops too.
+ */
+func (proxy *LatencyProxy) forward(source net.Conn, dest net.Conn, waitGroup *sync.WaitGroup) {
+	defer waitGroup.Done()
+	defer source.Close()
+	defer dest.Close()
+
+	buffer := make([]byte, proxyBufferSize)
+	for {
+		numBytesRead, err := source.Read(buffer)
+		if numBytesRead > 0 {
+			if latency := proxy.getLatency(); latency > 0 {
+				time.Sleep(latency)
+			}
+			if _, writeErr := dest.Write(buffer[:numBytesRead]); writeErr != nil {
+				return
+			}
+		}
+		if err != nil {
+			if err != io.EOF {
+				logrus.Tracef("Latency proxy connection to %v closed: %v", proxy.targetAddress, err)
+			}
+			return
+		}
+	}
+}
+
+func (proxy *LatencyProxy) getLatency() time.Duration {
+	proxy.mutex.Lock()
+	defer proxy.mutex.Unlock()
+	return proxy.latency
+}
+
+/*
+	Adds or removes a connection from the ones Close closes. A connection opened after the proxy was closed is closed
+	right away instead, since Close won't get to it; returns whether the connection is still open.
+ */
+func (proxy *LatencyProxy) trackConnection(conn net.Conn, isOpen bool) bool {
+	proxy.mutex.Lock()
+	defer proxy.mutex.Unlock()
+	if !isOpen {
+		delete(proxy.connections, conn)
+		return false
+	}
+	if proxy.listener == nil {
+		conn.Close()
+		return false
+	}
+	proxy.connections[conn] = true
+	return true
+}
+
+/*
+	Finds the local IP address traffic to the given address would leave from. Dialing UDP doesn't send any packets,
+	it just picks the route.
+ */
+func getLocalIpAddressFor(address string) (string, error) {
+	conn, err := net.Dial("udp", address)
+	if err != nil {
+		return "", stacktrace.Propagate(err, "An error occurred finding a route to %v", address)
+	}
+	defer conn.Close()
+	localAddr, ok := conn.LocalAddr().(*net.UDPAddr)
+	if !ok {
+		return "", stacktrace.NewError("Expected a UDP local address but got %v", fmt.Sprintf("%T", conn.LocalAddr()))
+	}
+	return localAddr.IP.String(), nil
+}
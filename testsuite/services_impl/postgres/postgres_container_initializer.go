@@ -10,6 +10,7 @@ import (
 const (
 	entrypointScriptPath = "/docker-entrypoint.sh"
 	postgresSuperUserPasswordEnvVar = "POSTGRES_PASSWORD"
+	restartDelaySeconds = 1
 )
 
 type PostgresContainerInitializer struct {
@@ -33,7 +34,7 @@ func (initializer PostgresContainerInitializer) GetUsedPorts() map[string]bool {
 }
 
 func (initializer PostgresContainerInitializer) GetService(ctx *services.ServiceContext) services.Service {
-	return NewPostgresService(ctx);
+	return NewPostgresService(ctx, port);
 }
 
 func (initializer PostgresContainerInitializer) GetFilesToGenerate() map[string]bool {
@@ -57,16 +58,19 @@ func (initializer PostgresContainerInitializer) GetTestVolumeMountpoint() string
 }
 
 func (initializer PostgresContainerInitializer) GetStartCommandOverrides(mountedFileFilepaths map[string]string, ipPlaceholder string) (entrypointArgs []string, cmdArgs []string, resultErr error) {
+	// Postgres runs under a loop that starts it again whenever it exits, so tests can restart the database
+	// (e.g. with pg_ctl) without the container stopping
 	entrypointArgs = []string{
 		"/bin/bash",
 		"-c",
-		fmt.Sprintf("%v=%v %v -d %v -h %v -p %v",
+		fmt.Sprintf("while true; do %v=%v %v -d %v -h %v -p %v; sleep %v; done",
 			postgresSuperUserPasswordEnvVar,
 			postgresSuperUserPassword,
 			entrypointScriptPath,
 			databaseName,
 			ipPlaceholder,
-			port),
+			port,
+			restartDelaySeconds),
 	}
 
 	return entrypointArgs, nil, nil
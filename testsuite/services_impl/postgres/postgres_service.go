@@ -3,9 +3,13 @@ package postgres
 import (
 	"database/sql"
 	"fmt"
-	"github.com/kurtosis-tech/kurtosis-libs/golang/lib/services"
-
-	_ "github.com/lib/pq"
+	"github.com/kurtosistech/chainlink-testing/testsuite/services_impl/geth"
+	"github.com/kurtosistech/chainlink-testing/testsuite/services_impl/service_context"
+	"github.com/lib/pq"
+	"github.com/palantir/stacktrace"
+	"path"
+	"regexp"
+	"time"
 )
 
 const (
@@ -15,14 +19,46 @@ const (
 	postgresSuperUsername = "postgres"
 
 	postgresSuperUserPassword = "password"
+
+	tableDumpFileExtension = ".csv"
+
+	// Where the postgres image keeps its data directory, which pg_ctl needs to find the running server
+	postgresDataDirpath = "/var/lib/postgresql/data"
+
+	waitForRestartMaxPolls = 30
+	waitForRestartPollIntervalSeconds = 1
 )
 
+// Database and role names are interpolated into SQL (Postgres can't parameterize identifiers), so we keep them simple
+var identifierRegex = regexp.MustCompile("^[a-z_][a-z0-9_]*$")
+
 type PostgresService struct {
-	serviceCtx *services.ServiceContext
+	serviceCtx service_context.ServiceContext
+	port       int
 }
 
-func NewPostgresService(serviceCtx *services.ServiceContext) *PostgresService {
-	return &PostgresService{serviceCtx: serviceCtx}
+/*
+	Everything a client needs to connect to one database on the Postgres service as one role.
+ */
+type DatabaseCredentials struct {
+	Host string
+	Port int
+	DatabaseName string
+	Username string
+	Password string
+}
+
+func (credentials DatabaseCredentials) GetConnectionUrl() string {
+	return fmt.Sprintf("postgresql://%v:%v@%v:%v/%v?sslmode=disable",
+		credentials.Username,
+		credentials.Password,
+		credentials.Host,
+		credentials.Port,
+		credentials.DatabaseName)
+}
+
+func NewPostgresService(serviceCtx service_context.ServiceContext, port int) *PostgresService {
+	return &PostgresService{serviceCtx: serviceCtx, port: port}
 }
 
 func (postgresService PostgresService) GetSuperUsername() string {
@@ -38,24 +74,283 @@ func (postgresService PostgresService) GetDatabaseName() string {
 }
 
 func (postgresService PostgresService) GetPort() int {
-	return port
+	return postgresService.port
 }
 
 func (postgresService PostgresService) GetIPAddress() string {
 	return postgresService.serviceCtx.GetIPAddress()
 }
 
+func (postgresService PostgresService) GetSuperUserCredentials() DatabaseCredentials {
+	return DatabaseCredentials{
+		Host:         postgresService.GetIPAddress(),
+		Port:         postgresService.port,
+		DatabaseName: databaseName,
+		Username:     postgresSuperUsername,
+		Password:     postgresSuperUserPassword,
+	}
+}
+
+/*
+	Creates a login role and a database owned by it, so that a consumer (e.g. one of several oracles sharing this
+	Postgres service) gets a database of its own and can't touch anybody else's.
+ */
+func (postgresService PostgresService) CreateDatabaseWithOwner(dbName string, username string, password string) (DatabaseCredentials, error) {
+	if !identifierRegex.MatchString(dbName) {
+		return DatabaseCredentials{}, stacktrace.NewError("Database name '%v' doesn't match %v", dbName, identifierRegex.String())
+	}
+	if !identifierRegex.MatchString(username) {
+		return DatabaseCredentials{}, stacktrace.NewError("Username '%v' doesn't match %v", username, identifierRegex.String())
+	}
+	createRoleStatement := fmt.Sprintf("CREATE ROLE %v WITH LOGIN PASSWORD %v", pq.QuoteIdentifier(username), pq.QuoteLiteral(password))
+	if _, err := postgresService.Exec(databaseName, createRoleStatement); err != nil {
+		return DatabaseCredentials{}, stacktrace.Propagate(err, "An error occurred creating role %v", username)
+	}
+	// CREATE DATABASE can't run inside a transaction, so this has to be its own statement
+	createDatabaseStatement := fmt.Sprintf("CREATE DATABASE %v WITH OWNER %v", pq.QuoteIdentifier(dbName), pq.QuoteIdentifier(username))
+	if _, err := postgresService.Exec(databaseName, createDatabaseStatement); err != nil {
+		return DatabaseCredentials{}, stacktrace.Propagate(err, "An error occurred creating database %v", dbName)
+	}
+	// By default every role can connect to every database, so we take that away from everybody but the owner
+	revokeStatement := fmt.Sprintf("REVOKE ALL ON DATABASE %v FROM PUBLIC", pq.QuoteIdentifier(dbName))
+	if _, err := postgresService.Exec(databaseName, revokeStatement); err != nil {
+		return DatabaseCredentials{}, stacktrace.Propagate(err, "An error occurred revoking public access to database %v", dbName)
+	}
+	return DatabaseCredentials{
+		Host:         postgresService.GetIPAddress(),
+		Port:         postgresService.port,
+		DatabaseName: dbName,
+		Username:     username,
+		Password:     password,
+	}, nil
+}
+
+/*
+	Runs a query against the given database as the superuser, returning each row as a map of column name -> value.
+	Text and bytea values come back as strings.
+ */
+func (postgresService PostgresService) Query(dbName string, query string, args ...interface{}) ([]map[string]interface{}, error) {
+	db, err := postgresService.openDatabase(dbName)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "An error occurred opening database %v", dbName)
+	}
+	defer db.Close()
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "An error occurred running query '%v' on database %v", query, dbName)
+	}
+	defer rows.Close()
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "An error occurred getting the columns of query '%v'", query)
+	}
+
+	result := []map[string]interface{}{}
+	for rows.Next() {
+		values := make([]interface{}, len(columns))
+		valuePtrs := make([]interface{}, len(columns))
+		for idx := range values {
+			valuePtrs[idx] = &values[idx]
+		}
+		if err := rows.Scan(valuePtrs...); err != nil {
+			return nil, stacktrace.Propagate(err, "An error occurred scanning a row of query '%v'", query)
+		}
+		row := map[string]interface{}{}
+		for idx, column := range columns {
+			if bytesValue, isBytes := values[idx].([]byte); isBytes {
+				row[column] = string(bytesValue)
+			} else {
+				row[column] = values[idx]
+			}
+		}
+		result = append(result, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, stacktrace.Propagate(err, "An error occurred iterating over the rows of query '%v'", query)
+	}
+	return result, nil
+}
+
+/*
+	Runs a single value query, e.g. a COUNT(*), against the given database as the superuser.
+ */
+func (postgresService PostgresService) QueryInt(dbName string, query string, args ...interface{}) (int64, error) {
+	db, err := postgresService.openDatabase(dbName)
+	if err != nil {
+		return 0, stacktrace.Propagate(err, "An error occurred opening database %v", dbName)
+	}
+	defer db.Close()
+
+	var result int64
+	if err := db.QueryRow(query, args...).Scan(&result); err != nil {
+		return 0, stacktrace.Propagate(err, "An error occurred running query '%v' on database %v", query, dbName)
+	}
+	return result, nil
+}
+
+/*
+	Runs a statement against the given database as the superuser, returning the number of rows it affected.
+ */
+func (postgresService PostgresService) Exec(dbName string, statement string, args ...interface{}) (int64, error) {
+	db, err := postgresService.openDatabase(dbName)
+	if err != nil {
+		return 0, stacktrace.Propagate(err, "An error occurred opening database %v", dbName)
+	}
+	defer db.Close()
+
+	result, err := db.Exec(statement, args...)
+	if err != nil {
+		return 0, stacktrace.Propagate(err, "An error occurred running statement '%v' on database %v", statement, dbName)
+	}
+	numRowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, stacktrace.Propagate(err, "An error occurred getting the rows affected by statement '%v'", statement)
+	}
+	return numRowsAffected, nil
+}
+
+/*
+	Dumps a table to a CSV file in the given directory, relative to the root of the test volume. The dump is done by
+	psql inside the container, so it works even if the table is too large to shuttle back to the testsuite.
+ */
+func (postgresService PostgresService) DumpTableToTestVolume(dbName string, tableName string, relativeDirpath string) error {
+	// Both go into a shell command as well as SQL, so anything past a plain identifier could run arbitrary commands
+	if !identifierRegex.MatchString(dbName) {
+		return stacktrace.NewError("Database name '%v' doesn't match %v", dbName, identifierRegex.String())
+	}
+	if !identifierRegex.MatchString(tableName) {
+		return stacktrace.NewError("Table name '%v' doesn't match %v", tableName, identifierRegex.String())
+	}
+	destDirpath := path.Join(geth.TestVolumeMountpoint, relativeDirpath)
+	destFilepath := path.Join(destDirpath, tableName + tableDumpFileExtension)
+	dumpCommand := []string{
+		"/bin/sh",
+		"-c",
+		fmt.Sprintf("mkdir -p %v && PGPASSWORD=%v psql -h %v -p %v -U %v -d %v -c \"\\copy (SELECT * FROM %v) TO '%v' WITH CSV HEADER\"",
+			destDirpath,
+			postgresSuperUserPassword,
+			postgresService.GetIPAddress(),
+			postgresService.port,
+			postgresSuperUsername,
+			dbName,
+			tableName,
+			destFilepath),
+	}
+	exitCode, logOutput, err := postgresService.serviceCtx.ExecCommand(dumpCommand)
+	if err != nil {
+		return stacktrace.Propagate(err, "Failed to execute command to dump table %v.", tableName)
+	}
+	if exitCode != 0 {
+		return stacktrace.NewError("Dumping table %v exited with code %v: %v", tableName, exitCode, string(*logOutput))
+	}
+	return nil
+}
+
+/*
+	Freezes every Postgres process with SIGSTOP, so clients' connections stay open but none of their queries are
+	answered until Resume is called.
+ */
+func (postgresService PostgresService) Pause() error {
+	if err := postgresService.signalPostgresProcesses("STOP"); err != nil {
+		return stacktrace.Propagate(err, "An error occurred pausing Postgres")
+	}
+	return nil
+}
+
+/*
+	Unfreezes the Postgres processes frozen by Pause.
+ */
+func (postgresService PostgresService) Resume() error {
+	if err := postgresService.signalPostgresProcesses("CONT"); err != nil {
+		return stacktrace.Propagate(err, "An error occurred resuming Postgres")
+	}
+	return nil
+}
+
+/*
+	Shuts Postgres down, dropping every client connection, and waits for the container's start loop to bring it
+	back up and accept connections again.
+ */
+func (postgresService PostgresService) Restart() error {
+	stopCommand := []string{
+		"/bin/sh",
+		"-c",
+		fmt.Sprintf("gosu %v pg_ctl stop -D %v -m fast", postgresSuperUsername, postgresDataDirpath),
+	}
+	exitCode, logOutput, err := postgresService.serviceCtx.ExecCommand(stopCommand)
+	if err != nil {
+		return stacktrace.Propagate(err, "Failed to execute command to stop Postgres.")
+	}
+	if exitCode != 0 {
+		return stacktrace.NewError("Stopping Postgres exited with code %v: %v", exitCode, string(*logOutput))
+	}
+	for i := 0; i < waitForRestartMaxPolls; i++ {
+		if postgresService.IsAvailable() {
+			return nil
+		}
+		time.Sleep(waitForRestartPollIntervalSeconds * time.Second)
+	}
+	return stacktrace.NewError("Postgres wasn't available again %v seconds after being stopped",
+		waitForRestartMaxPolls * waitForRestartPollIntervalSeconds)
+}
+
+/*
+	Kills every client connection to the given database, returning how many were killed. Postgres itself stays up,
+	so clients can reconnect straight away.
+ */
+func (postgresService PostgresService) TerminateConnections(dbName string) (int64, error) {
+	numTerminated, err := postgresService.QueryInt(
+		databaseName,
+		"SELECT COUNT(pg_terminate_backend(pid)) FROM pg_stat_activity WHERE datname = $1 AND pid <> pg_backend_pid()",
+		dbName)
+	if err != nil {
+		return 0, stacktrace.Propagate(err, "An error occurred terminating the connections to database %v", dbName)
+	}
+	return numTerminated, nil
+}
+
 // ===========================================================================================
 //                              Service interface methods
 // ===========================================================================================
 
 func (postgresService PostgresService) IsAvailable() bool {
-	ipAddress := postgresService.serviceCtx.GetIPAddress()
-	connStr := fmt.Sprintf("postgres://%v:%v@%v/%v?sslmode=disable", postgresSuperUsername, postgresSuperUserPassword, ipAddress, databaseName)
-	db, err := sql.Open(postgresDriverName, connStr)
+	db, err := postgresService.openDatabase(databaseName)
 	if err != nil {
 		return false
 	}
+	defer db.Close()
 	err = db.Ping()
-	return err != nil
+	return err == nil
+}
+
+// ==========================================================================================
+//								Helper methods
+// ==========================================================================================
+
+func (postgresService PostgresService) openDatabase(dbName string) (*sql.DB, error) {
+	credentials := postgresService.GetSuperUserCredentials()
+	credentials.DatabaseName = dbName
+	db, err := sql.Open(postgresDriverName, credentials.GetConnectionUrl())
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "An error occurred opening a connection to database %v", dbName)
+	}
+	return db, nil
+}
+
+func (postgresService PostgresService) signalPostgresProcesses(signal string) error {
+	// The image has no pkill, so we find the Postgres processes (the postmaster and every backend) by name ourselves
+	signalCommand := []string{
+		"/bin/sh",
+		"-c",
+		fmt.Sprintf("for dir in /proc/[0-9]*; do if [ \"$(cat $dir/comm 2>/dev/null)\" = postgres ]; then kill -%v ${dir#/proc/} || true; fi; done", signal),
+	}
+	exitCode, logOutput, err := postgresService.serviceCtx.ExecCommand(signalCommand)
+	if err != nil {
+		return stacktrace.Propagate(err, "Failed to execute command to send SIG%v to the Postgres processes.", signal)
+	}
+	if exitCode != 0 {
+		return stacktrace.NewError("Sending SIG%v to the Postgres processes exited with code %v: %v", signal, exitCode, string(*logOutput))
+	}
+	return nil
 }
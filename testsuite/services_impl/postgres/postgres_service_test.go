@@ -0,0 +1,53 @@
+package postgres
+
+import (
+	"github.com/kurtosistech/chainlink-testing/testsuite/services_impl/testutil"
+	"strings"
+	"testing"
+)
+
+const (
+	testPostgresServiceId = "postgres"
+	testPostgresIpAddress = "172.23.0.5"
+	testPostgresPort = 5432
+)
+
+func TestDumpTableToTestVolumeValidatesIdentifiers(t *testing.T) {
+	testCases := []struct {
+		name string
+		dbName string
+		tableName string
+		isErrorExpected bool
+	}{
+		{name: "valid names", dbName: "chainlink_oracle", tableName: "job_runs"},
+		{name: "database name with a shell command", dbName: "chainlink; rm -rf /", tableName: "job_runs", isErrorExpected: true},
+		{name: "table name with a quote", dbName: "chainlink_oracle", tableName: `job_runs" && echo "`, isErrorExpected: true},
+		{name: "table name with a subquery", dbName: "chainlink_oracle", tableName: "(SELECT 1)", isErrorExpected: true},
+		{name: "empty table name", dbName: "chainlink_oracle", tableName: "", isErrorExpected: true},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			serviceCtx := testutil.NewFakeServiceContext(testPostgresServiceId, testPostgresIpAddress)
+			postgresService := NewPostgresService(serviceCtx, testPostgresPort)
+
+			err := postgresService.DumpTableToTestVolume(testCase.dbName, testCase.tableName, "artifacts")
+
+			executedCommands := serviceCtx.GetExecutedCommands()
+			if testCase.isErrorExpected {
+				if err == nil {
+					t.Fatal("Expected the names to be rejected, but they weren't")
+				}
+				if len(executedCommands) != 0 {
+					t.Fatalf("Expected no command to run for rejected names, but got %v", executedCommands)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Dumping the table failed: %v", err)
+			}
+			if len(executedCommands) != 1 || !strings.Contains(strings.Join(executedCommands[0], " "), "SELECT * FROM " + testCase.tableName) {
+				t.Fatalf("Expected a single command dumping table %v, but got %v", testCase.tableName, executedCommands)
+			}
+		})
+	}
+}
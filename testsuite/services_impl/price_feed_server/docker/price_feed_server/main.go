@@ -5,6 +5,7 @@ import (
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 	"net/http"
+	"sync"
 )
 
 const (
@@ -16,6 +17,19 @@ type PriceFeedResponse struct {
 	Usd float32 `json:"USD"`
 }
 
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// Body of requests to the failing endpoint
+type FailingRequest struct {
+	Failing bool `json:"failing"`
+}
+
+// Whether the price endpoint answers with errors instead of the price, so tests can keep jobs from succeeding
+var isFailing = false
+var isFailingMutex = &sync.RWMutex{}
+
 func main() {
 	// Echo instance
 	e := echo.New()
@@ -26,6 +40,7 @@ func main() {
 
 	// Routes
 	e.GET("/", standardJsonResponse)
+	e.PUT("/failing", setFailing)
 
 	// Start server
 	e.Logger.Fatal(e.Start(fmt.Sprintf(":%v", httpPort)))
@@ -33,8 +48,24 @@ func main() {
 
 // Handler
 func standardJsonResponse(c echo.Context) error {
+	isFailingMutex.RLock()
+	defer isFailingMutex.RUnlock()
+	if isFailing {
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "price feed is set to fail"})
+	}
 	response := PriceFeedResponse{
 		Usd: sampleUsdResponse,
 	}
 	return c.JSON(http.StatusOK, response)
-}
\ No newline at end of file
+}
+
+func setFailing(c echo.Context) error {
+	request := new(FailingRequest)
+	if err := c.Bind(request); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+	}
+	isFailingMutex.Lock()
+	defer isFailingMutex.Unlock()
+	isFailing = request.Failing
+	return c.NoContent(http.StatusNoContent)
+}
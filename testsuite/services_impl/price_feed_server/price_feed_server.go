@@ -1,8 +1,13 @@
 package price_feed_server
 
 import (
-	"github.com/kurtosis-tech/kurtosis-libs/golang/lib/services"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/kurtosistech/chainlink-testing/testsuite/services_impl/service_context"
+	"github.com/palantir/stacktrace"
 	"net"
+	"net/http"
 	"strconv"
 	"time"
 )
@@ -10,14 +15,24 @@ import (
 const (
 	httpPort = 1323
 	isAvailableDialTimeout = 5 * time.Second
+	failingPath = "failing"
+	requestTimeout = 10 * time.Second
+
+	// The USD price the server image always answers with
+	SampleUsdPrice = 1675.58
 )
 
 type PriceFeedServer struct {
-	serviceCtx *services.ServiceContext
+	serviceCtx service_context.ServiceContext
+	httpPort   int
+}
+
+type failingRequest struct {
+	Failing bool `json:"failing"`
 }
 
-func NewPriceFeedServerService(serviceCtx *services.ServiceContext) *PriceFeedServer {
-	return &PriceFeedServer{serviceCtx: serviceCtx}
+func NewPriceFeedServerService(serviceCtx service_context.ServiceContext, httpPort int) *PriceFeedServer {
+	return &PriceFeedServer{serviceCtx: serviceCtx, httpPort: httpPort}
 }
 
 func (priceFeedServer PriceFeedServer) GetIPAddress() string {
@@ -25,7 +40,35 @@ func (priceFeedServer PriceFeedServer) GetIPAddress() string {
 }
 
 func (priceFeedServer PriceFeedServer) GetHTTPPort() int {
-	return httpPort
+	return priceFeedServer.httpPort
+}
+
+/*
+	Sets whether the server answers price requests with errors instead of the price, e.g. to keep the Oracle from
+	fulfilling requests.
+ */
+func (priceFeedServer PriceFeedServer) SetFailing(isFailing bool) error {
+	requestBody, err := json.Marshal(failingRequest{Failing: isFailing})
+	if err != nil {
+		return stacktrace.Propagate(err, "Failed to serialize the failing request.")
+	}
+	url := fmt.Sprintf("http://%v:%v/%v", priceFeedServer.GetIPAddress(), priceFeedServer.httpPort, failingPath)
+	request, err := http.NewRequest(http.MethodPut, url, bytes.NewBuffer(requestBody))
+	if err != nil {
+		return stacktrace.Propagate(err, "Failed to build the request to %v.", url)
+	}
+	request.Header.Set("Content-Type", "application/json")
+	client := &http.Client{Timeout: requestTimeout}
+	response, err := client.Do(request)
+	if err != nil {
+		return stacktrace.Propagate(err, "Failed to set the price feed server's failing flag to %v.", isFailing)
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusNoContent {
+		return stacktrace.NewError("Setting the price feed server's failing flag to %v returned status code %v.",
+			isFailing, response.StatusCode)
+	}
+	return nil
 }
 
 // ===========================================================================================
@@ -34,7 +77,7 @@ func (priceFeedServer PriceFeedServer) GetHTTPPort() int {
 
 func (priceFeedServer PriceFeedServer) IsAvailable() bool {
 	conn, err := net.DialTimeout("tcp",
-		net.JoinHostPort(priceFeedServer.GetIPAddress(), strconv.Itoa(httpPort)), isAvailableDialTimeout)
+		net.JoinHostPort(priceFeedServer.GetIPAddress(), strconv.Itoa(priceFeedServer.httpPort)), isAvailableDialTimeout)
 	if err != nil {
 		return false
 	}
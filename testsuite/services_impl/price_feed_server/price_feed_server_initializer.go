@@ -28,7 +28,7 @@ func (initializer PriceFeedServerInitializer) GetUsedPorts() map[string]bool {
 }
 
 func (initializer PriceFeedServerInitializer) GetService(ctx *services.ServiceContext) services.Service {
-	return NewPriceFeedServerService(ctx);
+	return NewPriceFeedServerService(ctx, httpPort);
 }
 
 func (initializer PriceFeedServerInitializer) GetFilesToGenerate() map[string]bool {
@@ -0,0 +1,21 @@
+package service_context
+
+import (
+	"github.com/kurtosis-tech/kurtosis-libs/golang/lib/services"
+)
+
+/*
+	The parts of a Kurtosis service context that our services use. Depending on this rather than the Kurtosis struct
+	lets services be pointed at testutil.FakeServiceContext, so their logic can run without a live container.
+ */
+type ServiceContext interface {
+	GetServiceID() services.ServiceID
+
+	GetIPAddress() string
+
+	// Runs the command on the service's container, returning its exit code and combined output
+	ExecCommand(command []string) (int32, *[]byte, error)
+}
+
+// The real Kurtosis service context is what services get in the testsuite
+var _ ServiceContext = (*services.ServiceContext)(nil)
@@ -0,0 +1,207 @@
+package testutil
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/kurtosis-tech/kurtosis-libs/golang/lib/services"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+)
+
+const (
+	// Error code geth answers JSON-RPC calls to unknown methods with
+	methodNotFoundErrorCode = -32601
+	// Error code the fake answers calls it was told to fail with
+	scriptedErrorCode = -32000
+
+	adminNodeInfoMethod = "admin_nodeInfo"
+	adminAddPeerMethod = "admin_addPeer"
+	adminPeersMethod = "admin_peers"
+	personalUnlockAccountMethod = "personal_unlockAccount"
+	ethSendTransactionMethod = "eth_sendTransaction"
+)
+
+type fakeJsonRpcRequest struct {
+	Method string `json:"method"`
+	Params []json.RawMessage `json:"params"`
+	Id int `json:"id"`
+}
+
+type fakeJsonRpcResponse struct {
+	JsonRpc string `json:"jsonrpc"`
+	Id int `json:"id"`
+	Result interface{} `json:"result"`
+	Error *fakeJsonRpcError `json:"error,omitempty"`
+}
+
+type fakeJsonRpcError struct {
+	Code int `json:"code"`
+	Message string `json:"message"`
+}
+
+// The fields of a geth admin_peers entry that the testsuite reads
+type fakePeer struct {
+	Enode string `json:"enode"`
+	Id string `json:"id"`
+}
+
+/*
+	Stand-in for a geth node's JSON-RPC endpoint, answering the admin calls used to connect peers and the calls the
+	transaction load generator makes. Peers added with admin_addPeer are listed by admin_peers, and every account
+	unlocks. Safe for concurrent use.
+ */
+type FakeGethRpcServer struct {
+	server *httptest.Server
+	enode string
+
+	// Mutex protecting all the state below
+	mutex *sync.Mutex
+	peerEnodes []string
+	numSentTransactions int
+	// Method -> result returned instead of the default one
+	methodResults map[string]interface{}
+	// Method -> message of the error returned instead of a result
+	methodErrors map[string]string
+}
+
+/*
+	Starts the server on a local port, which keeps serving until Close is called. admin_nodeInfo reports the given enode.
+ */
+func NewFakeGethRpcServer(enode string) *FakeGethRpcServer {
+	fake := &FakeGethRpcServer{
+		enode:         enode,
+		mutex:         &sync.Mutex{},
+		peerEnodes:    []string{},
+		methodResults: map[string]interface{}{},
+		methodErrors:  map[string]string{},
+	}
+	fake.server = httptest.NewServer(http.HandlerFunc(fake.handleRpcCall))
+	return fake
+}
+
+func (fake *FakeGethRpcServer) Close() {
+	fake.server.Close()
+}
+
+func (fake *FakeGethRpcServer) GetIPAddress() string {
+	host, _, _ := net.SplitHostPort(fake.server.Listener.Addr().String())
+	return host
+}
+
+func (fake *FakeGethRpcServer) GetPort() int {
+	_, portStr, _ := net.SplitHostPort(fake.server.Listener.Addr().String())
+	port, _ := strconv.Atoi(portStr)
+	return port
+}
+
+/*
+	A service context with the server's IP address, to build a geth service that talks to this server on GetPort.
+ */
+func (fake *FakeGethRpcServer) NewServiceContext(serviceId services.ServiceID) *FakeServiceContext {
+	return NewFakeServiceContext(serviceId, fake.GetIPAddress())
+}
+
+/*
+	Makes the method return the given result from now on, instead of its default one.
+ */
+func (fake *FakeGethRpcServer) SetMethodResult(method string, result interface{}) {
+	fake.mutex.Lock()
+	defer fake.mutex.Unlock()
+	fake.methodResults[method] = result
+}
+
+/*
+	Makes the method return a JSON-RPC error with the given message from now on.
+ */
+func (fake *FakeGethRpcServer) SetMethodError(method string, message string) {
+	fake.mutex.Lock()
+	defer fake.mutex.Unlock()
+	fake.methodErrors[method] = message
+}
+
+/*
+	Enodes added with admin_addPeer, in the order they were first added.
+ */
+func (fake *FakeGethRpcServer) GetPeerEnodes() []string {
+	fake.mutex.Lock()
+	defer fake.mutex.Unlock()
+	return append([]string{}, fake.peerEnodes...)
+}
+
+func (fake *FakeGethRpcServer) GetNumSentTransactions() int {
+	fake.mutex.Lock()
+	defer fake.mutex.Unlock()
+	return fake.numSentTransactions
+}
+
+// ===========================================================================================
+//                                  Helper methods
+// ===========================================================================================
+
+func (fake *FakeGethRpcServer) handleRpcCall(writer http.ResponseWriter, request *http.Request) {
+	var rpcRequest fakeJsonRpcRequest
+	if err := json.NewDecoder(request.Body).Decode(&rpcRequest); err != nil {
+		http.Error(writer, fmt.Sprintf("Couldn't parse JSON-RPC request: %v", err), http.StatusBadRequest)
+		return
+	}
+	result, rpcErr := fake.getResult(rpcRequest)
+	writer.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(writer).Encode(fakeJsonRpcResponse{
+		JsonRpc: "2.0",
+		Id:      rpcRequest.Id,
+		Result:  result,
+		Error:   rpcErr,
+	})
+}
+
+func (fake *FakeGethRpcServer) getResult(rpcRequest fakeJsonRpcRequest) (interface{}, *fakeJsonRpcError) {
+	fake.mutex.Lock()
+	defer fake.mutex.Unlock()
+	if message, found := fake.methodErrors[rpcRequest.Method]; found {
+		return nil, &fakeJsonRpcError{Code: scriptedErrorCode, Message: message}
+	}
+	if result, found := fake.methodResults[rpcRequest.Method]; found {
+		return result, nil
+	}
+	switch rpcRequest.Method {
+	case adminNodeInfoMethod:
+		return map[string]string{"enode": fake.enode}, nil
+	case adminAddPeerMethod:
+		var peerEnode string
+		if len(rpcRequest.Params) != 1 || json.Unmarshal(rpcRequest.Params[0], &peerEnode) != nil {
+			return nil, &fakeJsonRpcError{Code: scriptedErrorCode, Message: "admin_addPeer takes one enode"}
+		}
+		if !containsString(fake.peerEnodes, peerEnode) {
+			fake.peerEnodes = append(fake.peerEnodes, peerEnode)
+		}
+		return true, nil
+	case adminPeersMethod:
+		peers := []fakePeer{}
+		for idx, peerEnode := range fake.peerEnodes {
+			peers = append(peers, fakePeer{Enode: peerEnode, Id: strconv.Itoa(idx)})
+		}
+		return peers, nil
+	case personalUnlockAccountMethod:
+		return true, nil
+	case ethSendTransactionMethod:
+		fake.numSentTransactions++
+		return fmt.Sprintf("0x%064x", fake.numSentTransactions), nil
+	default:
+		return nil, &fakeJsonRpcError{
+			Code:    methodNotFoundErrorCode,
+			Message: fmt.Sprintf("the method %v does not exist/is not available", rpcRequest.Method),
+		}
+	}
+}
+
+func containsString(values []string, target string) bool {
+	for _, value := range values {
+		if value == target {
+			return true
+		}
+	}
+	return false
+}
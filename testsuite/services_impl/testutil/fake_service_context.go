@@ -0,0 +1,88 @@
+package testutil
+
+import (
+	"github.com/kurtosis-tech/kurtosis-libs/golang/lib/services"
+	"github.com/kurtosistech/chainlink-testing/testsuite/services_impl/service_context"
+	"sync"
+)
+
+/*
+	Handles a command run on a FakeServiceContext, returning the exit code, output and error ExecCommand should return.
+ */
+type CommandHandler func(command []string) (int32, string, error)
+
+/*
+	In-memory ServiceContext with a fixed ID and IP address, whose commands are answered by a scripted handler rather
+	than run on a container. Safe for concurrent use.
+ */
+type FakeServiceContext struct {
+	serviceId services.ServiceID
+	ipAddress string
+
+	// Mutex protecting the handler and the executed commands
+	mutex            *sync.Mutex
+	commandHandler   CommandHandler
+	executedCommands [][]string
+}
+
+/*
+	Creates a fake whose commands all succeed with no output, until a handler is set with SetCommandHandler.
+ */
+func NewFakeServiceContext(serviceId services.ServiceID, ipAddress string) *FakeServiceContext {
+	return &FakeServiceContext{
+		serviceId: serviceId,
+		ipAddress: ipAddress,
+		mutex:     &sync.Mutex{},
+		commandHandler: func(command []string) (int32, string, error) {
+			return 0, "", nil
+		},
+		executedCommands: [][]string{},
+	}
+}
+
+func (fake *FakeServiceContext) SetCommandHandler(handler CommandHandler) {
+	fake.mutex.Lock()
+	defer fake.mutex.Unlock()
+	fake.commandHandler = handler
+}
+
+/*
+	Every command ExecCommand was called with, in the order they were called.
+ */
+func (fake *FakeServiceContext) GetExecutedCommands() [][]string {
+	fake.mutex.Lock()
+	defer fake.mutex.Unlock()
+	executedCommands := [][]string{}
+	for _, command := range fake.executedCommands {
+		executedCommands = append(executedCommands, append([]string{}, command...))
+	}
+	return executedCommands
+}
+
+var _ service_context.ServiceContext = (*FakeServiceContext)(nil)
+
+// ===========================================================================================
+//                              ServiceContext interface methods
+// ===========================================================================================
+
+func (fake *FakeServiceContext) GetServiceID() services.ServiceID {
+	return fake.serviceId
+}
+
+func (fake *FakeServiceContext) GetIPAddress() string {
+	return fake.ipAddress
+}
+
+func (fake *FakeServiceContext) ExecCommand(command []string) (int32, *[]byte, error) {
+	fake.mutex.Lock()
+	fake.executedCommands = append(fake.executedCommands, append([]string{}, command...))
+	handler := fake.commandHandler
+	fake.mutex.Unlock()
+
+	exitCode, output, err := handler(command)
+	if err != nil {
+		return 0, nil, err
+	}
+	outputBytes := []byte(output)
+	return exitCode, &outputBytes, nil
+}
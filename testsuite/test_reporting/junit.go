@@ -0,0 +1,84 @@
+package test_reporting
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+// Name of the test case that stands for the test as a whole, alongside the ones for its steps
+const wholeTestCaseName = "(test)"
+
+type junitTestSuite struct {
+	XMLName xml.Name `xml:"testsuite"`
+	Name string `xml:"name,attr"`
+	NumTests int `xml:"tests,attr"`
+	NumFailures int `xml:"failures,attr"`
+	TimeSeconds string `xml:"time,attr"`
+	Timestamp string `xml:"timestamp,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name string `xml:"name,attr"`
+	ClassName string `xml:"classname,attr"`
+	TimeSeconds string `xml:"time,attr"`
+	Failure *junitFailure `xml:"failure,omitempty"`
+}
+
+type junitFailure struct {
+	// The outermost error message, which dashboards show as the failure's summary
+	Message string `xml:"message,attr"`
+	// The whole error chain, one message per line
+	Text string `xml:",chardata"`
+}
+
+// ==========================================================================================
+//								Helper methods
+// ==========================================================================================
+
+func newJunitTestSuite(report *TestReport) junitTestSuite {
+	testCases := []junitTestCase{}
+	for _, step := range report.Steps {
+		testCases = append(testCases, newJunitTestCase(report.TestName, step.Name, step.DurationSeconds, step.Status, step.ErrorChain))
+	}
+	testCases = append(testCases, newJunitTestCase(report.TestName, wholeTestCaseName, report.DurationSeconds, report.Status, report.ErrorChain))
+
+	numFailures := 0
+	for _, testCase := range testCases {
+		if testCase.Failure != nil {
+			numFailures++
+		}
+	}
+	return junitTestSuite{
+		Name:        report.TestName,
+		NumTests:    len(testCases),
+		NumFailures: numFailures,
+		TimeSeconds: formatJunitSeconds(report.DurationSeconds),
+		Timestamp:   report.StartTime.UTC().Format("2006-01-02T15:04:05"),
+		TestCases:   testCases,
+	}
+}
+
+func newJunitTestCase(testName string, name string, durationSeconds float64, status string, errorChain []string) junitTestCase {
+	testCase := junitTestCase{
+		Name:        name,
+		ClassName:   testName,
+		TimeSeconds: formatJunitSeconds(durationSeconds),
+	}
+	if status == FailedStatus {
+		message := ""
+		if len(errorChain) > 0 {
+			message = errorChain[0]
+		}
+		testCase.Failure = &junitFailure{
+			Message: message,
+			Text:    strings.Join(errorChain, "\n"),
+		}
+	}
+	return testCase
+}
+
+func formatJunitSeconds(seconds float64) string {
+	return fmt.Sprintf("%.3f", seconds)
+}
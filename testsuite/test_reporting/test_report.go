@@ -0,0 +1,151 @@
+package test_reporting
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"github.com/palantir/stacktrace"
+	"io/ioutil"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	PassedStatus = "passed"
+	FailedStatus = "failed"
+
+	reportFilePerms = 0644
+
+	// How stacktrace errors mark the location lines and causes in their full format
+	stacktraceLocationLinePrefix = " --- at "
+	stacktraceCausePrefix = "Caused by: "
+)
+
+/*
+	What happened during one step of a test.
+ */
+type StepResult struct {
+	Name string `json:"name"`
+	Status string `json:"status"`
+	StartTime time.Time `json:"startTime"`
+	DurationSeconds float64 `json:"durationSeconds"`
+	// Messages of the error the step failed with, from the outermost error down to its root cause; empty if it passed
+	ErrorChain []string `json:"errorChain,omitempty"`
+}
+
+/*
+	Results of a test and the steps it recorded, filled in as the test runs. Safe to use from many goroutines.
+ */
+type TestReport struct {
+	mutex *sync.Mutex
+
+	TestName string `json:"testName"`
+	Status string `json:"status"`
+	StartTime time.Time `json:"startTime"`
+	DurationSeconds float64 `json:"durationSeconds"`
+	Steps []StepResult `json:"steps"`
+	// Messages of the error the test failed with, from the outermost error down to its root cause; empty if it passed
+	ErrorChain []string `json:"errorChain,omitempty"`
+}
+
+func NewTestReport(testName string) *TestReport {
+	return &TestReport{
+		mutex:     &sync.Mutex{},
+		TestName:  testName,
+		Status:    PassedStatus,
+		StartTime: time.Now(),
+		Steps:     []StepResult{},
+	}
+}
+
+/*
+	Runs the step, recording how long it took and the error it returned, if any, which is passed back to the caller.
+ */
+func (report *TestReport) RunStep(name string, step func() error) error {
+	startTime := time.Now()
+	err := step()
+	result := StepResult{
+		Name:            name,
+		Status:          PassedStatus,
+		StartTime:       startTime,
+		DurationSeconds: time.Since(startTime).Seconds(),
+	}
+	if err != nil {
+		result.Status = FailedStatus
+		result.ErrorChain = GetErrorChain(err)
+	}
+	report.mutex.Lock()
+	report.Steps = append(report.Steps, result)
+	report.mutex.Unlock()
+	return err
+}
+
+/*
+	Marks the test as finished, failing it if the given value (an error, or whatever the test panicked with) isn't nil.
+ */
+func (report *TestReport) Finish(failure interface{}) {
+	report.mutex.Lock()
+	defer report.mutex.Unlock()
+	report.DurationSeconds = time.Since(report.StartTime).Seconds()
+	if failure == nil {
+		return
+	}
+	report.Status = FailedStatus
+	if err, isError := failure.(error); isError {
+		report.ErrorChain = GetErrorChain(err)
+	} else {
+		report.ErrorChain = []string{fmt.Sprintf("%v", failure)}
+	}
+}
+
+func (report *TestReport) WriteJson(filepath string) error {
+	report.mutex.Lock()
+	reportBytes, err := json.MarshalIndent(report, "", "  ")
+	report.mutex.Unlock()
+	if err != nil {
+		return stacktrace.Propagate(err, "An error occurred serializing the report of test '%v'", report.TestName)
+	}
+	if err := ioutil.WriteFile(filepath, reportBytes, reportFilePerms); err != nil {
+		return stacktrace.Propagate(err, "An error occurred writing the report of test '%v' to '%v'", report.TestName, filepath)
+	}
+	return nil
+}
+
+/*
+	Writes the report as a JUnit XML test suite with one test case per step, plus one for the test as a whole so that
+	failures outside of any step (e.g. failed assertions) still show up as a failed test case.
+ */
+func (report *TestReport) WriteJunitXml(filepath string) error {
+	report.mutex.Lock()
+	suite := newJunitTestSuite(report)
+	report.mutex.Unlock()
+	suiteBytes, err := xml.MarshalIndent(suite, "", "  ")
+	if err != nil {
+		return stacktrace.Propagate(err, "An error occurred serializing the JUnit report of test '%v'", report.TestName)
+	}
+	fileBytes := append([]byte(xml.Header), suiteBytes...)
+	if err := ioutil.WriteFile(filepath, fileBytes, reportFilePerms); err != nil {
+		return stacktrace.Propagate(err, "An error occurred writing the JUnit report of test '%v' to '%v'", report.TestName, filepath)
+	}
+	return nil
+}
+
+/*
+	The messages of an error and the errors it wraps, from the outermost one down to the root cause, without the
+	file and line information stacktrace adds.
+ */
+func GetErrorChain(err error) []string {
+	chain := []string{}
+	for _, line := range strings.Split(err.Error(), "\n") {
+		if strings.HasPrefix(line, stacktraceLocationLinePrefix) {
+			continue
+		}
+		message := strings.TrimPrefix(line, stacktraceCausePrefix)
+		if message == "" {
+			continue
+		}
+		chain = append(chain, message)
+	}
+	return chain
+}
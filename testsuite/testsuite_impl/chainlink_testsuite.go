@@ -2,43 +2,171 @@ package testsuite_impl
 
 import (
 	"github.com/kurtosis-tech/kurtosis-libs/golang/lib/testsuite"
+	"github.com/kurtosistech/chainlink-testing/testsuite/network_spec"
+	"github.com/kurtosistech/chainlink-testing/testsuite/networks_impl"
+	"github.com/kurtosistech/chainlink-testing/testsuite/testsuite_impl/cron_job_test"
+	"github.com/kurtosistech/chainlink-testing/testsuite/testsuite_impl/database_failure_test"
+	"github.com/kurtosistech/chainlink-testing/testsuite/testsuite_impl/eth_log_job_test"
+	"github.com/kurtosistech/chainlink-testing/testsuite/testsuite_impl/fulfillment_benchmark_test"
 	"github.com/kurtosistech/chainlink-testing/testsuite/testsuite_impl/link_contract_initialization_test"
+	"github.com/kurtosistech/chainlink-testing/testsuite/testsuite_impl/minimum_contract_payment_test"
+	"github.com/kurtosistech/chainlink-testing/testsuite/testsuite_impl/network_spec_test"
+	"github.com/kurtosistech/chainlink-testing/testsuite/testsuite_impl/oracle_key_management_test"
+	"github.com/kurtosistech/chainlink-testing/testsuite/testsuite_impl/oracle_under_load_test"
+	"github.com/kurtosistech/chainlink-testing/testsuite/testsuite_impl/oracle_upgrade_test"
+	"github.com/kurtosistech/chainlink-testing/testsuite/testsuite_impl/oracle_withdrawal_test"
+	"github.com/kurtosistech/chainlink-testing/testsuite/testsuite_impl/request_cancellation_test"
+	"github.com/kurtosistech/chainlink-testing/testsuite/testsuite_impl/test_base"
+	"github.com/kurtosistech/chainlink-testing/testsuite/testsuite_impl/webhook_job_test"
+	"github.com/sirupsen/logrus"
 )
 
 type ChainlinkTestsuite struct {
-	gethServiceImage string
+	// Every test is run once per combination of geth and oracle images in here
+	versionMatrix []VersionCombination
 	chainlinkContractDeployerImage string
-	chainlinkOracleImage string
+	// Image the upgrade test starts the Oracle on before upgrading it to the combination's image; the upgrade test
+	// isn't registered if this is empty, or for a combination whose Oracle image is this one
+	chainlinkOracleUpgradeFromImage string
 	postgresImage string
 	priceFeedServerImage string
+
+	defaultTopology networks_impl.NetworkTopology
+	// Test name -> topology that test uses instead of the default one
+	topologyOverrides map[string]networks_impl.NetworkTopology
+	// Names of the tests to run; if empty, every test is run
+	testsToRun []string
+	// Each spec is run as a test of its own, which takes its topology from the spec rather than from the topologies above
+	networkSpecs []network_spec.NetworkSpec
 }
 
-func NewChainlinkTestsuite(gethServiceImage string, chainlinkContractDeployerImage string,
-	chainlinkOracleImage string, postgresImage string, priceFeedServerImage string) *ChainlinkTestsuite {
+func NewChainlinkTestsuite(versionMatrix []VersionCombination, chainlinkContractDeployerImage string,
+	chainlinkOracleUpgradeFromImage string, postgresImage string, priceFeedServerImage string,
+	defaultTopology networks_impl.NetworkTopology, topologyOverrides map[string]networks_impl.NetworkTopology,
+	testsToRun []string, networkSpecs []network_spec.NetworkSpec) *ChainlinkTestsuite {
 	return &ChainlinkTestsuite{
-		gethServiceImage: gethServiceImage,
+		versionMatrix: versionMatrix,
 		chainlinkContractDeployerImage: chainlinkContractDeployerImage,
-		chainlinkOracleImage: chainlinkOracleImage,
+		chainlinkOracleUpgradeFromImage: chainlinkOracleUpgradeFromImage,
 		postgresImage: postgresImage,
 		priceFeedServerImage: priceFeedServerImage,
+		defaultTopology: defaultTopology,
+		topologyOverrides: topologyOverrides,
+		testsToRun: testsToRun,
+		networkSpecs: networkSpecs,
 	}
 }
 
+/*
+	With a single version combination, tests are registered under their own names; with more, each test is registered
+	once per combination with the combination's label appended (e.g. "cronJobTest_chainlink-0.10.2_client-go-latest"),
+	so results are reported per version. Every test writes JSON and JUnit reports named after the name it's registered
+	under.
+ */
 func (suite ChainlinkTestsuite) GetTests() map[string]testsuite.Test {
-	tests := map[string]testsuite.Test{
-		"linkContractInitializationTest": link_contract_initialization_test.NewLinkContractInitializationTest(
-			suite.gethServiceImage,
+	tests := map[string]testsuite.Test{}
+	for _, combination := range suite.versionMatrix {
+		for testName, test := range suite.GetTestsForVersions(combination) {
+			if len(suite.versionMatrix) > 1 {
+				testName = testName + "_" + combination.GetLabel()
+			}
+			tests[testName] = newReportedTest(testName, test)
+		}
+	}
+	return tests
+}
+
+/*
+	The selected tests, under their own names, set up to run against the given images. Tests to run and topology
+	overrides are keyed by these names, whatever the version matrix.
+ */
+func (suite ChainlinkTestsuite) GetTestsForVersions(combination VersionCombination) map[string]testsuite.Test {
+	gethServiceImage := combination.GethServiceImage
+	chainlinkOracleImage := combination.ChainlinkOracleImage
+	versionLabel := ""
+	if len(suite.versionMatrix) > 1 {
+		versionLabel = combination.GetLabel()
+	}
+	newTestBaseWithTopology := func(testName string, topology networks_impl.NetworkTopology) test_base.ChainlinkTestBase {
+		return test_base.NewChainlinkTestBase(testName,
+			gethServiceImage,
 			suite.chainlinkContractDeployerImage,
-			suite.chainlinkOracleImage,
+			chainlinkOracleImage,
 			suite.postgresImage,
-			suite.priceFeedServerImage,),
+			suite.priceFeedServerImage,
+			topology)
 	}
-	return tests
+	// The test gets the name it's registered under, so its scenario and failure artifacts are labelled with it
+	newTestBase := func(testName string) test_base.ChainlinkTestBase {
+		return newTestBaseWithTopology(testName, suite.getTopology(testName, versionLabel))
+	}
+	tests := map[string]testsuite.Test{
+		"linkContractInitializationTest": link_contract_initialization_test.NewLinkContractInitializationTest(
+			newTestBase("linkContractInitializationTest")),
+		"oracleUnderLoadTest": oracle_under_load_test.NewOracleUnderLoadTest(
+			newTestBase("oracleUnderLoadTest")),
+		"fulfillmentBenchmarkTest": fulfillment_benchmark_test.NewFulfillmentBenchmarkTest(
+			newTestBase("fulfillmentBenchmarkTest")),
+		"databaseFailureTest": database_failure_test.NewDatabaseFailureTest(
+			newTestBase("databaseFailureTest")),
+		"cronJobTest": cron_job_test.NewCronJobTest(
+			newTestBase("cronJobTest")),
+		"webhookJobTest": webhook_job_test.NewWebhookJobTest(
+			newTestBase("webhookJobTest")),
+		"ethLogJobTest": eth_log_job_test.NewEthLogJobTest(
+			newTestBase("ethLogJobTest")),
+		"minimumContractPaymentTest": minimum_contract_payment_test.NewMinimumContractPaymentTest(
+			newTestBase("minimumContractPaymentTest")),
+		"requestCancellationTest": request_cancellation_test.NewRequestCancellationTest(
+			newTestBase("requestCancellationTest")),
+		"oracleWithdrawalTest": oracle_withdrawal_test.NewOracleWithdrawalTest(
+			newTestBase("oracleWithdrawalTest")),
+		"oracleKeyManagementTest": oracle_key_management_test.NewOracleKeyManagementTest(
+			newTestBase("oracleKeyManagementTest")),
+	}
+	// Restarting the Oracle on the image it's already on wouldn't test an upgrade, so the test needs another image
+	switch suite.chainlinkOracleUpgradeFromImage {
+	case "":
+		logrus.Infof("Not registering oracleUpgradeTest, as no Chainlink oracle image to upgrade from was given.")
+	case chainlinkOracleImage:
+		logrus.Infof("Not registering oracleUpgradeTest against Chainlink oracle image '%v', as that's the image to upgrade from.",
+			chainlinkOracleImage)
+	default:
+		tests["oracleUpgradeTest"] = oracle_upgrade_test.NewOracleUpgradeTest(
+			newTestBase("oracleUpgradeTest"),
+			suite.chainlinkOracleUpgradeFromImage)
+	}
+	for _, spec := range suite.networkSpecs {
+		specTopology := spec.GetTopology()
+		specTopology.VersionLabel = versionLabel
+		specTestName := spec.Name + network_spec_test.TestNameSuffix
+		tests[specTestName] = network_spec_test.NewNetworkSpecTest(newTestBaseWithTopology(specTestName, specTopology), spec)
+	}
+	if len(suite.testsToRun) == 0 {
+		return tests
+	}
+	selectedTests := map[string]testsuite.Test{}
+	for _, testName := range suite.testsToRun {
+		if test, found := tests[testName]; found {
+			selectedTests[testName] = test
+		}
+	}
+	return selectedTests
 }
 
 func (suite ChainlinkTestsuite) GetNetworkWidthBits() uint32 {
 	return 8
 }
 
+// ==========================================================================================
+//								Helper methods
+// ==========================================================================================
 
-
+func (suite ChainlinkTestsuite) getTopology(testName string, versionLabel string) networks_impl.NetworkTopology {
+	topology := suite.defaultTopology
+	if override, found := suite.topologyOverrides[testName]; found {
+		topology = override
+	}
+	topology.VersionLabel = versionLabel
+	return topology
+}
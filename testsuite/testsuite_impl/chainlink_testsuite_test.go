@@ -0,0 +1,36 @@
+package testsuite_impl
+
+import (
+	"github.com/kurtosistech/chainlink-testing/testsuite/network_spec"
+	"github.com/kurtosistech/chainlink-testing/testsuite/networks_impl"
+	"testing"
+)
+
+const (
+	testOracleImage = "smartcontract/chainlink:0.10.3"
+	testUpgradeFromOracleImage = "smartcontract/chainlink:0.10.2"
+)
+
+func TestGetTestsForVersionsRegistersUpgradeTestOnlyForAnUpgrade(t *testing.T) {
+	testCases := []struct {
+		name string
+		upgradeFromImage string
+		isUpgradeTestExpected bool
+	}{
+		{name: "no image to upgrade from", upgradeFromImage: "", isUpgradeTestExpected: false},
+		{name: "upgrade from the image under test", upgradeFromImage: testOracleImage, isUpgradeTestExpected: false},
+		{name: "upgrade from another image", upgradeFromImage: testUpgradeFromOracleImage, isUpgradeTestExpected: true},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			combination := VersionCombination{GethServiceImage: "geth", ChainlinkOracleImage: testOracleImage}
+			suite := NewChainlinkTestsuite([]VersionCombination{combination}, "deployer", testCase.upgradeFromImage,
+				"postgres", "price-feed", networks_impl.NewDefaultNetworkTopology(),
+				map[string]networks_impl.NetworkTopology{}, []string{}, []network_spec.NetworkSpec{})
+			_, isUpgradeTestRegistered := suite.GetTestsForVersions(combination)["oracleUpgradeTest"]
+			if isUpgradeTestRegistered != testCase.isUpgradeTestExpected {
+				t.Fatalf("Expected oracleUpgradeTest to be registered to be %v, but it was %v", testCase.isUpgradeTestExpected, isUpgradeTestRegistered)
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package cron_job_test
+
+import (
+	"github.com/kurtosis-tech/kurtosis-libs/golang/lib/networks"
+	"github.com/kurtosis-tech/kurtosis-libs/golang/lib/testsuite"
+	"github.com/kurtosistech/chainlink-testing/testsuite/networks_impl"
+	"github.com/kurtosistech/chainlink-testing/testsuite/scenarios"
+	"github.com/kurtosistech/chainlink-testing/testsuite/services_impl/chainlink_oracle"
+	"github.com/kurtosistech/chainlink-testing/testsuite/services_impl/price_feed_server"
+	"github.com/kurtosistech/chainlink-testing/testsuite/testsuite_impl/test_base"
+	"github.com/palantir/stacktrace"
+	"github.com/sirupsen/logrus"
+	"math/big"
+	"time"
+)
+
+const (
+	archiveCronJobStepName = "archive cron job"
+	checkCronRunsStepName = "check cron runs"
+
+	// The node's cron schedules take a seconds field, so the job can run every few seconds
+	cronSchedule = "CRON_TZ=UTC 0/15 * * * * *"
+	cronPeriod = 15 * time.Second
+	// How long the job is left running before being archived
+	observationWindow = 60 * time.Second
+	// The window doesn't line up with the schedule, so it can catch one run more or less than it spans
+	allowedRunCountDeviation = 1
+)
+
+type CronJobTest struct {
+	test_base.ChainlinkTestBase
+}
+
+func NewCronJobTest(base test_base.ChainlinkTestBase) *CronJobTest {
+	return &CronJobTest{
+		ChainlinkTestBase: base,
+	}
+}
+
+func (test *CronJobTest) Run(network networks.Network, testCtx testsuite.TestContext) {
+	test.RunScenario(network, testCtx,
+		scenarios.ConnectGethPeersStep(),
+		scenarios.DeployContractsStep(),
+		scenarios.DeploySimpleConsumerStep(),
+		scenarios.StartOracleStep(),
+		scenarios.FundOracleEthAccountsStep(),
+		scenarios.AddCronJobStep(cronSchedule),
+		scenarios.SleepStep(observationWindow),
+		scenarios.NewStep(archiveCronJobStepName, archiveCronJob),
+		scenarios.NewStep(checkCronRunsStepName, checkCronRuns))
+}
+
+// ==========================================================================================
+//								Helper methods
+// ==========================================================================================
+
+func archiveCronJob(chainlinkNetwork *networks_impl.ChainlinkNetwork, state *scenarios.State) error {
+	jobId := state.JobIds[scenarios.CronJobName]
+	if err := chainlinkNetwork.GetChainlinkOracle().ArchiveJob(jobId); err != nil {
+		return stacktrace.Propagate(err, "Error archiving cron job %v.", jobId)
+	}
+	return nil
+}
+
+func checkCronRuns(chainlinkNetwork *networks_impl.ChainlinkNetwork, state *scenarios.State) error {
+	jobId := state.JobIds[scenarios.CronJobName]
+	runs, err := chainlinkNetwork.WaitForJobRunsToFinish(jobId)
+	if err != nil {
+		return stacktrace.Propagate(err, "Error waiting for the runs of cron job %v to finish.", jobId)
+	}
+	expectedNumRuns := int(observationWindow / cronPeriod)
+	logrus.Infof("Cron job %v ran %v times in %v; expected %v±%v.", jobId, len(runs), observationWindow, expectedNumRuns, allowedRunCountDeviation)
+	if len(runs) < expectedNumRuns - allowedRunCountDeviation || len(runs) > expectedNumRuns + allowedRunCountDeviation {
+		return stacktrace.NewError("Expected the cron job to run %v±%v times in %v, but it ran %v times.",
+			expectedNumRuns, allowedRunCountDeviation, observationWindow, len(runs))
+	}
+	for _, run := range runs {
+		if run.Attributes.Initiator.Type != chainlink_oracle.CronInitiatorType {
+			return stacktrace.NewError("Expected run %v to be started by the cron initiator, but it was started by '%v'.",
+				run.Attributes.Id, run.Attributes.Initiator.Type)
+		}
+		if run.Attributes.Status != chainlink_oracle.RunStatusCompleted {
+			return stacktrace.NewError("Expected run %v to complete, but it ended with status '%v'.", run.Attributes.Id, run.Attributes.Status)
+		}
+	}
+
+	value, numWrites, err := chainlinkNetwork.GetSimpleConsumerState()
+	if err != nil {
+		return stacktrace.Propagate(err, "Error reading the SimpleConsumer contract.")
+	}
+	if numWrites.Cmp(big.NewInt(int64(len(runs)))) != 0 {
+		return stacktrace.NewError("Expected one write to the SimpleConsumer per run (%v), but it had %v.", len(runs), numWrites)
+	}
+	expectedValue := chainlink_oracle.GetPriceFeedOnChainValue(price_feed_server.SampleUsdPrice)
+	if value.String() != expectedValue {
+		return stacktrace.NewError("Expected the SimpleConsumer to hold %v, but it held %v.", expectedValue, value)
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+package database_failure_test
+
+import (
+	"github.com/kurtosis-tech/kurtosis-libs/golang/lib/networks"
+	"github.com/kurtosis-tech/kurtosis-libs/golang/lib/testsuite"
+	"github.com/kurtosistech/chainlink-testing/testsuite/networks_impl"
+	"github.com/kurtosistech/chainlink-testing/testsuite/scenarios"
+	"github.com/kurtosistech/chainlink-testing/testsuite/testsuite_impl/test_base"
+	"github.com/palantir/stacktrace"
+	"github.com/sirupsen/logrus"
+	"time"
+)
+
+const (
+	requestBeforeFaultsStepName = "fulfill request before injecting database faults"
+	terminateConnectionsStepName = "terminate the Oracle's database connections"
+	requestAfterTerminatingStepName = "fulfill request after terminating database connections"
+	requestWhilePausedStepName = "request data while the database is paused"
+	waitForPausedRequestStepName = "wait for the request made while the database was paused"
+	requestBeforeRestartStepName = "request data before restarting the database"
+	waitForRestartedRequestStepName = "wait for the request pending while the database restarted"
+	requestWithLatencyStepName = "fulfill request with database latency"
+	requestAfterFaultsStepName = "fulfill request after removing database faults"
+
+	// Long enough for the Oracle's queries to time out and the request's log to arrive while the database is frozen
+	databasePauseDuration = 20 * time.Second
+
+	// Added to every chunk of traffic in each direction, so each query round trip costs at least twice this
+	databaseLatency = 200 * time.Millisecond
+)
+
+type DatabaseFailureTest struct {
+	test_base.ChainlinkTestBase
+}
+
+func NewDatabaseFailureTest(base test_base.ChainlinkTestBase) *DatabaseFailureTest {
+	return &DatabaseFailureTest{
+		ChainlinkTestBase: base,
+	}
+}
+
+func (test *DatabaseFailureTest) Setup(networkCtx *networks.NetworkContext) (networks.Network, error) {
+	chainlinkNetwork, err := test.SetupNetwork(networkCtx, test.ChainlinkOracleImage)
+	if err != nil {
+		return nil, err
+	}
+
+	err = chainlinkNetwork.EnableOracleDatabaseProxy()
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "Error enabling the Oracle database proxy.")
+	}
+
+	return chainlinkNetwork, nil
+}
+
+func (test *DatabaseFailureTest) Run(network networks.Network, testCtx testsuite.TestContext) {
+	pausedRequest := &pendingRequest{}
+	restartedRequest := &pendingRequest{}
+	test.RunScenario(network, testCtx,
+		scenarios.ConnectGethPeersStep(),
+		scenarios.DeployContractsStep(),
+		scenarios.FundLinkWalletStep(),
+		scenarios.StartOracleStep(),
+		scenarios.FundOracleEthAccountsStep(),
+		scenarios.DeployPriceFeedJobStep(),
+		// Sanity check that the Oracle fulfills requests before we start breaking its database
+		scenarios.NewStep(requestBeforeFaultsStepName, requestDataAndWait),
+
+		scenarios.NewStep(terminateConnectionsStepName, terminateDatabaseConnections),
+		scenarios.NewStep(requestAfterTerminatingStepName, requestDataAndWait),
+
+		scenarios.PauseOracleDatabaseStep(),
+		scenarios.NewStep(requestWhilePausedStepName, pausedRequest.send),
+		scenarios.SleepStep(databasePauseDuration),
+		scenarios.ResumeOracleDatabaseStep(),
+		scenarios.NewStep(waitForPausedRequestStepName, pausedRequest.waitForFulfillment),
+
+		scenarios.NewStep(requestBeforeRestartStepName, restartedRequest.send),
+		scenarios.RestartOracleDatabaseStep(),
+		scenarios.NewStep(waitForRestartedRequestStepName, restartedRequest.waitForFulfillment),
+
+		scenarios.SetOracleDatabaseLatencyStep(databaseLatency),
+		scenarios.NewStep(requestWithLatencyStepName, requestDataAndWait),
+		scenarios.SetOracleDatabaseLatencyStep(0),
+		// The Oracle should be back to normal once the faults are gone
+		scenarios.NewStep(requestAfterFaultsStepName, requestDataAndWait))
+}
+
+// ==========================================================================================
+//								Helper methods
+// ==========================================================================================
+
+func requestDataAndWait(chainlinkNetwork *networks_impl.ChainlinkNetwork, state *scenarios.State) error {
+	requestTxHash, err := chainlinkNetwork.SendDataRequest()
+	if err != nil {
+		return stacktrace.Propagate(err, "Error requesting data from Chainlink oracle.")
+	}
+	if err := chainlinkNetwork.WaitForDataRequestFulfillment(requestTxHash); err != nil {
+		return stacktrace.Propagate(err, "Oracle didn't fulfill request %v.", requestTxHash)
+	}
+	return nil
+}
+
+/*
+	Unlike scenarios.TerminateOracleDatabaseConnectionsStep this isn't retried, as a retry would find the connections
+	already gone.
+ */
+func terminateDatabaseConnections(chainlinkNetwork *networks_impl.ChainlinkNetwork, state *scenarios.State) error {
+	numTerminated, err := chainlinkNetwork.TerminateOracleDatabaseConnections()
+	if err != nil {
+		return stacktrace.Propagate(err, "Error terminating the Oracle's database connections.")
+	}
+	if numTerminated == 0 {
+		return stacktrace.NewError("Expected the Oracle to have open database connections to terminate, but it had none.")
+	}
+	logrus.Infof("Terminated %v of the Oracle's database connections.", numTerminated)
+	return nil
+}
+
+/*
+	A request sent in one step and waited on in a later one, so a database fault can be injected while its run is
+	pending.
+ */
+type pendingRequest struct {
+	txHash string
+}
+
+func (request *pendingRequest) send(chainlinkNetwork *networks_impl.ChainlinkNetwork, state *scenarios.State) error {
+	txHash, err := chainlinkNetwork.SendDataRequest()
+	if err != nil {
+		return stacktrace.Propagate(err, "Error requesting data from Chainlink oracle.")
+	}
+	request.txHash = txHash
+	return nil
+}
+
+func (request *pendingRequest) waitForFulfillment(chainlinkNetwork *networks_impl.ChainlinkNetwork, state *scenarios.State) error {
+	if err := chainlinkNetwork.WaitForDataRequestFulfillment(request.txHash); err != nil {
+		return stacktrace.Propagate(err, "Oracle didn't finish the run of request %v, pending while its database failed.", request.txHash)
+	}
+	return nil
+}
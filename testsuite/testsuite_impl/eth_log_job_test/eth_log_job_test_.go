@@ -0,0 +1,150 @@
+package eth_log_job_test
+
+import (
+	"fmt"
+	"github.com/kurtosis-tech/kurtosis-libs/golang/lib/networks"
+	"github.com/kurtosis-tech/kurtosis-libs/golang/lib/testsuite"
+	"github.com/kurtosistech/chainlink-testing/testsuite/networks_impl"
+	"github.com/kurtosistech/chainlink-testing/testsuite/scenarios"
+	"github.com/kurtosistech/chainlink-testing/testsuite/services_impl/chainlink_oracle"
+	"github.com/kurtosistech/chainlink-testing/testsuite/services_impl/geth"
+	"github.com/kurtosistech/chainlink-testing/testsuite/testsuite_impl/test_base"
+	"github.com/palantir/stacktrace"
+	"github.com/sirupsen/logrus"
+	"strconv"
+	"strings"
+)
+
+const (
+	emitEventsStepName = "emit events"
+	checkEventRunsStepName = "check event runs"
+	checkEthLogRunsStepName = "check EthLog runs"
+
+	// Enough blocks, at one per second, that polling sees runs waiting on them
+	minIncomingConfirmations = 10
+	numEvents = 3
+	eventDataPrefix = "kurtosis-event-"
+
+	// keccak256("Emitted(uint256,bytes32)"), the topic of the EventEmitter's event
+	emittedEventTopic = "0x3e26d583477d84ef7fcf199cf079517774a730fe5acb9aa157385bcf0b203c21"
+)
+
+type EthLogJobTest struct {
+	test_base.ChainlinkTestBase
+}
+
+func NewEthLogJobTest(base test_base.ChainlinkTestBase) *EthLogJobTest {
+	base.Topology = base.Topology.WithOracleEnvironment(chainlink_oracle.MinIncomingConfirmationsEnvVar, strconv.Itoa(minIncomingConfirmations))
+	return &EthLogJobTest{
+		ChainlinkTestBase: base,
+	}
+}
+
+func (test *EthLogJobTest) Run(network networks.Network, testCtx testsuite.TestContext) {
+	events := &emittedEvents{}
+	test.RunScenario(network, testCtx,
+		scenarios.ConnectGethPeersStep(),
+		scenarios.DeployContractsStep(),
+		scenarios.DeployEventEmitterStep(),
+		scenarios.StartOracleStep(),
+		scenarios.AddEthLogJobStep(),
+		scenarios.NewStep(emitEventsStepName, events.emit),
+		scenarios.NewStep(checkEventRunsStepName, events.checkRuns),
+		scenarios.NewStep(checkEthLogRunsStepName, checkEthLogRuns))
+}
+
+// ==========================================================================================
+//								Helper methods
+// ==========================================================================================
+
+/*
+	The events one run of the test emits, handed from the step that emits them to the step that checks their runs.
+ */
+type emittedEvents struct {
+	// Receipt of the transaction that emitted each event, in order of event ID starting at 1
+	receipts []*geth.TransactionReceipt
+}
+
+func (events *emittedEvents) emit(chainlinkNetwork *networks_impl.ChainlinkNetwork, state *scenarios.State) error {
+	events.receipts = []*geth.TransactionReceipt{}
+	for eventId := uint64(1); eventId <= numEvents; eventId++ {
+		receipt, err := chainlinkNetwork.EmitEvent(eventId, fmt.Sprintf("%v%v", eventDataPrefix, eventId))
+		if err != nil {
+			return stacktrace.Propagate(err, "Error emitting event %v.", eventId)
+		}
+		events.receipts = append(events.receipts, receipt)
+	}
+	return nil
+}
+
+func (events *emittedEvents) checkRuns(chainlinkNetwork *networks_impl.ChainlinkNetwork, state *scenarios.State) error {
+	jobId := state.JobIds[scenarios.EthLogJobName]
+	for eventIdx, receipt := range events.receipts {
+		eventId := uint64(eventIdx + 1)
+		run, lastPendingHead, err := chainlinkNetwork.WaitForLogRun(jobId, receipt.TransactionHash)
+		if err != nil {
+			return stacktrace.Propagate(err, "Error waiting for the run of event %v.", eventId)
+		}
+		logBlock, err := geth.ParseHexQuantity(receipt.BlockNumber)
+		if err != nil {
+			return stacktrace.Propagate(err, "Error parsing the block event %v was emitted in.", eventId)
+		}
+		// Seeing the run pending once the log was mined shows the Oracle held it back for confirmations
+		if lastPendingHead < logBlock {
+			return stacktrace.NewError("Expected the run for event %v, emitted in block %v, to be seen waiting for %v confirmations, but it never was.",
+				eventId, logBlock, minIncomingConfirmations)
+		}
+		// The block the log is in counts as its first confirmation
+		logrus.Infof("Run %v for event %v, emitted in block %v, was still waiting at block %v, when the log had %v confirmations.",
+			run.Attributes.Id, eventId, logBlock, lastPendingHead, lastPendingHead - logBlock + 1)
+
+		logEvent, err := run.GetLogEvent()
+		if err != nil {
+			return stacktrace.Propagate(err, "Error reading the log that started run %v.", run.Attributes.Id)
+		}
+		if err := checkLogEventMatches(run.Attributes.Id, logEvent, eventId); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func checkEthLogRuns(chainlinkNetwork *networks_impl.ChainlinkNetwork, state *scenarios.State) error {
+	jobId := state.JobIds[scenarios.EthLogJobName]
+	runs, err := chainlinkNetwork.GetChainlinkOracle().GetRunsForJob(jobId)
+	if err != nil {
+		return stacktrace.Propagate(err, "Error getting the runs of EthLog job %v.", jobId)
+	}
+	if len(runs) != numEvents {
+		return stacktrace.NewError("Expected one run of EthLog job %v per event (%v), but it had %v.", jobId, numEvents, len(runs))
+	}
+	for _, run := range runs {
+		if run.Attributes.Initiator.Type != chainlink_oracle.EthLogInitiatorType {
+			return stacktrace.NewError("Expected run %v to be started by the EthLog initiator, but it was started by '%v'.",
+				run.Attributes.Id, run.Attributes.Initiator.Type)
+		}
+	}
+	return nil
+}
+
+func checkLogEventMatches(runId string, logEvent chainlink_oracle.LogEvent, eventId uint64) error {
+	expectedTopics := []string{emittedEventTopic, "0x" + geth.EncodeUint256Word(eventId)}
+	if len(logEvent.Topics) != len(expectedTopics) {
+		return stacktrace.NewError("Expected the log of run %v to have topics %v, but it had %v.", runId, expectedTopics, logEvent.Topics)
+	}
+	for topicIdx := range expectedTopics {
+		if !strings.EqualFold(logEvent.Topics[topicIdx], expectedTopics[topicIdx]) {
+			return stacktrace.NewError("Expected topic %v of the log of run %v to be %v, but it was %v.",
+				topicIdx, runId, expectedTopics[topicIdx], logEvent.Topics[topicIdx])
+		}
+	}
+	dataWord, err := geth.EncodeBytes32Word(fmt.Sprintf("%v%v", eventDataPrefix, eventId))
+	if err != nil {
+		return stacktrace.Propagate(err, "Error encoding the data of event %v.", eventId)
+	}
+	expectedData := "0x" + dataWord
+	if !strings.EqualFold(logEvent.Data, expectedData) {
+		return stacktrace.NewError("Expected the log of run %v to have data %v, but it had %v.", runId, expectedData, logEvent.Data)
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package fulfillment_benchmark_test
+
+import (
+	"github.com/kurtosis-tech/kurtosis-libs/golang/lib/networks"
+	"github.com/kurtosis-tech/kurtosis-libs/golang/lib/testsuite"
+	"github.com/kurtosistech/chainlink-testing/testsuite/benchmarking"
+	"github.com/kurtosistech/chainlink-testing/testsuite/networks_impl"
+	"github.com/kurtosistech/chainlink-testing/testsuite/scenarios"
+	"github.com/kurtosistech/chainlink-testing/testsuite/services_impl/geth"
+	"github.com/kurtosistech/chainlink-testing/testsuite/testsuite_impl/test_base"
+	"github.com/palantir/stacktrace"
+	"github.com/sirupsen/logrus"
+	"path"
+)
+
+const (
+	benchmarkFulfillmentStepName = "benchmark fulfillment"
+
+	numConcurrentRequests = 20
+
+	benchmarkReportFilenamePrefix = "fulfillment-benchmark-report"
+	benchmarkReportFileExtension = ".json"
+)
+
+type FulfillmentBenchmarkTest struct {
+	test_base.ChainlinkTestBase
+}
+
+func NewFulfillmentBenchmarkTest(base test_base.ChainlinkTestBase) *FulfillmentBenchmarkTest {
+	return &FulfillmentBenchmarkTest{
+		ChainlinkTestBase: base,
+	}
+}
+
+func (test *FulfillmentBenchmarkTest) Run(network networks.Network, testCtx testsuite.TestContext) {
+	test.RunScenario(network, testCtx,
+		scenarios.ConnectGethPeersStep(),
+		scenarios.DeployContractsStep(),
+		scenarios.StartOracleStep(),
+		scenarios.FundOracleEthAccountsStep(),
+		scenarios.DeployPriceFeedJobStep(),
+		scenarios.NewStep(benchmarkFulfillmentStepName, benchmarkFulfillment))
+}
+
+// ==========================================================================================
+//								Helper methods
+// ==========================================================================================
+
+/*
+	Makes the concurrent requests and writes the report of how they were fulfilled, failing if any of them weren't.
+ */
+func benchmarkFulfillment(chainlinkNetwork *networks_impl.ChainlinkNetwork, state *scenarios.State) error {
+	samples, err := chainlinkNetwork.BenchmarkFulfillment(numConcurrentRequests)
+	if err != nil {
+		return stacktrace.Propagate(err, "Error benchmarking Oracle fulfillment.")
+	}
+	report, err := benchmarking.NewFulfillmentBenchmarkReport(chainlinkNetwork.GetChainlinkOracleImage(), samples)
+	if err != nil {
+		return stacktrace.Propagate(err, "Error building the fulfillment benchmark report.")
+	}
+	// Each version gets a report of its own when the testsuite runs against several
+	benchmarkReportFilename := benchmarkReportFilenamePrefix
+	if versionLabel := chainlinkNetwork.GetTopology().VersionLabel; versionLabel != "" {
+		benchmarkReportFilename = benchmarkReportFilename + "_" + versionLabel
+	}
+	benchmarkReportFilepath := path.Join(geth.SuiteExecutionVolumeMountpoint, benchmarkReportFilename + benchmarkReportFileExtension)
+	if err := report.WriteJson(benchmarkReportFilepath); err != nil {
+		return stacktrace.Propagate(err, "Error writing the fulfillment benchmark report.")
+	}
+
+	logrus.Infof("Oracle fulfilled %v of %v requests with latency p50/p95/p99 of %v/%v/%v seconds (%v/%v/%v blocks); report written to %v",
+		report.NumFulfilled,
+		report.NumRequests,
+		report.LatencySeconds.P50,
+		report.LatencySeconds.P95,
+		report.LatencySeconds.P99,
+		report.LatencyBlocks.P50,
+		report.LatencyBlocks.P95,
+		report.LatencyBlocks.P99,
+		benchmarkReportFilepath)
+	logrus.Infof("Average cost per fulfillment: %v $LINK juels, %v gas (%v wei)",
+		report.AverageLinkJuelsPerFulfillment,
+		report.AverageGasPerFulfillment,
+		report.AverageGasCostWeiPerFulfillment)
+	if report.NumFailed != 0 {
+		return stacktrace.NewError("%v of %v benchmark requests weren't fulfilled", report.NumFailed, report.NumRequests)
+	}
+	return nil
+}
@@ -2,144 +2,87 @@ package link_contract_initialization_test
 
 import (
 	"github.com/kurtosis-tech/kurtosis-libs/golang/lib/networks"
-	"github.com/kurtosis-tech/kurtosis-libs/golang/lib/services"
 	"github.com/kurtosis-tech/kurtosis-libs/golang/lib/testsuite"
 	"github.com/kurtosistech/chainlink-testing/testsuite/networks_impl"
+	"github.com/kurtosistech/chainlink-testing/testsuite/scenarios"
+	"github.com/kurtosistech/chainlink-testing/testsuite/testsuite_impl/test_base"
 	"github.com/palantir/stacktrace"
-	"github.com/sirupsen/logrus"
-	"time"
 )
 
 const (
-	numberOfExtraNodes = 2
+	// Blocks the Oracle's head tracker may trail the chain head by before we consider it stuck
+	maxOracleHeadTrackerLag = 5
 
-	gethDataDirArtifactId  services.FilesArtifactID = "geth-data-dir"
-	gethDataDirArtifactUrl                          = "https://kurtosis-public-access.s3.amazonaws.com/client-artifacts/chainlink/geth-data-dir.tgz"
+	fatallyErroredEthTxesQuery = "SELECT id, error FROM eth_txes WHERE state = 'fatal_error'"
+
+	// Names of the test's own steps, besides the ones from the scenarios package
+	checkTransactionsStepName = "check oracle transactions"
+	checkMetricsStepName = "check oracle metrics"
 )
 
 type LinkContractInitializationTest struct {
-	gethServiceImage string
-	chainlinkContractDeployerImage string
-	chainlinkOracleImage string
-	postgresImage string
-	priceFeedServerImage string
-	validatorIds []services.ServiceID
+	test_base.ChainlinkTestBase
 }
 
-func NewLinkContractInitializationTest(gethServiceImage string, chainlinkContractDeployerImage string,
-	chainlinkOracleImage string, postgresImage string, priceFeedServerImage string) *LinkContractInitializationTest {
+func NewLinkContractInitializationTest(base test_base.ChainlinkTestBase) *LinkContractInitializationTest {
 	return &LinkContractInitializationTest{
-		gethServiceImage: gethServiceImage,
-		chainlinkContractDeployerImage: chainlinkContractDeployerImage,
-		chainlinkOracleImage: chainlinkOracleImage,
-		postgresImage: postgresImage,
-		priceFeedServerImage: priceFeedServerImage,
-		validatorIds: []services.ServiceID{},
+		ChainlinkTestBase: base,
 	}
 }
 
-func (test *LinkContractInitializationTest) Setup(networkCtx *networks.NetworkContext) (networks.Network, error) {
-	chainlinkNetwork := networks_impl.NewChainlinkNetwork(networkCtx,
-		gethDataDirArtifactId,
-		test.gethServiceImage,
-		test.chainlinkContractDeployerImage,
-		test.postgresImage,
-		test.chainlinkOracleImage,
-		test.priceFeedServerImage)
-
-	err := chainlinkNetwork.AddPostgres()
-	if err != nil {
-		return nil, stacktrace.Propagate(err, "Error adding postgres to the network.")
-	}
+func (test *LinkContractInitializationTest) Run(network networks.Network, testCtx testsuite.TestContext) {
+	test.RunScenario(network, testCtx,
+		scenarios.ConnectGethPeersStep(),
+		scenarios.DeployContractsStep(),
+		scenarios.FundLinkWalletStep(),
+		scenarios.StartOracleStep(),
+		scenarios.FundOracleEthAccountsStep(),
+		scenarios.StartMetricsCollectionStep(),
+		scenarios.DeployPriceFeedJobStep(),
+		scenarios.RequestDataStep(),
+		scenarios.NewStep(checkTransactionsStepName, checkOracleTransactions),
+		scenarios.NewStep(checkMetricsStepName, checkOracleMetrics))
+}
 
-	err = chainlinkNetwork.AddPriceFeedServer()
-	if err != nil {
-		return nil, stacktrace.Propagate(err, "Error adding the price feed server to the network.")
-	}
+// ==========================================================================================
+//								Helper methods
+// ==========================================================================================
 
-	err = chainlinkNetwork.AddBootstrapper()
+func checkOracleTransactions(chainlinkNetwork *networks_impl.ChainlinkNetwork, state *scenarios.State) error {
+	fatallyErroredEthTxes, err := chainlinkNetwork.QueryOracleDatabase(fatallyErroredEthTxesQuery)
 	if err != nil {
-		return nil, stacktrace.Propagate(err, "Error adding bootstrapper to the network.")
+		return stacktrace.Propagate(err, "Error querying the Oracle's database for failed transactions.")
 	}
-	logrus.Infof("Added a geth bootstrapper service.")
-	for i := 0; i < numberOfExtraNodes; i++ {
-		serviceId, err := chainlinkNetwork.AddGethService()
-		if err != nil {
-			return nil, stacktrace.Propagate(err, "Failed to add an ethereum node.")
-		}
-		logrus.Infof("Added a geth service with id: %v", serviceId)
-		test.validatorIds = append(test.validatorIds, serviceId)
+	if len(fatallyErroredEthTxes) != 0 {
+		return stacktrace.NewError("Oracle has transactions that fatally errored: %+v", fatallyErroredEthTxes)
 	}
-
-	return chainlinkNetwork, nil
+	return nil
 }
 
-func (test *LinkContractInitializationTest) Run(network networks.Network, testCtx testsuite.TestContext) {
-	// Necessary because Go doesn't have generics
-	chainlinkNetwork := network.(*networks_impl.ChainlinkNetwork)
-
-	logrus.Infof("Manually connecting all nodes of the Ethereum network.")
-	err := chainlinkNetwork.ManuallyConnectPeers()
-	if err != nil {
-		testCtx.Fatal(stacktrace.Propagate(err, "Failed to manually connect peers in the network."))
+func checkOracleMetrics(chainlinkNetwork *networks_impl.ChainlinkNetwork, state *scenarios.State) error {
+	if err := chainlinkNetwork.StopMetricsCollection(); err != nil {
+		return stacktrace.Propagate(err, "Error stopping metrics collection.")
 	}
-
-	logrus.Infof("Deploying $LINK contracts on the testnet.")
-	err = chainlinkNetwork.DeployChainlinkContract()
+	completedRuns, err := chainlinkNetwork.GetOracleCompletedRunsIncrease()
 	if err != nil {
-		testCtx.Fatal(stacktrace.Propagate(err, "Failed to deploy the $LINK contract on the network."))
+		return stacktrace.Propagate(err, "Error getting the Oracle's completed runs from its metrics.")
 	}
-
-	logrus.Infof("Funding a $LINK wallet contract on the testnet.")
-	err = chainlinkNetwork.FundLinkWallet()
-	if err != nil {
-		testCtx.Fatal(stacktrace.Propagate(err, "Failed to fund a $LINK wallet on the network."))
+	if completedRuns < 1 {
+		return stacktrace.NewError("Oracle metrics report %v completed runs, but the job completed", completedRuns)
 	}
-
-	logrus.Infof("Starting a Chainlink Oracle node, using $LINK contract deployed at %v", chainlinkNetwork.GetLinkContractAddress())
-	err = chainlinkNetwork.AddOracleService()
+	headTrackerLag, err := chainlinkNetwork.GetOracleHeadTrackerLag()
 	if err != nil {
-		testCtx.Fatal(stacktrace.Propagate(err, "Error adding chainlink oracle to the network."))
+		return stacktrace.Propagate(err, "Error getting the Oracle's head tracker lag from metrics.")
 	}
-	logrus.Infof("Chainlink Oracle started and responsive on: %v:%v",
-		chainlinkNetwork.GetChainlinkOracle().GetIPAddress(),
-		chainlinkNetwork.GetChainlinkOracle().GetOperatorPort())
-
-	logrus.Infof("Funding ethereum accounts owned by the Oracle so that it can fulfill requests.")
-	err = chainlinkNetwork.FundOracleEthAccounts()
-	if err != nil {
-		testCtx.Fatal(stacktrace.Propagate(err, "Error funding Oracle accounts."))
+	if headTrackerLag > maxOracleHeadTrackerLag {
+		return stacktrace.NewError("Oracle head tracker is %v blocks behind the chain head, more than the allowed %v", headTrackerLag, maxOracleHeadTrackerLag)
 	}
-
-	logrus.Infof("Configuring and setting a JobSpec on the Oracle to access an example price feed.")
-	err = chainlinkNetwork.DeployOracleJob()
+	revertedTxs, err := chainlinkNetwork.GetOracleTxManagerRevertsIncrease()
 	if err != nil {
-		testCtx.Fatal(stacktrace.Propagate(err, "Error deploying Oracle job."))
+		return stacktrace.Propagate(err, "Error getting the Oracle's reverted transactions from metrics.")
 	}
-
-	logrus.Infof("Using on-chain smart contracts to trigger job from the Oracle smart contract.")
-	err = chainlinkNetwork.RequestData()
-	if err != nil {
-		testCtx.Fatal(stacktrace.Propagate(err, "Error requesting data from Chainlink oracle."))
+	if revertedTxs != 0 {
+		return stacktrace.NewError("Oracle tx manager saw %v transactions revert", revertedTxs)
 	}
-
-	logrus.Infof("Oracle successfully ran job accessing a remote price feed URL.")
+	return nil
 }
-
-
-func (test *LinkContractInitializationTest) GetTestConfiguration() testsuite.TestConfiguration {
-	return testsuite.TestConfiguration{
-		FilesArtifactUrls: map[services.FilesArtifactID]string{
-			gethDataDirArtifactId: gethDataDirArtifactUrl,
-		},
-	}
-}
-
-func (test *LinkContractInitializationTest) GetExecutionTimeout() time.Duration {
-	return 30000 * time.Second
-}
-
-func (test *LinkContractInitializationTest) GetSetupTimeout() time.Duration {
-	return 30000 * time.Second
-}
-
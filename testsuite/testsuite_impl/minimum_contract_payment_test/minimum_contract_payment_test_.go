@@ -0,0 +1,138 @@
+package minimum_contract_payment_test
+
+import (
+	"fmt"
+	"github.com/kurtosis-tech/kurtosis-libs/golang/lib/networks"
+	"github.com/kurtosis-tech/kurtosis-libs/golang/lib/testsuite"
+	"github.com/kurtosistech/chainlink-testing/testsuite/networks_impl"
+	"github.com/kurtosistech/chainlink-testing/testsuite/scenarios"
+	"github.com/kurtosistech/chainlink-testing/testsuite/services_impl/chainlink_oracle"
+	"github.com/kurtosistech/chainlink-testing/testsuite/testsuite_impl/test_base"
+	"github.com/palantir/stacktrace"
+	"math/big"
+	"time"
+)
+
+const (
+	requestFromUnfundedConsumerStepName = "request data from an unfunded consumer"
+	requestFromSpentConsumerStepName = "request data from the consumer that spent its $LINK"
+
+	// All amounts are in juels, i.e. 10^-18 $LINK
+	minimumContractPaymentJuels = "1000000000000000000"
+	sufficientPaymentJuels = "2000000000000000000"
+	insufficientPaymentJuels = "500000000000000000"
+	// Exactly enough for one sufficient and one insufficient request, leaving the consumer with nothing afterwards
+	consumerFundingJuels = "2500000000000000000"
+
+	// How long to give the Oracle to pick up requests that shouldn't get a run, before checking that none did
+	unexpectedRunGracePeriod = 15 * time.Second
+)
+
+type MinimumContractPaymentTest struct {
+	test_base.ChainlinkTestBase
+}
+
+func NewMinimumContractPaymentTest(base test_base.ChainlinkTestBase) *MinimumContractPaymentTest {
+	base.Topology = base.Topology.WithOracleEnvironment(chainlink_oracle.MinimumContractPaymentEnvVar, minimumContractPaymentJuels)
+	return &MinimumContractPaymentTest{
+		ChainlinkTestBase: base,
+	}
+}
+
+func (test *MinimumContractPaymentTest) Run(network networks.Network, testCtx testsuite.TestContext) {
+	test.RunScenario(network, testCtx,
+		scenarios.ConnectGethPeersStep(),
+		scenarios.DeployContractsStep(),
+		scenarios.FundLinkWalletWithAmountStep(consumerFundingJuels),
+		scenarios.StartOracleStep(),
+		scenarios.FundOracleEthAccountsStep(),
+		scenarios.DeployPriceFeedJobStep(),
+		requestDataPayingStep(sufficientPaymentJuels, chainlink_oracle.RunStatusCompleted),
+		requestDataPayingStep(insufficientPaymentJuels, chainlink_oracle.RunStatusErrored),
+		scenarios.NewStep(requestFromUnfundedConsumerStepName, requestFromUnfundedConsumer),
+		scenarios.NewStep(requestFromSpentConsumerStepName, requestFromSpentConsumer))
+}
+
+// ==========================================================================================
+//								Helper methods
+// ==========================================================================================
+
+/*
+	Requests data from the funded consumer paying the given amount, checking that the Oracle's run for the request ends
+	with the given status and records the payment.
+ */
+func requestDataPayingStep(paymentJuels string, expectedStatus string) scenarios.Step {
+	stepName := fmt.Sprintf("request data paying %v juels", paymentJuels)
+	return scenarios.NewStep(stepName, func(chainlinkNetwork *networks_impl.ChainlinkNetwork, state *scenarios.State) error {
+		txHash, isReverted, err := chainlinkNetwork.SendDataRequestWithPayment("", paymentJuels)
+		if err != nil {
+			return stacktrace.Propagate(err, "Error requesting data paying %v juels.", paymentJuels)
+		}
+		if isReverted {
+			return stacktrace.NewError("The funded consumer's request paying %v juels was rejected on-chain.", paymentJuels)
+		}
+		run, err := chainlinkNetwork.WaitForRequestRunToFinish(txHash)
+		if err != nil {
+			return stacktrace.Propagate(err, "Error waiting for the run of the request paying %v juels.", paymentJuels)
+		}
+		if run.Attributes.Status != expectedStatus {
+			return stacktrace.NewError("Expected the run for the request paying %v juels, against a minimum of %v, to end with status '%v', but it ended with status '%v'.",
+				paymentJuels, minimumContractPaymentJuels, expectedStatus, run.Attributes.Status)
+		}
+		expectedPayment, _ := new(big.Int).SetString(paymentJuels, 10)
+		payment, ok := new(big.Int).SetString(run.Attributes.Payment, 10)
+		if !ok || payment.Cmp(expectedPayment) != 0 {
+			return stacktrace.NewError("Expected run %v to record a payment of %v juels, but it recorded '%v'.",
+				run.Attributes.Id, paymentJuels, run.Attributes.Payment)
+		}
+		return nil
+	})
+}
+
+func requestFromUnfundedConsumer(chainlinkNetwork *networks_impl.ChainlinkNetwork, state *scenarios.State) error {
+	unfundedConsumerAddress, err := chainlinkNetwork.DeployUnfundedConsumer()
+	if err != nil {
+		return stacktrace.Propagate(err, "Error deploying an unfunded consumer contract.")
+	}
+	return checkRequestRejectedOnChain(chainlinkNetwork, state, unfundedConsumerAddress, "the unfunded consumer")
+}
+
+/*
+	The funded consumer has spent all its $LINK on the requests before this.
+ */
+func requestFromSpentConsumer(chainlinkNetwork *networks_impl.ChainlinkNetwork, state *scenarios.State) error {
+	return checkRequestRejectedOnChain(chainlinkNetwork, state, "", "the consumer that spent its $LINK")
+}
+
+/*
+	Checks that a request paying more than the minimum through the given consumer is rejected on-chain, and that the
+	Oracle doesn't start a run for it.
+ */
+func checkRequestRejectedOnChain(chainlinkNetwork *networks_impl.ChainlinkNetwork, state *scenarios.State,
+		consumerAddress string, consumerDescription string) error {
+	oracleService := chainlinkNetwork.GetChainlinkOracle()
+	priceFeedJobId := state.JobIds[scenarios.PriceFeedJobName]
+	runsBefore, err := oracleService.GetRunsForJob(priceFeedJobId)
+	if err != nil {
+		return stacktrace.Propagate(err, "Error getting the runs of job %v.", priceFeedJobId)
+	}
+
+	_, isReverted, err := chainlinkNetwork.SendDataRequestWithPayment(consumerAddress, sufficientPaymentJuels)
+	if err != nil {
+		return stacktrace.Propagate(err, "Error requesting data from %v.", consumerDescription)
+	}
+	if !isReverted {
+		return stacktrace.NewError("Expected the request from %v to be rejected on-chain, but it went through.", consumerDescription)
+	}
+
+	time.Sleep(unexpectedRunGracePeriod)
+	runsAfter, err := oracleService.GetRunsForJob(priceFeedJobId)
+	if err != nil {
+		return stacktrace.Propagate(err, "Error getting the runs of job %v.", priceFeedJobId)
+	}
+	if len(runsAfter) != len(runsBefore) {
+		return stacktrace.NewError("Expected no run for the request from %v, but job %v went from %v runs to %v.",
+			consumerDescription, priceFeedJobId, len(runsBefore), len(runsAfter))
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package network_spec_test
+
+import (
+	"github.com/kurtosis-tech/kurtosis-libs/golang/lib/networks"
+	"github.com/kurtosis-tech/kurtosis-libs/golang/lib/testsuite"
+	"github.com/kurtosistech/chainlink-testing/testsuite/network_spec"
+	"github.com/kurtosistech/chainlink-testing/testsuite/networks_impl"
+	"github.com/kurtosistech/chainlink-testing/testsuite/testsuite_impl/test_base"
+	"github.com/palantir/stacktrace"
+)
+
+const (
+	// Suffix of the names network spec tests are registered under, after the spec's name
+	TestNameSuffix = "NetworkSpecTest"
+)
+
+/*
+	Builds the network a spec file describes and runs the spec's scenario against it, so new scenarios can be added
+	as data files rather than test packages.
+ */
+type NetworkSpecTest struct {
+	// The base's topology is the spec's, with the version label the testsuite gives it
+	test_base.ChainlinkTestBase
+	spec network_spec.NetworkSpec
+}
+
+func NewNetworkSpecTest(base test_base.ChainlinkTestBase, spec network_spec.NetworkSpec) *NetworkSpecTest {
+	return &NetworkSpecTest{
+		ChainlinkTestBase: base,
+		spec: spec,
+	}
+}
+
+func (test *NetworkSpecTest) Setup(networkCtx *networks.NetworkContext) (networks.Network, error) {
+	builder := network_spec.NewNetworkBuilder(test_base.GethDataDirArtifactId,
+		test.GethServiceImage,
+		test.ChainlinkContractDeployerImage,
+		test.PostgresImage,
+		test.ChainlinkOracleImage,
+		test.PriceFeedServerImage,
+		test.Topology.VersionLabel)
+	chainlinkNetwork, err := builder.Build(networkCtx, test.spec)
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "Error building the network described by spec '%v'.", test.spec.Name)
+	}
+	return chainlinkNetwork, nil
+}
+
+func (test *NetworkSpecTest) Run(network networks.Network, testCtx testsuite.TestContext) {
+	// Necessary because Go doesn't have generics
+	chainlinkNetwork := network.(*networks_impl.ChainlinkNetwork)
+	defer chainlinkNetwork.DumpArtifactsOnFailure(test.TestName)
+
+	scenario, err := network_spec.BuildScenario(test.spec.Name, chainlinkNetwork, test.spec.Scenario)
+	if err != nil {
+		testCtx.Fatal(stacktrace.Propagate(err, "Error building the scenario of spec '%v'.", test.spec.Name))
+	}
+	if err := scenario.Run(); err != nil {
+		testCtx.Fatal(stacktrace.Propagate(err, "Error running the scenario of spec '%v'.", test.spec.Name))
+	}
+}
@@ -0,0 +1,239 @@
+package oracle_key_management_test
+
+import (
+	"github.com/kurtosis-tech/kurtosis-libs/golang/lib/networks"
+	"github.com/kurtosis-tech/kurtosis-libs/golang/lib/testsuite"
+	"github.com/kurtosistech/chainlink-testing/testsuite/networks_impl"
+	"github.com/kurtosistech/chainlink-testing/testsuite/scenarios"
+	"github.com/kurtosistech/chainlink-testing/testsuite/services_impl/chainlink_oracle"
+	"github.com/kurtosistech/chainlink-testing/testsuite/services_impl/geth"
+	"github.com/kurtosistech/chainlink-testing/testsuite/testsuite_impl/test_base"
+	"github.com/palantir/stacktrace"
+	"github.com/sirupsen/logrus"
+	"strings"
+)
+
+const (
+	createKeysStepName = "create keys"
+	roundTripKeyStepName = "export, delete and import back a key"
+	importGethAccountStepName = "import geth account"
+	checkKeysFundedStepName = "check every key is funded"
+	checkKeyRotationStepName = "check fulfillments rotate between keys"
+
+	numCreatedKeys = 2
+	// Password the key that's exported and imported back is encrypted with in between
+	exportPassword = "export-password"
+	// The prefunded account reserved for importing into Oracles, which nothing else sends transactions from
+	importedGethAccount = geth.KeyImportFundedAddress
+
+	// Enough requests for the node to send fulfillments from every key if it rotates between them
+	numRequestsPerKey = 2
+	// 1 $LINK, in juels
+	requestPaymentJuels = "1000000000000000000"
+)
+
+
+type OracleKeyManagementTest struct {
+	test_base.ChainlinkTestBase
+}
+
+func NewOracleKeyManagementTest(base test_base.ChainlinkTestBase) *OracleKeyManagementTest {
+	// The base's Setup validates the topology, rejecting it if the account has another role in the network
+	base.Topology = base.Topology.WithOracleImportedAccount(importedGethAccount)
+	return &OracleKeyManagementTest{
+		ChainlinkTestBase: base,
+	}
+}
+
+func (test *OracleKeyManagementTest) Run(network networks.Network, testCtx testsuite.TestContext) {
+	test.RunScenario(network, testCtx,
+		scenarios.ConnectGethPeersStep(),
+		scenarios.DeployContractsStep(),
+		scenarios.FundLinkWalletStep(),
+		scenarios.StartOracleStep(),
+		scenarios.NewStep(createKeysStepName, createKeys),
+		scenarios.NewStep(roundTripKeyStepName, roundTripKey),
+		scenarios.NewStep(importGethAccountStepName, importGethAccount),
+		scenarios.FundOracleEthAccountsStep(),
+		scenarios.NewStep(checkKeysFundedStepName, checkKeysFunded),
+		scenarios.DeployPriceFeedJobStep(),
+		scenarios.NewStep(checkKeyRotationStepName, checkKeyRotation))
+}
+
+// ==========================================================================================
+//								Helper methods
+// ==========================================================================================
+
+func createKeys(chainlinkNetwork *networks_impl.ChainlinkNetwork, state *scenarios.State) error {
+	for keyIdx := 0; keyIdx < numCreatedKeys; keyIdx++ {
+		createdKey, err := chainlinkNetwork.GetChainlinkOracle().CreateEthKey()
+		if err != nil {
+			return stacktrace.Propagate(err, "Error creating key %v on the Oracle.", keyIdx)
+		}
+		logrus.Infof("Created key %v on the Oracle.", createdKey.Attributes.Address)
+	}
+	return nil
+}
+
+/*
+	Creates a key, exports it, deletes it and imports it back, checking that it's gone in between and that it comes
+	back with the same address.
+ */
+func roundTripKey(chainlinkNetwork *networks_impl.ChainlinkNetwork, state *scenarios.State) error {
+	oracleService := chainlinkNetwork.GetChainlinkOracle()
+	key, err := oracleService.CreateEthKey()
+	if err != nil {
+		return stacktrace.Propagate(err, "Error creating a key to export on the Oracle.")
+	}
+	address := key.Attributes.Address
+	keystoreJson, err := oracleService.ExportEthKey(address, exportPassword)
+	if err != nil {
+		return stacktrace.Propagate(err, "Error exporting key %v from the Oracle.", address)
+	}
+	if err := oracleService.DeleteEthKey(address); err != nil {
+		return stacktrace.Propagate(err, "Error deleting key %v from the Oracle.", address)
+	}
+	isListed, err := isOracleKey(oracleService, address)
+	if err != nil {
+		return err
+	}
+	if isListed {
+		return stacktrace.NewError("Expected key %v not to be listed after deleting it, but it still is.", address)
+	}
+
+	importedKey, err := oracleService.ImportEthKey(keystoreJson, exportPassword)
+	if err != nil {
+		return stacktrace.Propagate(err, "Error importing exported key %v back into the Oracle.", address)
+	}
+	if !strings.EqualFold(importedKey.Attributes.Address, address) {
+		return stacktrace.NewError("Expected importing exported key %v to give it back, but got key %v.", address, importedKey.Attributes.Address)
+	}
+	isListed, err = isOracleKey(oracleService, address)
+	if err != nil {
+		return err
+	}
+	if !isListed {
+		return stacktrace.NewError("Expected key %v to be listed again after importing it back, but it isn't.", address)
+	}
+	return nil
+}
+
+func importGethAccount(chainlinkNetwork *networks_impl.ChainlinkNetwork, state *scenarios.State) error {
+	oracleService := chainlinkNetwork.GetChainlinkOracle()
+	if err := chainlinkNetwork.ImportGethAccountIntoOracle(importedGethAccount); err != nil {
+		return stacktrace.Propagate(err, "Error importing geth account %v into the Oracle.", importedGethAccount)
+	}
+	isListed, err := isOracleKey(oracleService, importedGethAccount)
+	if err != nil {
+		return err
+	}
+	if !isListed {
+		return stacktrace.NewError("Expected the imported geth account %v to be listed among the Oracle's keys, but it isn't.", importedGethAccount)
+	}
+
+	oracleKeys, err := oracleService.GetEthAccounts()
+	if err != nil {
+		return stacktrace.Propagate(err, "Error getting the Oracle's keys.")
+	}
+	// The key the node starts with, the created keys including the one that was round tripped, and the imported one
+	expectedNumKeys := 1 + numCreatedKeys + 1 + 1
+	if len(oracleKeys) != expectedNumKeys {
+		return stacktrace.NewError("Expected the Oracle to have %v keys, but it has %v.", expectedNumKeys, len(oracleKeys))
+	}
+	return nil
+}
+
+func checkKeysFunded(chainlinkNetwork *networks_impl.ChainlinkNetwork, state *scenarios.State) error {
+	oracleKeys, err := chainlinkNetwork.GetChainlinkOracle().GetEthAccounts()
+	if err != nil {
+		return stacktrace.Propagate(err, "Error getting the Oracle's keys.")
+	}
+	for _, oracleKey := range oracleKeys {
+		address := oracleKey.Attributes.Address
+		ethBalance, err := chainlinkNetwork.GetEthBalance(address)
+		if err != nil {
+			return stacktrace.Propagate(err, "Error getting the ETH balance of Oracle key %v.", address)
+		}
+		if ethBalance.Sign() <= 0 {
+			return stacktrace.NewError("Expected funding the Oracle's accounts to fund key %v, but it has no ETH.", address)
+		}
+	}
+	return nil
+}
+
+/*
+	Sends enough requests for every key to send some of the fulfillments, checking that each one does.
+ */
+func checkKeyRotation(chainlinkNetwork *networks_impl.ChainlinkNetwork, state *scenarios.State) error {
+	oracleKeys, err := chainlinkNetwork.GetChainlinkOracle().GetEthAccounts()
+	if err != nil {
+		return stacktrace.Propagate(err, "Error getting the Oracle's keys.")
+	}
+	// Key address in lowercase -> number of fulfillments sent from it
+	numFulfillmentsByKey := map[string]int{}
+	for _, oracleKey := range oracleKeys {
+		numFulfillmentsByKey[strings.ToLower(oracleKey.Attributes.Address)] = 0
+	}
+	numRequests := numRequestsPerKey * len(oracleKeys)
+	for requestIdx := 0; requestIdx < numRequests; requestIdx++ {
+		// Sending a request sets fulfillment permissions for every key first
+		txHash, isReverted, err := chainlinkNetwork.SendDataRequestWithPayment("", requestPaymentJuels)
+		if err != nil {
+			return stacktrace.Propagate(err, "Error sending request %v.", requestIdx)
+		}
+		if isReverted {
+			return stacktrace.NewError("Request %v was rejected on-chain.", requestIdx)
+		}
+		if requestIdx == 0 {
+			for address := range numFulfillmentsByKey {
+				isAuthorized, err := chainlinkNetwork.IsAuthorizedOracleNode(address)
+				if err != nil {
+					return stacktrace.Propagate(err, "Error checking whether key %v may fulfill requests.", address)
+				}
+				if !isAuthorized {
+					return stacktrace.NewError("Expected every Oracle key to be allowed to fulfill requests, but %v isn't.", address)
+				}
+			}
+		}
+		run, err := chainlinkNetwork.WaitForRequestRunToFinish(txHash)
+		if err != nil {
+			return stacktrace.Propagate(err, "Error waiting for the run of request %v.", requestIdx)
+		}
+		if run.Attributes.Status != chainlink_oracle.RunStatusCompleted {
+			return stacktrace.NewError("Expected the run of request %v to complete, but it ended with status '%v'.",
+				requestIdx, run.Attributes.Status)
+		}
+		fulfillmentTransaction, err := chainlinkNetwork.GetFulfillmentTransaction(run)
+		if err != nil {
+			return stacktrace.Propagate(err, "Error getting the fulfillment transaction of request %v.", requestIdx)
+		}
+		sender := strings.ToLower(fulfillmentTransaction.From)
+		numFulfillments, isFromOracleKey := numFulfillmentsByKey[sender]
+		if !isFromOracleKey {
+			return stacktrace.NewError("Request %v was fulfilled from %v, which isn't one of the Oracle's keys.", requestIdx, sender)
+		}
+		numFulfillmentsByKey[sender] = numFulfillments + 1
+		logrus.Infof("Request %v was fulfilled from key %v.", requestIdx, sender)
+	}
+
+	for address, numFulfillments := range numFulfillmentsByKey {
+		if numFulfillments == 0 {
+			return stacktrace.NewError("Expected the Oracle to rotate between its keys, but it sent none of the %v fulfillments from key %v; fulfillments by key: %v",
+				numRequests, address, numFulfillmentsByKey)
+		}
+	}
+	return nil
+}
+
+func isOracleKey(oracleService chainlink_oracle.ChainlinkOracle, address string) (bool, error) {
+	oracleKeys, err := oracleService.GetEthAccounts()
+	if err != nil {
+		return false, stacktrace.Propagate(err, "Error getting the Oracle's keys.")
+	}
+	for _, oracleKey := range oracleKeys {
+		if strings.EqualFold(oracleKey.Attributes.Address, address) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
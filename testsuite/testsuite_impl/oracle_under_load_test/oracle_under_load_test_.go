@@ -0,0 +1,113 @@
+package oracle_under_load_test
+
+import (
+	"github.com/kurtosis-tech/kurtosis-libs/golang/lib/networks"
+	"github.com/kurtosis-tech/kurtosis-libs/golang/lib/testsuite"
+	"github.com/kurtosistech/chainlink-testing/testsuite/networks_impl"
+	"github.com/kurtosistech/chainlink-testing/testsuite/scenarios"
+	"github.com/kurtosistech/chainlink-testing/testsuite/testsuite_impl/test_base"
+	"github.com/palantir/stacktrace"
+	"github.com/sirupsen/logrus"
+	"time"
+)
+
+const (
+	requestDataUnderLoadStepName = "fulfill request under load"
+	measureGasUtilizationStepName = "measure block gas utilization"
+
+	// Each load transaction uses 1M gas, so 20 transactions/s asks for twice what fits under the 10M block gas limit
+	loadTransactionsPerSecond = 20
+	loadGasPerTransaction = 1000000
+
+	// Time to let the load build up a txpool backlog before the oracle request goes out
+	loadWarmupDuration = 10 * time.Second
+
+	// With twice the block gas limit asked for, blocks are only this empty if the load didn't congest them, leaving
+	// nothing measured; 1M gas transactions can leave up to a tenth of a block unfilled
+	minAverageGasUtilization = 0.8
+	// The Oracle has to keep up under congestion, not merely get the request in eventually
+	maxFulfillmentLatency = 60 * time.Second
+)
+
+type OracleUnderLoadTest struct {
+	test_base.ChainlinkTestBase
+}
+
+func NewOracleUnderLoadTest(base test_base.ChainlinkTestBase) *OracleUnderLoadTest {
+	return &OracleUnderLoadTest{
+		ChainlinkTestBase: base,
+	}
+}
+
+func (test *OracleUnderLoadTest) Run(network networks.Network, testCtx testsuite.TestContext) {
+	logrus.Infof("Running under transaction load of %v transactions/s at %v gas each.", loadTransactionsPerSecond, loadGasPerTransaction)
+	measurement := &loadMeasurement{}
+	test.RunScenario(network, testCtx,
+		scenarios.ConnectGethPeersStep(),
+		scenarios.DeployContractsStep(),
+		scenarios.FundLinkWalletStep(),
+		scenarios.StartOracleStep(),
+		scenarios.FundOracleEthAccountsStep(),
+		scenarios.DeployPriceFeedJobStep(),
+		// Its cleanup stops the load if a later step fails before the load is stopped
+		scenarios.StartTransactionLoadStep(loadTransactionsPerSecond, loadGasPerTransaction),
+		scenarios.SleepStep(loadWarmupDuration),
+		scenarios.NewStep(requestDataUnderLoadStepName, measurement.requestDataUnderLoad),
+		scenarios.StopTransactionLoadStep(),
+		scenarios.NewStep(measureGasUtilizationStepName, measurement.measureGasUtilization))
+}
+
+// ==========================================================================================
+//								Helper methods
+// ==========================================================================================
+
+/*
+	The blocks and latency of the request made under load, handed on to the step that measures how full those blocks
+	were and checks both against the bounds above.
+ */
+type loadMeasurement struct {
+	startBlock uint64
+	endBlock uint64
+	fulfillmentLatency time.Duration
+}
+
+func (measurement *loadMeasurement) requestDataUnderLoad(chainlinkNetwork *networks_impl.ChainlinkNetwork, state *scenarios.State) error {
+	startBlock, err := chainlinkNetwork.GetBootstrapper().GetBlockNumber()
+	if err != nil {
+		return stacktrace.Propagate(err, "Error getting the block number before requesting data.")
+	}
+	requestStartTime := time.Now()
+	if err := chainlinkNetwork.RequestData(); err != nil {
+		return stacktrace.Propagate(err, "Error requesting data from Chainlink oracle under load.")
+	}
+	fulfillmentLatency := time.Since(requestStartTime)
+	endBlock, err := chainlinkNetwork.GetBootstrapper().GetBlockNumber()
+	if err != nil {
+		return stacktrace.Propagate(err, "Error getting the block number after requesting data.")
+	}
+	measurement.startBlock = startBlock
+	measurement.endBlock = endBlock
+	measurement.fulfillmentLatency = fulfillmentLatency
+	return nil
+}
+
+func (measurement *loadMeasurement) measureGasUtilization(chainlinkNetwork *networks_impl.ChainlinkNetwork, state *scenarios.State) error {
+	gasUtilization, err := chainlinkNetwork.GetAverageBlockGasUtilization(measurement.startBlock, measurement.endBlock)
+	if err != nil {
+		return stacktrace.Propagate(err, "Error measuring block gas utilization.")
+	}
+	logrus.Infof("Oracle fulfilled the request in %v across blocks %v to %v, with blocks %.1f%% full on average.",
+		measurement.fulfillmentLatency,
+		measurement.startBlock,
+		measurement.endBlock,
+		gasUtilization * 100)
+	if gasUtilization < minAverageGasUtilization {
+		return stacktrace.NewError("Expected blocks %v to %v to be at least %.1f%% full on average under load, but they were %.1f%% full.",
+			measurement.startBlock, measurement.endBlock, minAverageGasUtilization * 100, gasUtilization * 100)
+	}
+	if measurement.fulfillmentLatency > maxFulfillmentLatency {
+		return stacktrace.NewError("Expected the Oracle to fulfill the request under load within %v, but it took %v.",
+			maxFulfillmentLatency, measurement.fulfillmentLatency)
+	}
+	return nil
+}
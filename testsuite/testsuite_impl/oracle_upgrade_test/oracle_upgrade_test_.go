@@ -0,0 +1,193 @@
+package oracle_upgrade_test
+
+import (
+	"github.com/kurtosis-tech/kurtosis-libs/golang/lib/networks"
+	"github.com/kurtosis-tech/kurtosis-libs/golang/lib/testsuite"
+	"github.com/kurtosistech/chainlink-testing/testsuite/networks_impl"
+	"github.com/kurtosistech/chainlink-testing/testsuite/scenarios"
+	"github.com/kurtosistech/chainlink-testing/testsuite/services_impl/chainlink_oracle"
+	"github.com/kurtosistech/chainlink-testing/testsuite/testsuite_impl/test_base"
+	"github.com/palantir/stacktrace"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	requestDataBeforeUpgradeStepName = "fulfill request before the upgrade"
+	runWebJobBeforeUpgradeStepName = "run web initiated job before the upgrade"
+	recordOracleStateStepName = "record the Oracle's state before the upgrade"
+	checkOracleStateStepName = "check the upgraded Oracle kept its state"
+	runWebJobAfterUpgradeStepName = "run web initiated job after the upgrade"
+	requestDataAfterUpgradeStepName = "fulfill request after the upgrade"
+)
+
+/*
+	Runs jobs on an Oracle, then upgrades it in place to another image against the same database, checking that the
+	new image migrates the database and carries on with the old image's keys, jobs and runs.
+ */
+type OracleUpgradeTest struct {
+	// The base's Oracle image is the one the Oracle is upgraded to
+	test_base.ChainlinkTestBase
+	// Image the Oracle starts on
+	chainlinkOracleUpgradeFromImage string
+}
+
+func NewOracleUpgradeTest(base test_base.ChainlinkTestBase, chainlinkOracleUpgradeFromImage string) *OracleUpgradeTest {
+	return &OracleUpgradeTest{
+		ChainlinkTestBase: base,
+		chainlinkOracleUpgradeFromImage: chainlinkOracleUpgradeFromImage,
+	}
+}
+
+func (test *OracleUpgradeTest) Setup(networkCtx *networks.NetworkContext) (networks.Network, error) {
+	chainlinkNetwork, err := test.SetupNetwork(networkCtx, test.chainlinkOracleUpgradeFromImage)
+	if err != nil {
+		return nil, err
+	}
+	return chainlinkNetwork, nil
+}
+
+func (test *OracleUpgradeTest) Run(network networks.Network, testCtx testsuite.TestContext) {
+	logrus.Infof("Starting the Oracle on image '%v', to upgrade it to '%v'.", test.chainlinkOracleUpgradeFromImage, test.ChainlinkOracleImage)
+	upgrade := &oracleUpgrade{}
+	test.RunScenario(network, testCtx,
+		scenarios.ConnectGethPeersStep(),
+		scenarios.DeployContractsStep(),
+		scenarios.FundLinkWalletStep(),
+		scenarios.DeploySimpleConsumerStep(),
+		scenarios.StartOracleStep(),
+		scenarios.FundOracleEthAccountsStep(),
+		scenarios.DeployPriceFeedJobStep(),
+		scenarios.RequestDataStep().WithName(requestDataBeforeUpgradeStepName),
+		scenarios.AddWebJobStep(),
+		scenarios.NewStep(runWebJobBeforeUpgradeStepName, upgrade.runWebJobBeforeUpgrade),
+		scenarios.NewStep(recordOracleStateStepName, upgrade.recordOracleState),
+		scenarios.UpgradeOracleStep(test.ChainlinkOracleImage),
+		scenarios.NewStep(checkOracleStateStepName, upgrade.checkOracleState),
+		// Both jobs were created on the old image, so running them shows the upgraded Oracle can use what it inherited
+		scenarios.NewStep(runWebJobAfterUpgradeStepName, runWebJobAfterUpgrade),
+		scenarios.RequestDataStep().WithName(requestDataAfterUpgradeStepName))
+}
+
+// ==========================================================================================
+//								Helper methods
+// ==========================================================================================
+
+/*
+	What the Oracle had before the upgrade, handed on to the step that checks the upgraded Oracle still has it.
+ */
+type oracleUpgrade struct {
+	preUpgradeRun chainlink_oracle.Run
+	preUpgradeMigrations []string
+	preUpgradeJobIds []string
+	preUpgradeKeyAddresses []string
+}
+
+func (upgrade *oracleUpgrade) runWebJobBeforeUpgrade(chainlinkNetwork *networks_impl.ChainlinkNetwork, state *scenarios.State) error {
+	webJobId := state.JobIds[scenarios.WebJobName]
+	run, err := chainlinkNetwork.RunWebJob(webJobId)
+	if err != nil {
+		return stacktrace.Propagate(err, "Error running web initiated job %v before the upgrade.", webJobId)
+	}
+	upgrade.preUpgradeRun = run
+	return nil
+}
+
+func (upgrade *oracleUpgrade) recordOracleState(chainlinkNetwork *networks_impl.ChainlinkNetwork, state *scenarios.State) error {
+	migrations, err := chainlinkNetwork.GetOracleDatabaseMigrations()
+	if err != nil {
+		return stacktrace.Propagate(err, "Error getting the Oracle's migrations before the upgrade.")
+	}
+	jobIds, err := chainlinkNetwork.GetOracleDatabaseJobIds()
+	if err != nil {
+		return stacktrace.Propagate(err, "Error getting the Oracle's jobs before the upgrade.")
+	}
+	keyAddresses, err := getEthKeyAddresses(chainlinkNetwork.GetChainlinkOracle())
+	if err != nil {
+		return stacktrace.Propagate(err, "Error getting the Oracle's eth keys before the upgrade.")
+	}
+	upgrade.preUpgradeMigrations = migrations
+	upgrade.preUpgradeJobIds = jobIds
+	upgrade.preUpgradeKeyAddresses = keyAddresses
+	return nil
+}
+
+func (upgrade *oracleUpgrade) checkOracleState(chainlinkNetwork *networks_impl.ChainlinkNetwork, state *scenarios.State) error {
+	postUpgradeMigrations, err := chainlinkNetwork.GetOracleDatabaseMigrations()
+	if err != nil {
+		return stacktrace.Propagate(err, "Error getting the Oracle's migrations after the upgrade.")
+	}
+	newMigrations := getMissingStrings(postUpgradeMigrations, upgrade.preUpgradeMigrations)
+	logrus.Infof("The upgraded Oracle applied %v new migrations: %v", len(newMigrations), newMigrations)
+	lostMigrations := getMissingStrings(upgrade.preUpgradeMigrations, postUpgradeMigrations)
+	if len(lostMigrations) != 0 {
+		return stacktrace.NewError("Expected the upgraded Oracle to keep every migration already applied, but %v are gone.", lostMigrations)
+	}
+
+	postUpgradeJobIds, err := chainlinkNetwork.GetOracleDatabaseJobIds()
+	if err != nil {
+		return stacktrace.Propagate(err, "Error getting the Oracle's jobs after the upgrade.")
+	}
+	lostJobIds := getMissingStrings(upgrade.preUpgradeJobIds, postUpgradeJobIds)
+	if len(lostJobIds) != 0 {
+		return stacktrace.NewError("Expected the upgraded Oracle to keep all %v jobs, but %v are gone.", len(upgrade.preUpgradeJobIds), lostJobIds)
+	}
+
+	postUpgradeKeyAddresses, err := getEthKeyAddresses(chainlinkNetwork.GetChainlinkOracle())
+	if err != nil {
+		return stacktrace.Propagate(err, "Error getting the Oracle's eth keys after the upgrade.")
+	}
+	lostKeyAddresses := getMissingStrings(upgrade.preUpgradeKeyAddresses, postUpgradeKeyAddresses)
+	if len(lostKeyAddresses) != 0 {
+		return stacktrace.NewError("Expected the upgraded Oracle to keep its keystore, but keys %v are gone.", lostKeyAddresses)
+	}
+
+	preUpgradeRun := upgrade.preUpgradeRun
+	preservedRun, err := chainlinkNetwork.GetChainlinkOracle().GetRun(preUpgradeRun.Attributes.Id)
+	if err != nil {
+		return stacktrace.Propagate(err, "Error getting run %v from before the upgrade.", preUpgradeRun.Attributes.Id)
+	}
+	if preservedRun.Attributes.Status != preUpgradeRun.Attributes.Status {
+		return stacktrace.NewError("Expected run %v from before the upgrade to still be '%v', but it's '%v'.",
+			preUpgradeRun.Attributes.Id, preUpgradeRun.Attributes.Status, preservedRun.Attributes.Status)
+	}
+	return nil
+}
+
+func runWebJobAfterUpgrade(chainlinkNetwork *networks_impl.ChainlinkNetwork, state *scenarios.State) error {
+	webJobId := state.JobIds[scenarios.WebJobName]
+	run, err := chainlinkNetwork.RunWebJob(webJobId)
+	if err != nil {
+		return stacktrace.Propagate(err, "Error running web initiated job %v after the upgrade.", webJobId)
+	}
+	logrus.Infof("Run %v of web initiated job %v completed after the upgrade.", run.Attributes.Id, webJobId)
+	return nil
+}
+
+func getEthKeyAddresses(oracleService chainlink_oracle.ChainlinkOracle) ([]string, error) {
+	keys, err := oracleService.GetEthAccounts()
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "An error occurred getting the Oracle's eth keys")
+	}
+	addresses := []string{}
+	for _, key := range keys {
+		addresses = append(addresses, key.Attributes.Address)
+	}
+	return addresses, nil
+}
+
+/*
+	The strings in expected that aren't in actual.
+ */
+func getMissingStrings(expected []string, actual []string) []string {
+	isInActual := map[string]bool{}
+	for _, str := range actual {
+		isInActual[str] = true
+	}
+	missing := []string{}
+	for _, str := range expected {
+		if !isInActual[str] {
+			missing = append(missing, str)
+		}
+	}
+	return missing
+}
@@ -0,0 +1,203 @@
+package oracle_withdrawal_test
+
+import (
+	"fmt"
+	"github.com/kurtosis-tech/kurtosis-libs/golang/lib/networks"
+	"github.com/kurtosis-tech/kurtosis-libs/golang/lib/testsuite"
+	"github.com/kurtosistech/chainlink-testing/testsuite/networks_impl"
+	"github.com/kurtosistech/chainlink-testing/testsuite/scenarios"
+	"github.com/kurtosistech/chainlink-testing/testsuite/services_impl/chainlink_oracle"
+	"github.com/kurtosistech/chainlink-testing/testsuite/services_impl/geth"
+	"github.com/kurtosistech/chainlink-testing/testsuite/testsuite_impl/test_base"
+	"github.com/palantir/stacktrace"
+	"github.com/sirupsen/logrus"
+	"math/big"
+)
+
+const (
+	recordBalancesStepName = "record $LINK balances"
+	checkFulfillmentPaymentsStepName = "check fulfillment payments"
+	overdrawStepName = "withdraw more than is withdrawable"
+	withdrawStepName = "withdraw everything withdrawable"
+
+	numFulfillments = 3
+	// 1 $LINK, in juels
+	requestPaymentJuels = 1000000000000000000
+)
+
+type OracleWithdrawalTest struct {
+	test_base.ChainlinkTestBase
+}
+
+func NewOracleWithdrawalTest(base test_base.ChainlinkTestBase) *OracleWithdrawalTest {
+	return &OracleWithdrawalTest{
+		ChainlinkTestBase: base,
+	}
+}
+
+func (test *OracleWithdrawalTest) Run(network networks.Network, testCtx testsuite.TestContext) {
+	withdrawal := &oracleWithdrawal{}
+	test.RunScenario(network, testCtx,
+		scenarios.ConnectGethPeersStep(),
+		scenarios.DeployContractsStep(),
+		scenarios.FundLinkWalletStep(),
+		scenarios.StartOracleStep(),
+		scenarios.FundOracleEthAccountsStep(),
+		scenarios.DeployPriceFeedJobStep(),
+		scenarios.NewStep(recordBalancesStepName, withdrawal.recordBalances),
+		scenarios.NewStep(fmt.Sprintf("fulfill %v requests", numFulfillments), fulfillRequests),
+		scenarios.NewStep(checkFulfillmentPaymentsStepName, withdrawal.checkFulfillmentPayments),
+		scenarios.NewStep(overdrawStepName, withdrawal.overdraw),
+		scenarios.NewStep(withdrawStepName, withdrawal.withdraw))
+}
+
+// ==========================================================================================
+//								Helper methods
+// ==========================================================================================
+
+/*
+	The balances one run of the test tracks, handed on from step to step as the Oracle is paid and withdraws.
+ */
+type oracleWithdrawal struct {
+	balancesBefore networks_impl.LinkBalances
+	withdrawableBefore *big.Int
+
+	balancesAfterFulfillments networks_impl.LinkBalances
+	withdrawable *big.Int
+	// The Oracle node key the withdrawal pays, so the node gets paid for its work
+	recipientAddress string
+}
+
+func (withdrawal *oracleWithdrawal) recordBalances(chainlinkNetwork *networks_impl.ChainlinkNetwork, state *scenarios.State) error {
+	balances, err := chainlinkNetwork.GetLinkBalances()
+	if err != nil {
+		return stacktrace.Propagate(err, "Error getting $LINK balances.")
+	}
+	withdrawable, err := chainlinkNetwork.GetOracleWithdrawable()
+	if err != nil {
+		return stacktrace.Propagate(err, "Error getting the Oracle contract's withdrawable $LINK.")
+	}
+	withdrawal.balancesBefore = balances
+	withdrawal.withdrawableBefore = withdrawable
+	return nil
+}
+
+func fulfillRequests(chainlinkNetwork *networks_impl.ChainlinkNetwork, state *scenarios.State) error {
+	payment := big.NewInt(requestPaymentJuels)
+	for requestIdx := 0; requestIdx < numFulfillments; requestIdx++ {
+		txHash, isReverted, err := chainlinkNetwork.SendDataRequestWithPayment("", payment.String())
+		if err != nil {
+			return stacktrace.Propagate(err, "Error sending request %v.", requestIdx)
+		}
+		if isReverted {
+			return stacktrace.NewError("Request %v was rejected on-chain.", requestIdx)
+		}
+		run, err := chainlinkNetwork.WaitForRequestRunToFinish(txHash)
+		if err != nil {
+			return stacktrace.Propagate(err, "Error waiting for the run of request %v.", requestIdx)
+		}
+		if run.Attributes.Status != chainlink_oracle.RunStatusCompleted {
+			return stacktrace.NewError("Expected the run of request %v to complete, but it ended with status '%v'.",
+				requestIdx, run.Attributes.Status)
+		}
+		logrus.Infof("Request %v was fulfilled.", requestIdx)
+	}
+	return nil
+}
+
+/*
+	Checks that the fulfilled requests' payments went from the consumer to the Oracle contract, where they're
+	withdrawable.
+ */
+func (withdrawal *oracleWithdrawal) checkFulfillmentPayments(chainlinkNetwork *networks_impl.ChainlinkNetwork, state *scenarios.State) error {
+	payment := big.NewInt(requestPaymentJuels)
+	totalPayment := new(big.Int).Mul(payment, big.NewInt(numFulfillments))
+	withdrawable, err := chainlinkNetwork.GetOracleWithdrawable()
+	if err != nil {
+		return stacktrace.Propagate(err, "Error getting the Oracle contract's withdrawable $LINK.")
+	}
+	expectedWithdrawable := new(big.Int).Add(withdrawal.withdrawableBefore, totalPayment)
+	if withdrawable.Cmp(expectedWithdrawable) != 0 {
+		return stacktrace.NewError("Expected %v juels to be withdrawable from the Oracle contract after %v fulfillments of %v juels, but %v are.",
+			expectedWithdrawable, numFulfillments, payment, withdrawable)
+	}
+
+	balances, err := chainlinkNetwork.GetLinkBalances()
+	if err != nil {
+		return stacktrace.Propagate(err, "Error getting $LINK balances.")
+	}
+	expectedConsumerBalance := new(big.Int).Sub(withdrawal.balancesBefore.Consumer, totalPayment)
+	if balances.Consumer.Cmp(expectedConsumerBalance) != 0 {
+		return stacktrace.NewError("Expected the consumer to have %v juels after paying for %v requests, but it has %v.",
+			expectedConsumerBalance, numFulfillments, balances.Consumer)
+	}
+	expectedOracleContractBalance := new(big.Int).Add(withdrawal.balancesBefore.OracleContract, totalPayment)
+	if balances.OracleContract.Cmp(expectedOracleContractBalance) != 0 {
+		return stacktrace.NewError("Expected the Oracle contract to hold %v juels after %v requests were paid for, but it holds %v.",
+			expectedOracleContractBalance, numFulfillments, balances.OracleContract)
+	}
+
+	withdrawal.recipientAddress = ""
+	for address := range balances.OracleNodeKeys {
+		withdrawal.recipientAddress = address
+		break
+	}
+	if withdrawal.recipientAddress == "" {
+		return stacktrace.NewError("The Oracle node reported no keys to withdraw to.")
+	}
+	withdrawal.balancesAfterFulfillments = balances
+	withdrawal.withdrawable = withdrawable
+	return nil
+}
+
+func (withdrawal *oracleWithdrawal) overdraw(chainlinkNetwork *networks_impl.ChainlinkNetwork, state *scenarios.State) error {
+	receipt, err := chainlinkNetwork.WithdrawFromOracle(withdrawal.recipientAddress, new(big.Int).Add(withdrawal.withdrawable, big.NewInt(1)))
+	if err != nil {
+		return stacktrace.Propagate(err, "Error withdrawing more than is withdrawable from the Oracle contract.")
+	}
+	if receipt.Status != geth.TransactionRevertedStatus {
+		return stacktrace.NewError("Expected withdrawing more than the withdrawable %v juels to revert, but transaction %v succeeded.",
+			withdrawal.withdrawable, receipt.TransactionHash)
+	}
+	return nil
+}
+
+/*
+	Withdraws everything withdrawable to the recipient, checking that it comes out of the Oracle contract and that the
+	Oracle node sees its key's balance go up.
+ */
+func (withdrawal *oracleWithdrawal) withdraw(chainlinkNetwork *networks_impl.ChainlinkNetwork, state *scenarios.State) error {
+	receipt, err := chainlinkNetwork.WithdrawFromOracle(withdrawal.recipientAddress, withdrawal.withdrawable)
+	if err != nil {
+		return stacktrace.Propagate(err, "Error withdrawing from the Oracle contract.")
+	}
+	if receipt.Status != geth.TransactionSucceededStatus {
+		return stacktrace.NewError("Withdrawing the withdrawable %v juels reverted in transaction %v.",
+			withdrawal.withdrawable, receipt.TransactionHash)
+	}
+
+	withdrawableAfter, err := chainlinkNetwork.GetOracleWithdrawable()
+	if err != nil {
+		return stacktrace.Propagate(err, "Error getting the Oracle contract's withdrawable $LINK.")
+	}
+	if withdrawableAfter.Sign() != 0 {
+		return stacktrace.NewError("Expected nothing to be withdrawable after withdrawing everything, but %v juels are.", withdrawableAfter)
+	}
+
+	balances, err := chainlinkNetwork.GetLinkBalances()
+	if err != nil {
+		return stacktrace.Propagate(err, "Error getting $LINK balances.")
+	}
+	expectedOracleContractBalance := new(big.Int).Sub(withdrawal.balancesAfterFulfillments.OracleContract, withdrawal.withdrawable)
+	if balances.OracleContract.Cmp(expectedOracleContractBalance) != 0 {
+		return stacktrace.NewError("Expected the Oracle contract to hold %v juels after the withdrawal, but it holds %v.",
+			expectedOracleContractBalance, balances.OracleContract)
+	}
+	recipientBalance, found := balances.OracleNodeKeys[withdrawal.recipientAddress]
+	expectedRecipientBalance := new(big.Int).Add(withdrawal.balancesAfterFulfillments.OracleNodeKeys[withdrawal.recipientAddress], withdrawal.withdrawable)
+	if !found || recipientBalance.Cmp(expectedRecipientBalance) != 0 {
+		return stacktrace.NewError("Expected the Oracle node to report %v juels for key %v after the withdrawal, but it reported %v.",
+			expectedRecipientBalance, withdrawal.recipientAddress, recipientBalance)
+	}
+	return nil
+}
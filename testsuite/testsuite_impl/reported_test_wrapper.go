@@ -0,0 +1,116 @@
+package testsuite_impl
+
+import (
+	"github.com/kurtosis-tech/kurtosis-libs/golang/lib/networks"
+	"github.com/kurtosis-tech/kurtosis-libs/golang/lib/testsuite"
+	"github.com/kurtosistech/chainlink-testing/testsuite/networks_impl"
+	"github.com/kurtosistech/chainlink-testing/testsuite/services_impl/geth"
+	"github.com/kurtosistech/chainlink-testing/testsuite/test_reporting"
+	"github.com/palantir/stacktrace"
+	"github.com/sirupsen/logrus"
+	"os"
+	"path"
+	"time"
+)
+
+const (
+	testReportsDirname = "test-reports"
+	testReportsDirPerms = 0755
+
+	setupStepName = "setup"
+)
+
+/*
+	Wraps a test so that its setup, its result and any steps it runs through ChainlinkNetwork.RunTestStep are written
+	as JSON and JUnit XML reports to the suite execution volume once the test is done, whether it passed or not.
+ */
+type reportedTest struct {
+	testName string
+	test testsuite.Test
+	// Created by Setup; Kurtosis runs each test's setup and run in a testsuite container of its own
+	report *test_reporting.TestReport
+}
+
+func newReportedTest(testName string, test testsuite.Test) *reportedTest {
+	return &reportedTest{
+		testName: testName,
+		test: test,
+	}
+}
+
+func (test *reportedTest) Setup(networkCtx *networks.NetworkContext) (networks.Network, error) {
+	test.report = test_reporting.NewTestReport(test.testName)
+	var network networks.Network
+	err := test.report.RunStep(setupStepName, func() error {
+		var setupErr error
+		network, setupErr = test.test.Setup(networkCtx)
+		return setupErr
+	})
+	if err != nil {
+		test.finishReport(err)
+		return nil, err
+	}
+	if chainlinkNetwork, isChainlinkNetwork := network.(*networks_impl.ChainlinkNetwork); isChainlinkNetwork {
+		chainlinkNetwork.SetTestReport(test.report)
+	}
+	return network, nil
+}
+
+func (test *reportedTest) Run(network networks.Network, testCtx testsuite.TestContext) {
+	// Kurtosis fails tests by panicking, so the report gets written on the way out either way
+	defer func() {
+		panicValue := recover()
+		test.finishReport(panicValue)
+		if panicValue != nil {
+			panic(panicValue)
+		}
+	}()
+	test.test.Run(network, testCtx)
+}
+
+func (test *reportedTest) GetTestConfiguration() testsuite.TestConfiguration {
+	return test.test.GetTestConfiguration()
+}
+
+func (test *reportedTest) GetExecutionTimeout() time.Duration {
+	return test.test.GetExecutionTimeout()
+}
+
+func (test *reportedTest) GetSetupTimeout() time.Duration {
+	return test.test.GetSetupTimeout()
+}
+
+// ==========================================================================================
+//								Helper methods
+// ==========================================================================================
+
+/*
+	Marks the report as finished and writes it out. Reporting is best-effort, so errors are logged rather than failing
+	the test.
+ */
+func (test *reportedTest) finishReport(failure interface{}) {
+	if test.report == nil {
+		test.report = test_reporting.NewTestReport(test.testName)
+	}
+	test.report.Finish(failure)
+	if err := writeTestReport(test.report); err != nil {
+		logrus.Errorf("An error occurred writing the report of test %v: %v", test.testName, err)
+	}
+}
+
+func writeTestReport(report *test_reporting.TestReport) error {
+	testReportsDirpath := path.Join(geth.SuiteExecutionVolumeMountpoint, testReportsDirname)
+	if err := os.MkdirAll(testReportsDirpath, testReportsDirPerms); err != nil {
+		return stacktrace.Propagate(err, "An error occurred creating test reports directory '%v'", testReportsDirpath)
+	}
+	jsonFilepath := path.Join(testReportsDirpath, report.TestName + ".json")
+	if err := report.WriteJson(jsonFilepath); err != nil {
+		return stacktrace.Propagate(err, "An error occurred writing the JSON report")
+	}
+	junitFilepath := path.Join(testReportsDirpath, report.TestName + ".xml")
+	if err := report.WriteJunitXml(junitFilepath); err != nil {
+		return stacktrace.Propagate(err, "An error occurred writing the JUnit report")
+	}
+	logrus.Infof("Report of test %v written to %v and %v", report.TestName, jsonFilepath, junitFilepath)
+	return nil
+}
@@ -0,0 +1,211 @@
+package request_cancellation_test
+
+import (
+	"github.com/kurtosis-tech/kurtosis-libs/golang/lib/networks"
+	"github.com/kurtosis-tech/kurtosis-libs/golang/lib/testsuite"
+	"github.com/kurtosistech/chainlink-testing/testsuite/networks_impl"
+	"github.com/kurtosistech/chainlink-testing/testsuite/scenarios"
+	"github.com/kurtosistech/chainlink-testing/testsuite/services_impl/chainlink_oracle"
+	"github.com/kurtosistech/chainlink-testing/testsuite/services_impl/geth"
+	"github.com/kurtosistech/chainlink-testing/testsuite/testsuite_impl/test_base"
+	"github.com/palantir/stacktrace"
+	"github.com/sirupsen/logrus"
+	"math/big"
+)
+
+const (
+	makeFailingRequestStepName = "make a request the Oracle fails"
+	cancelBeforeExpiryStepName = "cancel the request before it expires"
+	waitForExpiryStepName = "wait for the request to expire"
+	cancelAfterExpiryStepName = "cancel the expired request"
+	fulfillCancelledRequestStepName = "fulfill the cancelled request"
+	makeControlRequestStepName = "make a request the Oracle fails that isn't cancelled"
+	fulfillControlRequestStepName = "fulfill the request that wasn't cancelled"
+
+	// 1 $LINK, in juels
+	requestPaymentJuels = "1000000000000000000"
+
+	// Value the test tries to fulfill the cancelled request, and the request that wasn't cancelled, with
+	lateFulfillmentValue = 42
+)
+
+type RequestCancellationTest struct {
+	test_base.ChainlinkTestBase
+}
+
+func NewRequestCancellationTest(base test_base.ChainlinkTestBase) *RequestCancellationTest {
+	return &RequestCancellationTest{
+		ChainlinkTestBase: base,
+	}
+}
+
+func (test *RequestCancellationTest) Run(network networks.Network, testCtx testsuite.TestContext) {
+	cancellation := &requestCancellation{}
+	test.RunScenario(network, testCtx,
+		scenarios.ConnectGethPeersStep(),
+		scenarios.DeployContractsStep(),
+		scenarios.FundLinkWalletStep(),
+		scenarios.StartOracleStep(),
+		scenarios.FundOracleEthAccountsStep(),
+		scenarios.DeployPriceFeedJobStep(),
+		// The Oracle can't get the price, so its run errors without fulfilling the request
+		scenarios.SetPriceFeedFailingStep(true),
+		scenarios.NewStep(makeFailingRequestStepName, cancellation.makeFailingRequest),
+		scenarios.NewStep(cancelBeforeExpiryStepName, cancellation.cancelBeforeExpiry),
+		scenarios.NewStep(waitForExpiryStepName, cancellation.waitForExpiry),
+		scenarios.NewStep(cancelAfterExpiryStepName, cancellation.cancelAfterExpiry),
+		scenarios.NewStep(fulfillCancelledRequestStepName, cancellation.fulfillCancelledRequest),
+		// The same fulfillment of a request that wasn't cancelled has to go through, or the revert above proves nothing
+		scenarios.NewStep(makeControlRequestStepName, cancellation.makeControlRequest),
+		scenarios.NewStep(fulfillControlRequestStepName, cancellation.fulfillControlRequest))
+}
+
+// ==========================================================================================
+//								Helper methods
+// ==========================================================================================
+
+/*
+	The requests one run of the test makes, handed on from the steps that make them to the steps after: the one it
+	cancels, and the control one it leaves alone.
+ */
+type requestCancellation struct {
+	request networks_impl.OracleRequest
+	controlRequest networks_impl.OracleRequest
+}
+
+func (cancellation *requestCancellation) makeFailingRequest(chainlinkNetwork *networks_impl.ChainlinkNetwork, state *scenarios.State) error {
+	request, err := makeRequestAndWaitForFailedRun(chainlinkNetwork)
+	if err != nil {
+		return stacktrace.Propagate(err, "Error making the request to cancel.")
+	}
+	cancellation.request = request
+	return nil
+}
+
+func (cancellation *requestCancellation) cancelBeforeExpiry(chainlinkNetwork *networks_impl.ChainlinkNetwork, state *scenarios.State) error {
+	request := cancellation.request
+	receipt, err := chainlinkNetwork.CancelRequest(request)
+	if err != nil {
+		return stacktrace.Propagate(err, "Error cancelling request %v before it expired.", request.RequestId)
+	}
+	if receipt.Status != geth.TransactionRevertedStatus {
+		return stacktrace.NewError("Expected cancelling request %v before it expired to revert, but transaction %v succeeded.",
+			request.RequestId, receipt.TransactionHash)
+	}
+	return nil
+}
+
+func (cancellation *requestCancellation) waitForExpiry(chainlinkNetwork *networks_impl.ChainlinkNetwork, state *scenarios.State) error {
+	request := cancellation.request
+	if err := chainlinkNetwork.WaitForBlockTimestamp(request.CancelExpiration); err != nil {
+		return stacktrace.Propagate(err, "Error waiting for request %v to expire.", request.RequestId)
+	}
+	return nil
+}
+
+/*
+	Cancels the expired request, checking that its payment goes from the Oracle contract back to the requester.
+ */
+func (cancellation *requestCancellation) cancelAfterExpiry(chainlinkNetwork *networks_impl.ChainlinkNetwork, state *scenarios.State) error {
+	request := cancellation.request
+	requesterBalanceBefore, err := chainlinkNetwork.GetLinkBalance(request.Requester)
+	if err != nil {
+		return stacktrace.Propagate(err, "Error getting the $LINK balance of requester %v.", request.Requester)
+	}
+	oracleBalanceBefore, err := chainlinkNetwork.GetLinkBalance(state.OracleContractAddress)
+	if err != nil {
+		return stacktrace.Propagate(err, "Error getting the $LINK balance of the Oracle contract.")
+	}
+
+	receipt, err := chainlinkNetwork.CancelRequest(request)
+	if err != nil {
+		return stacktrace.Propagate(err, "Error cancelling request %v.", request.RequestId)
+	}
+	if receipt.Status != geth.TransactionSucceededStatus {
+		return stacktrace.NewError("Cancelling expired request %v reverted in transaction %v.", request.RequestId, receipt.TransactionHash)
+	}
+
+	requesterBalanceAfter, err := chainlinkNetwork.GetLinkBalance(request.Requester)
+	if err != nil {
+		return stacktrace.Propagate(err, "Error getting the $LINK balance of requester %v.", request.Requester)
+	}
+	oracleBalanceAfter, err := chainlinkNetwork.GetLinkBalance(state.OracleContractAddress)
+	if err != nil {
+		return stacktrace.Propagate(err, "Error getting the $LINK balance of the Oracle contract.")
+	}
+	expectedRequesterBalance := new(big.Int).Add(requesterBalanceBefore, request.Payment)
+	if requesterBalanceAfter.Cmp(expectedRequesterBalance) != 0 {
+		return stacktrace.NewError("Expected cancelling to refund the requester's %v juels, taking its balance from %v to %v, but it's %v.",
+			request.Payment, requesterBalanceBefore, expectedRequesterBalance, requesterBalanceAfter)
+	}
+	expectedOracleBalance := new(big.Int).Sub(oracleBalanceBefore, request.Payment)
+	if oracleBalanceAfter.Cmp(expectedOracleBalance) != 0 {
+		return stacktrace.NewError("Expected the refund to come out of the Oracle contract, taking its balance from %v to %v, but it's %v.",
+			oracleBalanceBefore, expectedOracleBalance, oracleBalanceAfter)
+	}
+	return nil
+}
+
+func (cancellation *requestCancellation) makeControlRequest(chainlinkNetwork *networks_impl.ChainlinkNetwork, state *scenarios.State) error {
+	request, err := makeRequestAndWaitForFailedRun(chainlinkNetwork)
+	if err != nil {
+		return stacktrace.Propagate(err, "Error making the control request.")
+	}
+	cancellation.controlRequest = request
+	return nil
+}
+
+func (cancellation *requestCancellation) fulfillControlRequest(chainlinkNetwork *networks_impl.ChainlinkNetwork, state *scenarios.State) error {
+	request := cancellation.controlRequest
+	receipt, err := chainlinkNetwork.FulfillRequest(request, lateFulfillmentValue)
+	if err != nil {
+		return stacktrace.Propagate(err, "Error fulfilling control request %v.", request.RequestId)
+	}
+	if receipt.Status != geth.TransactionSucceededStatus {
+		return stacktrace.NewError("Expected fulfilling control request %v, which wasn't cancelled, to succeed, but transaction %v reverted.",
+			request.RequestId, receipt.TransactionHash)
+	}
+	return nil
+}
+
+func (cancellation *requestCancellation) fulfillCancelledRequest(chainlinkNetwork *networks_impl.ChainlinkNetwork, state *scenarios.State) error {
+	request := cancellation.request
+	receipt, err := chainlinkNetwork.FulfillRequest(request, lateFulfillmentValue)
+	if err != nil {
+		return stacktrace.Propagate(err, "Error fulfilling cancelled request %v.", request.RequestId)
+	}
+	if receipt.Status != geth.TransactionRevertedStatus {
+		return stacktrace.NewError("Expected fulfilling cancelled request %v to revert, but transaction %v succeeded.",
+			request.RequestId, receipt.TransactionHash)
+	}
+	return nil
+}
+
+/*
+	Has the consumer make a request, which the Oracle can't fulfill while the price feed fails, waiting for the
+	Oracle's run of it to error so nothing but the test touches the request afterwards.
+ */
+func makeRequestAndWaitForFailedRun(chainlinkNetwork *networks_impl.ChainlinkNetwork) (networks_impl.OracleRequest, error) {
+	txHash, isReverted, err := chainlinkNetwork.SendDataRequestWithPayment("", requestPaymentJuels)
+	if err != nil {
+		return networks_impl.OracleRequest{}, stacktrace.Propagate(err, "Error requesting data.")
+	}
+	if isReverted {
+		return networks_impl.OracleRequest{}, stacktrace.NewError("The consumer's request paying %v juels was rejected on-chain.", requestPaymentJuels)
+	}
+	request, err := chainlinkNetwork.GetOracleRequest(txHash)
+	if err != nil {
+		return networks_impl.OracleRequest{}, stacktrace.Propagate(err, "Error getting the Oracle request made in transaction %v.", txHash)
+	}
+	logrus.Infof("Made request %v, which can be cancelled from Unix time %v.", request.RequestId, request.CancelExpiration)
+
+	run, err := chainlinkNetwork.WaitForRequestRunToFinish(txHash)
+	if err != nil {
+		return networks_impl.OracleRequest{}, stacktrace.Propagate(err, "Error waiting for the run of request %v.", request.RequestId)
+	}
+	if run.Attributes.Status != chainlink_oracle.RunStatusErrored {
+		return networks_impl.OracleRequest{}, stacktrace.NewError("Expected the run of request %v to error while the price feed fails, but it ended with status '%v'.",
+			request.RequestId, run.Attributes.Status)
+	}
+	return request, nil
+}
@@ -0,0 +1,117 @@
+package test_base
+
+import (
+	"github.com/kurtosis-tech/kurtosis-libs/golang/lib/networks"
+	"github.com/kurtosis-tech/kurtosis-libs/golang/lib/services"
+	"github.com/kurtosis-tech/kurtosis-libs/golang/lib/testsuite"
+	"github.com/kurtosistech/chainlink-testing/testsuite/networks_impl"
+	"github.com/kurtosistech/chainlink-testing/testsuite/scenarios"
+	"github.com/palantir/stacktrace"
+	"time"
+)
+
+const (
+	GethDataDirArtifactId  services.FilesArtifactID = "geth-data-dir"
+	gethDataDirArtifactUrl                          = "https://kurtosis-public-access.s3.amazonaws.com/client-artifacts/chainlink/geth-data-dir.tgz"
+
+	setupTimeout = 30000 * time.Second
+	executionTimeout = 30000 * time.Second
+)
+
+/*
+	The name and the images and topology a test builds its ChainlinkNetwork from. Tests embed it for the standard
+	Setup, test configuration and timeouts, so they only define their Run.
+ */
+type ChainlinkTestBase struct {
+	// Name the testsuite registers the test under, which labels its scenario and failure artifacts
+	TestName string
+	GethServiceImage string
+	ChainlinkContractDeployerImage string
+	ChainlinkOracleImage string
+	PostgresImage string
+	PriceFeedServerImage string
+	Topology networks_impl.NetworkTopology
+}
+
+func NewChainlinkTestBase(testName string, gethServiceImage string, chainlinkContractDeployerImage string,
+	chainlinkOracleImage string, postgresImage string, priceFeedServerImage string,
+	topology networks_impl.NetworkTopology) ChainlinkTestBase {
+	return ChainlinkTestBase{
+		TestName: testName,
+		GethServiceImage: gethServiceImage,
+		ChainlinkContractDeployerImage: chainlinkContractDeployerImage,
+		ChainlinkOracleImage: chainlinkOracleImage,
+		PostgresImage: postgresImage,
+		PriceFeedServerImage: priceFeedServerImage,
+		Topology: topology,
+	}
+}
+
+/*
+	Starts the network's base services, leaving contracts, Oracles and jobs to the test's Run.
+ */
+func (base ChainlinkTestBase) Setup(networkCtx *networks.NetworkContext) (networks.Network, error) {
+	chainlinkNetwork, err := base.SetupNetwork(networkCtx, base.ChainlinkOracleImage)
+	if err != nil {
+		return nil, err
+	}
+	return chainlinkNetwork, nil
+}
+
+/*
+	Like Setup, but the network's Oracles are started on the given image, and the network is returned as is for tests
+	that add to their setup.
+ */
+func (base ChainlinkTestBase) SetupNetwork(networkCtx *networks.NetworkContext, chainlinkOracleImage string) (*networks_impl.ChainlinkNetwork, error) {
+	// The testsuite validates topologies before tests adjust them, so they're checked again here
+	if err := base.Topology.Validate(); err != nil {
+		return nil, stacktrace.Propagate(err, "The test's network topology is invalid.")
+	}
+	chainlinkNetwork := networks_impl.NewChainlinkNetwork(networkCtx,
+		GethDataDirArtifactId,
+		base.GethServiceImage,
+		base.ChainlinkContractDeployerImage,
+		base.PostgresImage,
+		chainlinkOracleImage,
+		base.PriceFeedServerImage,
+		base.Topology)
+
+	err := chainlinkNetwork.AddBaseServices()
+	if err != nil {
+		return nil, stacktrace.Propagate(err, "Error adding the base services to the network.")
+	}
+
+	return chainlinkNetwork, nil
+}
+
+func (base ChainlinkTestBase) GetTestConfiguration() testsuite.TestConfiguration {
+	return testsuite.TestConfiguration{
+		FilesArtifactUrls: map[services.FilesArtifactID]string{
+			GethDataDirArtifactId: gethDataDirArtifactUrl,
+		},
+	}
+}
+
+func (base ChainlinkTestBase) GetExecutionTimeout() time.Duration {
+	return executionTimeout
+}
+
+func (base ChainlinkTestBase) GetSetupTimeout() time.Duration {
+	return setupTimeout
+}
+
+/*
+	Runs the steps as a scenario named after the test, failing the test at the first step that fails and dumping the
+	network's failure artifacts if it does.
+ */
+func (base ChainlinkTestBase) RunScenario(network networks.Network, testCtx testsuite.TestContext, steps ...scenarios.Step) {
+	// Necessary because Go doesn't have generics
+	chainlinkNetwork := network.(*networks_impl.ChainlinkNetwork)
+	defer chainlinkNetwork.DumpArtifactsOnFailure(base.TestName)
+
+	// Each step is logged, timed and recorded in the test's report, so dashboards show which one failed
+	scenario := scenarios.NewScenario(base.TestName, chainlinkNetwork).AddSteps(steps...)
+	if err := scenario.Run(); err != nil {
+		testCtx.Fatal(stacktrace.Propagate(err, "Scenario '%v' failed.", base.TestName))
+	}
+}
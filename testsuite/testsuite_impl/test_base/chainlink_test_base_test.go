@@ -0,0 +1,106 @@
+package test_base
+
+import (
+	"github.com/kurtosis-tech/kurtosis-libs/golang/lib/testsuite"
+	"github.com/kurtosistech/chainlink-testing/testsuite/networks_impl"
+	"github.com/kurtosistech/chainlink-testing/testsuite/scenarios"
+	"github.com/kurtosistech/chainlink-testing/testsuite/services_impl/geth"
+	"github.com/kurtosistech/chainlink-testing/testsuite/test_reporting"
+	"reflect"
+	"testing"
+)
+
+func TestSetupRejectsInvalidTopology(t *testing.T) {
+	testCases := []struct {
+		name string
+		topology networks_impl.NetworkTopology
+	}{
+		{
+			name: "no geth nodes",
+			topology: func() networks_impl.NetworkTopology {
+				topology := networks_impl.NewDefaultNetworkTopology()
+				topology.NumGethNodes = 0
+				return topology
+			}(),
+		},
+		{
+			name: "no oracles",
+			topology: func() networks_impl.NetworkTopology {
+				topology := networks_impl.NewDefaultNetworkTopology()
+				topology.NumOracles = 0
+				return topology
+			}(),
+		},
+		{
+			// Tests adjust their topologies after the testsuite has validated them
+			name: "imported account that's also a signer",
+			topology: networks_impl.NewDefaultNetworkTopology().WithOracleImportedAccount(geth.SignerCandidateAddresses[0]),
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			base := NewChainlinkTestBase("testName", "geth", "deployer", "oracle", "postgres", "price-feed", testCase.topology)
+			// The topology is checked before the network context is used, so none is needed
+			network, err := base.Setup(nil)
+			if err == nil {
+				t.Fatalf("Expected setup to reject the topology, but it returned network %v", network)
+			}
+			if network != nil {
+				t.Fatalf("Expected no network when setup fails, but got %v", network)
+			}
+		})
+	}
+}
+
+func TestGetTestConfiguration(t *testing.T) {
+	base := NewChainlinkTestBase("testName", "geth", "deployer", "oracle", "postgres", "price-feed", networks_impl.NewDefaultNetworkTopology())
+	configuration := base.GetTestConfiguration()
+	if url, found := configuration.FilesArtifactUrls[GethDataDirArtifactId]; !found || url != gethDataDirArtifactUrl {
+		t.Fatalf("Expected the geth data dir artifact %v at %v, but got artifacts %v", GethDataDirArtifactId, gethDataDirArtifactUrl, configuration.FilesArtifactUrls)
+	}
+}
+
+func TestRunScenarioRunsStepsInOrder(t *testing.T) {
+	network := networks_impl.NewChainlinkNetwork(nil, GethDataDirArtifactId, "geth", "deployer", "postgres", "oracle",
+		"price-feed", networks_impl.NewDefaultNetworkTopology())
+	ranSteps := []string{}
+	newRecordingStep := func(name string) scenarios.Step {
+		return scenarios.NewStep(name, func(network *networks_impl.ChainlinkNetwork, state *scenarios.State) error {
+			ranSteps = append(ranSteps, name)
+			return nil
+		})
+	}
+
+	base := NewChainlinkTestBase("testName", "geth", "deployer", "oracle", "postgres", "price-feed", networks_impl.NewDefaultNetworkTopology())
+	base.RunScenario(network, testsuite.TestContext{}, newRecordingStep("first"), newRecordingStep("second"))
+
+	expectedSteps := []string{"first", "second"}
+	if !reflect.DeepEqual(ranSteps, expectedSteps) {
+		t.Fatalf("Expected steps %v to run, but got %v", expectedSteps, ranSteps)
+	}
+}
+
+func TestRunScenarioRecordsStepsInTestReport(t *testing.T) {
+	network := networks_impl.NewChainlinkNetwork(nil, GethDataDirArtifactId, "geth", "deployer", "postgres", "oracle",
+		"price-feed", networks_impl.NewDefaultNetworkTopology())
+	report := test_reporting.NewTestReport("testName")
+	network.SetTestReport(report)
+	noOp := func(network *networks_impl.ChainlinkNetwork, state *scenarios.State) error {
+		return nil
+	}
+
+	base := NewChainlinkTestBase("testName", "geth", "deployer", "oracle", "postgres", "price-feed", networks_impl.NewDefaultNetworkTopology())
+	base.RunScenario(network, testsuite.TestContext{}, scenarios.NewStep("first", noOp), scenarios.NewStep("second", noOp))
+
+	reportedSteps := []string{}
+	for _, step := range report.Steps {
+		if step.Status != test_reporting.PassedStatus {
+			t.Fatalf("Expected step '%v' to be reported as %v, but it was reported as %v", step.Name, test_reporting.PassedStatus, step.Status)
+		}
+		reportedSteps = append(reportedSteps, step.Name)
+	}
+	expectedSteps := []string{"first", "second"}
+	if !reflect.DeepEqual(reportedSteps, expectedSteps) {
+		t.Fatalf("Expected steps %v in the test report, but got %v", expectedSteps, reportedSteps)
+	}
+}
@@ -0,0 +1,63 @@
+package testsuite_impl
+
+import (
+	"github.com/palantir/stacktrace"
+	"regexp"
+	"strings"
+)
+
+// Characters that can't go in a test name, which version labels are made part of
+var versionLabelDisallowedCharsRegex = regexp.MustCompile("[^A-Za-z0-9.-]")
+
+/*
+	One geth image and one oracle image the testsuite runs every test against.
+ */
+type VersionCombination struct {
+	GethServiceImage string
+	ChainlinkOracleImage string
+}
+
+/*
+	Every pairing of the given geth and oracle images, in the order the images are given with the geth image varying
+	slowest.
+ */
+func NewVersionMatrix(gethServiceImages []string, chainlinkOracleImages []string) ([]VersionCombination, error) {
+	if len(gethServiceImages) == 0 || len(chainlinkOracleImages) == 0 {
+		return nil, stacktrace.NewError("A version matrix needs at least one geth image and one oracle image")
+	}
+	combinations := []VersionCombination{}
+	combinationsByLabel := map[string]VersionCombination{}
+	for _, gethServiceImage := range gethServiceImages {
+		for _, chainlinkOracleImage := range chainlinkOracleImages {
+			combination := VersionCombination{
+				GethServiceImage:     gethServiceImage,
+				ChainlinkOracleImage: chainlinkOracleImage,
+			}
+			// Tests are named after their labels, so two combinations with the same label would clobber each other
+			label := combination.GetLabel()
+			if existing, found := combinationsByLabel[label]; found {
+				return nil, stacktrace.NewError("Version combinations %+v and %+v would both be labelled '%v'", existing, combination, label)
+			}
+			combinationsByLabel[label] = combination
+			combinations = append(combinations, combination)
+		}
+	}
+	return combinations, nil
+}
+
+/*
+	A short name for the combination that's safe to use in test names and file paths, made from the last path segment
+	of each image (e.g. "chainlink-0.10.2_client-go-latest").
+ */
+func (combination VersionCombination) GetLabel() string {
+	return getImageLabel(combination.ChainlinkOracleImage) + "_" + getImageLabel(combination.GethServiceImage)
+}
+
+// ==========================================================================================
+//								Helper methods
+// ==========================================================================================
+
+func getImageLabel(image string) string {
+	imageName := image[strings.LastIndex(image, "/") + 1:]
+	return versionLabelDisallowedCharsRegex.ReplaceAllString(imageName, "-")
+}
@@ -0,0 +1,90 @@
+package webhook_job_test
+
+import (
+	"github.com/kurtosis-tech/kurtosis-libs/golang/lib/networks"
+	"github.com/kurtosis-tech/kurtosis-libs/golang/lib/testsuite"
+	"github.com/kurtosistech/chainlink-testing/testsuite/networks_impl"
+	"github.com/kurtosistech/chainlink-testing/testsuite/scenarios"
+	"github.com/kurtosistech/chainlink-testing/testsuite/services_impl/chainlink_oracle"
+	"github.com/kurtosistech/chainlink-testing/testsuite/services_impl/price_feed_server"
+	"github.com/kurtosistech/chainlink-testing/testsuite/testsuite_impl/test_base"
+	"github.com/palantir/stacktrace"
+	"github.com/sirupsen/logrus"
+	"math/big"
+)
+
+const (
+	triggerWebJobStepName = "trigger web initiated job"
+	checkWebRunsStepName = "check web initiated runs"
+
+	numTriggeredRuns = 3
+)
+
+type WebhookJobTest struct {
+	test_base.ChainlinkTestBase
+}
+
+func NewWebhookJobTest(base test_base.ChainlinkTestBase) *WebhookJobTest {
+	return &WebhookJobTest{
+		ChainlinkTestBase: base,
+	}
+}
+
+func (test *WebhookJobTest) Run(network networks.Network, testCtx testsuite.TestContext) {
+	test.RunScenario(network, testCtx,
+		scenarios.ConnectGethPeersStep(),
+		scenarios.DeployContractsStep(),
+		scenarios.DeploySimpleConsumerStep(),
+		scenarios.StartOracleStep(),
+		scenarios.FundOracleEthAccountsStep(),
+		scenarios.AddWebJobStep(),
+		scenarios.NewStep(triggerWebJobStepName, triggerWebJob),
+		scenarios.NewStep(checkWebRunsStepName, checkWebRuns))
+}
+
+// ==========================================================================================
+//								Helper methods
+// ==========================================================================================
+
+func triggerWebJob(chainlinkNetwork *networks_impl.ChainlinkNetwork, state *scenarios.State) error {
+	jobId := state.JobIds[scenarios.WebJobName]
+	for i := 0; i < numTriggeredRuns; i++ {
+		run, err := chainlinkNetwork.RunWebJob(jobId)
+		if err != nil {
+			return stacktrace.Propagate(err, "Error running web initiated job %v.", jobId)
+		}
+		logrus.Infof("Run %v of web initiated job %v completed.", run.Attributes.Id, jobId)
+	}
+	return nil
+}
+
+func checkWebRuns(chainlinkNetwork *networks_impl.ChainlinkNetwork, state *scenarios.State) error {
+	jobId := state.JobIds[scenarios.WebJobName]
+	runs, err := chainlinkNetwork.GetChainlinkOracle().GetRunsForJob(jobId)
+	if err != nil {
+		return stacktrace.Propagate(err, "Error getting the runs of web initiated job %v.", jobId)
+	}
+	if len(runs) != numTriggeredRuns {
+		return stacktrace.NewError("Expected web initiated job %v to have run once per trigger (%v times), but it ran %v times.",
+			jobId, numTriggeredRuns, len(runs))
+	}
+	for _, run := range runs {
+		if run.Attributes.Initiator.Type != chainlink_oracle.WebInitiatorType {
+			return stacktrace.NewError("Expected run %v to be started by the web initiator, but it was started by '%v'.",
+				run.Attributes.Id, run.Attributes.Initiator.Type)
+		}
+	}
+
+	value, numWrites, err := chainlinkNetwork.GetSimpleConsumerState()
+	if err != nil {
+		return stacktrace.Propagate(err, "Error reading the SimpleConsumer contract.")
+	}
+	if numWrites.Cmp(big.NewInt(int64(len(runs)))) != 0 {
+		return stacktrace.NewError("Expected one write to the SimpleConsumer per triggered run (%v), but it had %v.", len(runs), numWrites)
+	}
+	expectedValue := chainlink_oracle.GetPriceFeedOnChainValue(price_feed_server.SampleUsdPrice)
+	if value.String() != expectedValue {
+		return stacktrace.NewError("Expected the SimpleConsumer to hold %v, but it held %v.", expectedValue, value)
+	}
+	return nil
+}